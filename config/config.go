@@ -25,6 +25,130 @@ type Config struct {
 	FrontendBypassBrowsers    bool
 	FrontendCustomHeader      string
 	FrontendCustomHeaderValue string
+	Upload                    UploadConfig
+	Download                  DownloadConfig
+	Storage                   StorageConfig
+	Export                    ExportConfig
+	Import                    ImportConfig
+	Migration                 MigrationConfig
+	AcademicRecord            AcademicRecordConfig
+	Advisor                   AdvisorConfig
+	RegistrationEdit          RegistrationEditConfig
+	Purge                     PurgeConfig
+	Retention                 RetentionConfig
+	CursorSigningKey          string
+}
+
+// UploadConfig holds the defaults used to validate uploaded documents, a
+// document requirement can narrow the MIME types or the size per document type
+type UploadConfig struct {
+	MaxSizeBytes     int64
+	AllowedMimeTypes []string
+	Scanner          string
+	ClamAVAddress    string
+	ClamAVTimeout    int64
+	// VersionRetention is how many previous versions of a document are kept
+	// next to the current one, 0 keeps all of them
+	VersionRetention int64
+}
+
+// DownloadConfig holds the settings of the document download proxy and the
+// signed links handed out in notifications. Signed links are disabled until a
+// dedicated SigningKey is set, anyone knowing the key can forge them.
+type DownloadConfig struct {
+	SigningKey     string
+	LinkTTLSeconds int64
+	PublicBaseURL  string
+	MaxSizeBytes   int64
+}
+
+// StorageConfig selects where documents are stored, Driver is one of gcs, s3
+// or local. gcs goes through the SIM-MBKM file storage service.
+type StorageConfig struct {
+	Driver string
+
+	GCSProjectID   string
+	GCSBucket      string
+	GCSDownloadURL string
+
+	S3Endpoint     string
+	S3Region       string
+	S3Bucket       string
+	S3AccessKey    string
+	S3SecretKey    string
+	S3UsePathStyle bool
+	S3ObjectPrefix string
+
+	LocalDirectory string
+}
+
+// ExportConfig holds the limits of the CSV and XLSX registration exports
+type ExportConfig struct {
+	MaxRows int64
+}
+
+// ImportConfig holds the limits of the LO-MBKM bulk registration import
+type ImportConfig struct {
+	MaxRows      int64
+	MaxSizeBytes int64
+}
+
+// AcademicRecordConfig selects where the official semester and SKS of a
+// student come from. Client is none, which skips the check, or local. Mode is
+// off, check to only flag registrations whose typed in values differ, or
+// overwrite to replace them with the record values.
+type AcademicRecordConfig struct {
+	Client   string
+	Mode     string
+	StubFile string
+}
+
+// AdvisorConfig controls how the academic advisor of a registration is
+// checked. Mode is off, validate to check the typed in advisor against user
+// management, or auto_fill to take the advisor assigned to the student.
+type AdvisorConfig struct {
+	Mode string
+}
+
+// RegistrationEditConfig controls what happens when a material field of an
+// approved registration changes. With ReopenApprovals the approvals go back
+// to PENDING and the student may make such changes, without it only LO-MBKM
+// and admins can and the approvals stay.
+type RegistrationEditConfig struct {
+	ReopenApprovals bool
+}
+
+// PurgeConfig controls the job that removes deleted registrations for good.
+// A deleted registration stays in the trash, documents included, for
+// RetentionDays before the job purges it, the job runs every IntervalMinutes.
+// The job is off until Enabled is set.
+type PurgeConfig struct {
+	Enabled         bool
+	RetentionDays   int64
+	IntervalMinutes int64
+	BatchSize       int64
+}
+
+// RetentionConfig controls the job that anonymizes old registrations. A
+// registration is due RetentionYears after its academic period ended, or
+// after it was created when it has none. Its personal fields are replaced by
+// pseudonyms derived with PseudonymKey and its documents are purged. With
+// DryRun the job only logs what it would anonymize. Nothing is anonymized
+// without a dedicated PseudonymKey, and it has to stay the same for the
+// pseudonyms to stay stable.
+type RetentionConfig struct {
+	Enabled         bool
+	DryRun          bool
+	RetentionYears  int64
+	IntervalMinutes int64
+	BatchSize       int64
+	PseudonymKey    string
+}
+
+// MigrationConfig controls whether pending schema migrations are applied
+// when the service starts, they can always be run with the migrate subcommand
+type MigrationConfig struct {
+	OnStartup bool
 }
 
 // LoadConfig loads configuration from environment variables
@@ -47,6 +171,70 @@ func LoadConfig() *Config {
 		FrontendBypassBrowsers:    getEnvAsBool("FRONTEND_BYPASS_BROWSERS", false),
 		FrontendCustomHeader:      getEnv("FRONTEND_CUSTOM_HEADER", "X-Frontend-Request"),
 		FrontendCustomHeaderValue: getEnv("FRONTEND_CUSTOM_HEADER_VALUE", "true"),
+		CursorSigningKey:          getEnv("CURSOR_SIGNING_KEY", ""),
+		Upload: UploadConfig{
+			MaxSizeBytes:     getEnvAsInt64("UPLOAD_MAX_SIZE_BYTES", 5*1024*1024),
+			AllowedMimeTypes: getEnvAsSlice("UPLOAD_ALLOWED_MIME_TYPES", []string{"application/pdf", "image/jpeg", "image/png"}),
+			Scanner:          getEnv("UPLOAD_SCANNER", "none"),
+			ClamAVAddress:    getEnv("CLAMAV_ADDRESS", "tcp://localhost:3310"),
+			ClamAVTimeout:    getEnvAsInt64("CLAMAV_TIMEOUT_SECONDS", 30),
+			VersionRetention: getEnvAsInt64("DOCUMENT_VERSION_RETENTION", 0),
+		},
+		Download: DownloadConfig{
+			SigningKey:     getEnv("DOWNLOAD_SIGNING_KEY", ""),
+			LinkTTLSeconds: getEnvAsInt64("DOWNLOAD_LINK_TTL_SECONDS", 3600),
+			PublicBaseURL:  getEnv("PUBLIC_BASE_URL", ""),
+			MaxSizeBytes:   getEnvAsInt64("DOWNLOAD_MAX_SIZE_BYTES", 50*1024*1024),
+		},
+		Storage: StorageConfig{
+			Driver:         getEnv("STORAGE_DRIVER", "gcs"),
+			GCSProjectID:   getEnv("STORAGE_GCS_PROJECT_ID", "sim_mbkm"),
+			GCSBucket:      getEnv("STORAGE_GCS_BUCKET", ""),
+			GCSDownloadURL: getEnv("FILE_STORAGE_DOWNLOAD_URL", ""),
+			S3Endpoint:     getEnv("STORAGE_S3_ENDPOINT", "https://s3.amazonaws.com"),
+			S3Region:       getEnv("STORAGE_S3_REGION", "us-east-1"),
+			S3Bucket:       getEnv("STORAGE_S3_BUCKET", ""),
+			S3AccessKey:    getEnv("STORAGE_S3_ACCESS_KEY", ""),
+			S3SecretKey:    getEnv("STORAGE_S3_SECRET_KEY", ""),
+			S3UsePathStyle: getEnvAsBool("STORAGE_S3_USE_PATH_STYLE", true),
+			S3ObjectPrefix: getEnv("STORAGE_S3_OBJECT_PREFIX", "registrations/"),
+			LocalDirectory: getEnv("STORAGE_LOCAL_DIRECTORY", "./storage"),
+		},
+		Export: ExportConfig{
+			MaxRows: getEnvAsInt64("EXPORT_MAX_ROWS", 10000),
+		},
+		Import: ImportConfig{
+			MaxRows:      getEnvAsInt64("IMPORT_MAX_ROWS", 1000),
+			MaxSizeBytes: getEnvAsInt64("IMPORT_MAX_SIZE_BYTES", 5*1024*1024),
+		},
+		Migration: MigrationConfig{
+			OnStartup: getEnvAsBool("MIGRATE_ON_STARTUP", true),
+		},
+		AcademicRecord: AcademicRecordConfig{
+			Client:   getEnv("ACADEMIC_RECORD_CLIENT", "none"),
+			Mode:     getEnv("ACADEMIC_RECORD_MODE", "check"),
+			StubFile: getEnv("ACADEMIC_RECORD_STUB_FILE", ""),
+		},
+		Advisor: AdvisorConfig{
+			Mode: getEnv("ADVISOR_MODE", "validate"),
+		},
+		RegistrationEdit: RegistrationEditConfig{
+			ReopenApprovals: getEnvAsBool("REGISTRATION_REOPEN_APPROVALS", false),
+		},
+		Purge: PurgeConfig{
+			Enabled:         getEnvAsBool("REGISTRATION_PURGE_ENABLED", false),
+			RetentionDays:   getEnvAsInt64("REGISTRATION_PURGE_RETENTION_DAYS", 30),
+			IntervalMinutes: getEnvAsInt64("REGISTRATION_PURGE_INTERVAL_MINUTES", 60),
+			BatchSize:       getEnvAsInt64("REGISTRATION_PURGE_BATCH_SIZE", 100),
+		},
+		Retention: RetentionConfig{
+			Enabled:         getEnvAsBool("RETENTION_ENABLED", false),
+			DryRun:          getEnvAsBool("RETENTION_DRY_RUN", false),
+			RetentionYears:  getEnvAsInt64("RETENTION_YEARS", 5),
+			IntervalMinutes: getEnvAsInt64("RETENTION_INTERVAL_MINUTES", 1440),
+			BatchSize:       getEnvAsInt64("RETENTION_BATCH_SIZE", 100),
+			PseudonymKey:    getEnv("RETENTION_PSEUDONYM_KEY", ""),
+		},
 	}
 }
 
@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"net/http"
+	"registration-service/dto"
+	"registration-service/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type academicPeriodController struct {
+	academicPeriodService service.AcademicPeriodService
+}
+
+type AcademicPeriodController interface {
+	GetAllAcademicPeriods(ctx *gin.Context)
+	GetAcademicPeriodByID(ctx *gin.Context)
+	CreateAcademicPeriod(ctx *gin.Context)
+	UpdateAcademicPeriod(ctx *gin.Context)
+	DeleteAcademicPeriod(ctx *gin.Context)
+}
+
+func NewAcademicPeriodController(academicPeriodService service.AcademicPeriodService) AcademicPeriodController {
+	return &academicPeriodController{academicPeriodService: academicPeriodService}
+}
+
+func (c *academicPeriodController) GetAllAcademicPeriods(ctx *gin.Context) {
+	var filter dto.FilterAcademicPeriodRequest
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	periods, err := c.academicPeriodService.FindAllAcademicPeriods(ctx, filter, nil)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.Response{
+		Message: dto.MESSAGE_ACADEMIC_PERIOD_GET_ALL_SUCCESS,
+		Status:  dto.STATUS_SUCCESS,
+		Data:    periods,
+	})
+}
+
+func (c *academicPeriodController) GetAcademicPeriodByID(ctx *gin.Context) {
+	id := ctx.Param("id")
+	period, err := c.academicPeriodService.FindAcademicPeriodByID(ctx, id, nil)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.Response{
+		Message: dto.MESSAGE_ACADEMIC_PERIOD_GET_SUCCESS,
+		Status:  dto.STATUS_SUCCESS,
+		Data:    period,
+	})
+}
+
+func (c *academicPeriodController) CreateAcademicPeriod(ctx *gin.Context) {
+	var request dto.AcademicPeriodRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err := c.academicPeriodService.CreateAcademicPeriod(ctx, request, nil)
+	if err != nil {
+		ctx.AbortWithStatusJSON(errorStatus(err), dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dto.Response{
+		Message: dto.MESSAGE_ACADEMIC_PERIOD_CREATE_SUCCESS,
+		Status:  dto.STATUS_SUCCESS,
+	})
+}
+
+func (c *academicPeriodController) UpdateAcademicPeriod(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	var request dto.AcademicPeriodRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err := c.academicPeriodService.UpdateAcademicPeriod(ctx, id, request, nil)
+	if err != nil {
+		ctx.AbortWithStatusJSON(errorStatus(err), dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.Response{
+		Message: dto.MESSAGE_ACADEMIC_PERIOD_UPDATE_SUCCESS,
+		Status:  dto.STATUS_SUCCESS,
+	})
+}
+
+func (c *academicPeriodController) DeleteAcademicPeriod(ctx *gin.Context) {
+	id := ctx.Param("id")
+	err := c.academicPeriodService.DeleteAcademicPeriod(ctx, id, nil)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.Response{
+		Message: dto.MESSAGE_ACADEMIC_PERIOD_DELETE_SUCCESS,
+		Status:  dto.STATUS_SUCCESS,
+	})
+}
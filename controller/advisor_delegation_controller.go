@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"net/http"
+	"registration-service/dto"
+	"registration-service/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type advisorDelegationController struct {
+	advisorDelegationService service.AdvisorDelegationService
+}
+
+type AdvisorDelegationController interface {
+	GetAllAdvisorDelegations(ctx *gin.Context)
+	GetAdvisorDelegationByID(ctx *gin.Context)
+	CreateAdvisorDelegation(ctx *gin.Context)
+	RevokeAdvisorDelegation(ctx *gin.Context)
+}
+
+func NewAdvisorDelegationController(advisorDelegationService service.AdvisorDelegationService) AdvisorDelegationController {
+	return &advisorDelegationController{advisorDelegationService: advisorDelegationService}
+}
+
+func (c *advisorDelegationController) GetAllAdvisorDelegations(ctx *gin.Context) {
+	var filter dto.FilterAdvisorDelegationRequest
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	delegations, err := c.advisorDelegationService.FindAllAdvisorDelegations(ctx, filter, nil)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.Response{
+		Message: dto.MESSAGE_ADVISOR_DELEGATION_GET_ALL_SUCCESS,
+		Status:  dto.STATUS_SUCCESS,
+		Data:    delegations,
+	})
+}
+
+func (c *advisorDelegationController) GetAdvisorDelegationByID(ctx *gin.Context) {
+	id := ctx.Param("id")
+	delegation, err := c.advisorDelegationService.FindAdvisorDelegationByID(ctx, id, nil)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.Response{
+		Message: dto.MESSAGE_ADVISOR_DELEGATION_GET_SUCCESS,
+		Status:  dto.STATUS_SUCCESS,
+		Data:    delegation,
+	})
+}
+
+func (c *advisorDelegationController) CreateAdvisorDelegation(ctx *gin.Context) {
+	var request dto.AdvisorDelegationRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	token := ctx.GetHeader("Authorization")
+	if token == "" {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: dto.MESSAGE_UNAUTHORIZED,
+		})
+		return
+	}
+
+	delegation, err := c.advisorDelegationService.CreateAdvisorDelegation(ctx, request, token, nil)
+	if err != nil {
+		ctx.AbortWithStatusJSON(errorStatus(err), dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dto.Response{
+		Message: dto.MESSAGE_ADVISOR_DELEGATION_CREATE_SUCCESS,
+		Status:  dto.STATUS_SUCCESS,
+		Data:    delegation,
+	})
+}
+
+func (c *advisorDelegationController) RevokeAdvisorDelegation(ctx *gin.Context) {
+	id := ctx.Param("id")
+	err := c.advisorDelegationService.RevokeAdvisorDelegation(ctx, id, nil)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.Response{
+		Message: dto.MESSAGE_ADVISOR_DELEGATION_REVOKE_SUCCESS,
+		Status:  dto.STATUS_SUCCESS,
+	})
+}
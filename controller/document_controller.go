@@ -1,10 +1,17 @@
 package controller
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
 	"net/http"
 	"registration-service/dto"
 	"registration-service/helper"
 	"registration-service/service"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -19,6 +26,12 @@ type DocumentController interface {
 	CreateDocument(ctx *gin.Context)
 	UpdateDocument(ctx *gin.Context)
 	DeleteDocument(ctx *gin.Context)
+	GetDocumentVersions(ctx *gin.Context)
+	GetDocumentVersion(ctx *gin.Context)
+	DownloadDocument(ctx *gin.Context)
+	DownloadSignedDocument(ctx *gin.Context)
+	CreateDownloadLink(ctx *gin.Context)
+	ReviewDocument(ctx *gin.Context)
 }
 
 func NewDocumentController(documentService service.DocumentService) DocumentController {
@@ -26,7 +39,14 @@ func NewDocumentController(documentService service.DocumentService) DocumentCont
 }
 
 func (c *documentController) GetAllDocuments(ctx *gin.Context) {
-	pagReq := helper.Pagination(ctx)
+	pagReq, err := helper.CursorPagination(ctx)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
 
 	documents, metaData, err := c.documentService.FindAllDocuments(ctx, pagReq, nil)
 	if err != nil {
@@ -56,6 +76,7 @@ func (c *documentController) GetDocumentByID(ctx *gin.Context) {
 		return
 	}
 
+	setETag(ctx, document.LockVersion)
 	ctx.JSON(http.StatusOK, dto.Response{
 		Message: dto.MESSAGE_DOCUMENT_GET_SUCCESS,
 		Status:  dto.STATUS_SUCCESS,
@@ -75,7 +96,7 @@ func (c *documentController) CreateDocument(ctx *gin.Context) {
 	}
 
 	var request dto.DocumentRequest
-	err = ctx.ShouldBindJSON(&request)
+	err = ctx.ShouldBind(&request)
 	if err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
 			Status:  dto.STATUS_ERROR,
@@ -84,7 +105,8 @@ func (c *documentController) CreateDocument(ctx *gin.Context) {
 		return
 	}
 
-	err = c.documentService.CreateDocument(ctx, request, file, nil)
+	token := ctx.GetHeader("Authorization")
+	err = c.documentService.CreateDocument(ctx, request, file, token, nil)
 	if err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
 			Status:  dto.STATUS_ERROR,
@@ -112,7 +134,16 @@ func (c *documentController) UpdateDocument(ctx *gin.Context) {
 	}
 
 	var request dto.UpdateDocumentRequest
-	err = ctx.ShouldBindJSON(&request)
+	err = ctx.ShouldBind(&request)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	request.LockVersion, err = ifMatchVersion(ctx)
 	if err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
 			Status:  dto.STATUS_ERROR,
@@ -121,7 +152,12 @@ func (c *documentController) UpdateDocument(ctx *gin.Context) {
 		return
 	}
 
-	err = c.documentService.UpdateDocument(ctx, id, request, file, nil)
+	token := ctx.GetHeader("Authorization")
+	err = c.documentService.UpdateDocument(ctx, id, request, file, token, nil)
+	if stale, ok := staleVersion(err); ok {
+		c.abortStaleDocument(ctx, stale)
+		return
+	}
 	if err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
 			Status:  dto.STATUS_ERROR,
@@ -152,3 +188,329 @@ func (c *documentController) DeleteDocument(ctx *gin.Context) {
 		Status:  dto.STATUS_SUCCESS,
 	})
 }
+
+func (c *documentController) GetDocumentVersions(ctx *gin.Context) {
+	id := ctx.Param("id")
+	token := ctx.GetHeader("Authorization")
+
+	versions, err := c.documentService.FindDocumentVersions(ctx, id, token, nil)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.Response{
+		Message: dto.MESSAGE_DOCUMENT_VERSIONS_SUCCESS,
+		Status:  dto.STATUS_SUCCESS,
+		Data:    versions,
+	})
+}
+
+func (c *documentController) GetDocumentVersion(ctx *gin.Context) {
+	id := ctx.Param("id")
+	token := ctx.GetHeader("Authorization")
+
+	version, err := strconv.Atoi(ctx.Param("version"))
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: "invalid version",
+		})
+		return
+	}
+
+	documentVersion, err := c.documentService.FindDocumentVersion(ctx, id, version, token, nil)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.Response{
+		Message: dto.MESSAGE_DOCUMENT_VERSION_SUCCESS,
+		Status:  dto.STATUS_SUCCESS,
+		Data:    documentVersion,
+	})
+}
+
+func (c *documentController) DownloadDocument(ctx *gin.Context) {
+	id := ctx.Param("id")
+	token := ctx.GetHeader("Authorization")
+
+	version, err := queryVersion(ctx)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	download, err := c.documentService.DownloadDocument(ctx, id, version, token, nil)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	serveDocument(ctx, download)
+}
+
+func (c *documentController) DownloadSignedDocument(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	version, err := queryVersion(ctx)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	expires, err := strconv.ParseInt(ctx.Query("expires"), 10, 64)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: "invalid download link",
+		})
+		return
+	}
+
+	download, err := c.documentService.DownloadSignedDocument(ctx, id, version, expires, ctx.Query("signature"), nil)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	serveDocument(ctx, download)
+}
+
+func (c *documentController) CreateDownloadLink(ctx *gin.Context) {
+	id := ctx.Param("id")
+	token := ctx.GetHeader("Authorization")
+
+	version, err := queryVersion(ctx)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	link, err := c.documentService.CreateDownloadLink(ctx, id, version, token, nil)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.Response{
+		Message: dto.MESSAGE_DOCUMENT_LINK_SUCCESS,
+		Status:  dto.STATUS_SUCCESS,
+		Data:    link,
+	})
+}
+
+// queryVersion reads the optional version query, 0 means the current version
+func queryVersion(ctx *gin.Context) (int, error) {
+	if ctx.Query("version") == "" {
+		return 0, nil
+	}
+
+	version, err := strconv.Atoi(ctx.Query("version"))
+	if err != nil || version < 1 {
+		return 0, errors.New("invalid version")
+	}
+
+	return version, nil
+}
+
+// serveDocument streams the document to the client. A single byte range is
+// honoured when the size is known, anything else gets the whole document.
+func serveDocument(ctx *gin.Context, download service.DocumentDownload) {
+	defer download.Content.Close()
+
+	disposition := "attachment"
+	if ctx.Query("inline") == "true" {
+		disposition = "inline"
+	}
+
+	ctx.Header("Content-Type", download.ContentType)
+	ctx.Header("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": download.Name}))
+	ctx.Header("X-Content-Type-Options", "nosniff")
+	if !download.ModTime.IsZero() {
+		ctx.Header("Last-Modified", download.ModTime.UTC().Format(http.TimeFormat))
+	}
+	if download.Size < 0 {
+		ctx.Status(http.StatusOK)
+		copyDocument(ctx, download.Verified(), -1)
+		return
+	}
+
+	ctx.Header("Accept-Ranges", "bytes")
+	start, length, err := byteRange(ctx.Request, download.Size)
+	if err != nil {
+		ctx.Header("Content-Range", fmt.Sprintf("bytes */%d", download.Size))
+		ctx.AbortWithStatusJSON(http.StatusRequestedRangeNotSatisfiable, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if length < 0 {
+		ctx.Header("Content-Length", strconv.FormatInt(download.Size, 10))
+		ctx.Status(http.StatusOK)
+		copyDocument(ctx, download.Verified(), download.Size)
+		return
+	}
+
+	// a part can't be checked against the checksum of the whole document
+	if seeker, ok := download.Content.(io.Seeker); ok {
+		_, err = seeker.Seek(start, io.SeekStart)
+	} else {
+		_, err = io.CopyN(io.Discard, download.Content, start)
+	}
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: "failed to download file",
+		})
+		return
+	}
+
+	ctx.Header("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, start+length-1, download.Size))
+	ctx.Header("Content-Length", strconv.FormatInt(length, 10))
+	ctx.Status(http.StatusPartialContent)
+	copyDocument(ctx, download.Content, length)
+}
+
+// copyDocument writes n bytes of the content, or all of it when n is -1. The
+// headers are out at this point, a failure can only cut the transfer short.
+func copyDocument(ctx *gin.Context, content io.Reader, n int64) {
+	var err error
+	if n < 0 {
+		_, err = io.Copy(ctx.Writer, content)
+	} else {
+		_, err = io.CopyN(ctx.Writer, content, n)
+	}
+	if err != nil {
+		log.Printf("failed to stream document: %v", err)
+		ctx.Abort()
+	}
+}
+
+// byteRange reads a single "bytes=start-end" range from the request, length
+// is -1 when the whole document has to be sent. Malformed and multiple ranges
+// and ranges with If-Range are answered with the whole document.
+func byteRange(req *http.Request, size int64) (int64, int64, error) {
+	header := strings.TrimSpace(req.Header.Get("Range"))
+	if header == "" || req.Header.Get("If-Range") != "" {
+		return 0, -1, nil
+	}
+
+	spec, ok := strings.CutPrefix(header, "bytes=")
+	if !ok || strings.Contains(spec, ",") {
+		return 0, -1, nil
+	}
+
+	first, last, ok := strings.Cut(strings.TrimSpace(spec), "-")
+	if !ok {
+		return 0, -1, nil
+	}
+
+	var start, end int64
+	if first == "" {
+		// suffix range, the last n bytes
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n < 0 {
+			return 0, -1, nil
+		}
+		if n == 0 {
+			return 0, 0, errors.New("range not satisfiable")
+		}
+		start = size - n
+		if start < 0 {
+			start = 0
+		}
+		end = size - 1
+	} else {
+		var err error
+		start, err = strconv.ParseInt(first, 10, 64)
+		if err != nil || start < 0 {
+			return 0, -1, nil
+		}
+		end = size - 1
+		if last != "" {
+			end, err = strconv.ParseInt(last, 10, 64)
+			if err != nil || end < start {
+				return 0, -1, nil
+			}
+			if end > size-1 {
+				end = size - 1
+			}
+		}
+	}
+
+	if start >= size {
+		return 0, 0, errors.New("range not satisfiable")
+	}
+
+	return start, end - start + 1, nil
+}
+
+func (c *documentController) ReviewDocument(ctx *gin.Context) {
+	id := ctx.Param("id")
+	token := ctx.GetHeader("Authorization")
+
+	var request dto.DocumentReviewRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var err error
+	request.LockVersion, err = ifMatchVersion(ctx)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err = c.documentService.ReviewDocument(ctx, id, request, token, nil)
+	if stale, ok := staleVersion(err); ok {
+		c.abortStaleDocument(ctx, stale)
+		return
+	}
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.Response{
+		Message: dto.MESSAGE_DOCUMENT_REVIEW_SUCCESS,
+		Status:  dto.STATUS_SUCCESS,
+	})
+}
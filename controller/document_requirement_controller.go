@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"net/http"
+	"registration-service/dto"
+	"registration-service/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type documentRequirementController struct {
+	documentRequirementService service.DocumentRequirementService
+}
+
+type DocumentRequirementController interface {
+	GetAllDocumentRequirements(ctx *gin.Context)
+	GetDocumentRequirementByID(ctx *gin.Context)
+	CreateDocumentRequirement(ctx *gin.Context)
+	UpdateDocumentRequirement(ctx *gin.Context)
+	DeleteDocumentRequirement(ctx *gin.Context)
+}
+
+func NewDocumentRequirementController(documentRequirementService service.DocumentRequirementService) DocumentRequirementController {
+	return &documentRequirementController{documentRequirementService: documentRequirementService}
+}
+
+func (c *documentRequirementController) GetAllDocumentRequirements(ctx *gin.Context) {
+	var filter dto.FilterDocumentRequirementRequest
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	requirements, err := c.documentRequirementService.FindAllDocumentRequirements(ctx, filter, nil)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.Response{
+		Message: dto.MESSAGE_DOCUMENT_REQUIREMENT_GET_ALL_SUCCESS,
+		Status:  dto.STATUS_SUCCESS,
+		Data:    requirements,
+	})
+}
+
+func (c *documentRequirementController) GetDocumentRequirementByID(ctx *gin.Context) {
+	id := ctx.Param("id")
+	requirement, err := c.documentRequirementService.FindDocumentRequirementByID(ctx, id, nil)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.Response{
+		Message: dto.MESSAGE_DOCUMENT_REQUIREMENT_GET_SUCCESS,
+		Status:  dto.STATUS_SUCCESS,
+		Data:    requirement,
+	})
+}
+
+func (c *documentRequirementController) CreateDocumentRequirement(ctx *gin.Context) {
+	var request dto.DocumentRequirementRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err := c.documentRequirementService.CreateDocumentRequirement(ctx, request, nil)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dto.Response{
+		Message: dto.MESSAGE_DOCUMENT_REQUIREMENT_CREATE_SUCCESS,
+		Status:  dto.STATUS_SUCCESS,
+	})
+}
+
+func (c *documentRequirementController) UpdateDocumentRequirement(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	var request dto.DocumentRequirementRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err := c.documentRequirementService.UpdateDocumentRequirement(ctx, id, request, nil)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.Response{
+		Message: dto.MESSAGE_DOCUMENT_REQUIREMENT_UPDATE_SUCCESS,
+		Status:  dto.STATUS_SUCCESS,
+	})
+}
+
+func (c *documentRequirementController) DeleteDocumentRequirement(ctx *gin.Context) {
+	id := ctx.Param("id")
+	err := c.documentRequirementService.DeleteDocumentRequirement(ctx, id, nil)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.Response{
+		Message: dto.MESSAGE_DOCUMENT_REQUIREMENT_DELETE_SUCCESS,
+		Status:  dto.STATUS_SUCCESS,
+	})
+}
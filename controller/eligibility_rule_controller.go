@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"net/http"
+	"registration-service/dto"
+	"registration-service/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type eligibilityRuleController struct {
+	eligibilityRuleService service.EligibilityRuleService
+}
+
+type EligibilityRuleController interface {
+	GetAllEligibilityRules(ctx *gin.Context)
+	GetEligibilityRuleByID(ctx *gin.Context)
+	CreateEligibilityRule(ctx *gin.Context)
+	UpdateEligibilityRule(ctx *gin.Context)
+	DeleteEligibilityRule(ctx *gin.Context)
+}
+
+func NewEligibilityRuleController(eligibilityRuleService service.EligibilityRuleService) EligibilityRuleController {
+	return &eligibilityRuleController{eligibilityRuleService: eligibilityRuleService}
+}
+
+func (c *eligibilityRuleController) GetAllEligibilityRules(ctx *gin.Context) {
+	var filter dto.FilterEligibilityRuleRequest
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	rules, err := c.eligibilityRuleService.FindAllEligibilityRules(ctx, filter, nil)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.Response{
+		Message: dto.MESSAGE_ELIGIBILITY_RULE_GET_ALL_SUCCESS,
+		Status:  dto.STATUS_SUCCESS,
+		Data:    rules,
+	})
+}
+
+func (c *eligibilityRuleController) GetEligibilityRuleByID(ctx *gin.Context) {
+	id := ctx.Param("id")
+	rule, err := c.eligibilityRuleService.FindEligibilityRuleByID(ctx, id, nil)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.Response{
+		Message: dto.MESSAGE_ELIGIBILITY_RULE_GET_SUCCESS,
+		Status:  dto.STATUS_SUCCESS,
+		Data:    rule,
+	})
+}
+
+func (c *eligibilityRuleController) CreateEligibilityRule(ctx *gin.Context) {
+	var request dto.EligibilityRuleRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err := c.eligibilityRuleService.CreateEligibilityRule(ctx, request, nil)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dto.Response{
+		Message: dto.MESSAGE_ELIGIBILITY_RULE_CREATE_SUCCESS,
+		Status:  dto.STATUS_SUCCESS,
+	})
+}
+
+func (c *eligibilityRuleController) UpdateEligibilityRule(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	var request dto.EligibilityRuleRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err := c.eligibilityRuleService.UpdateEligibilityRule(ctx, id, request, nil)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.Response{
+		Message: dto.MESSAGE_ELIGIBILITY_RULE_UPDATE_SUCCESS,
+		Status:  dto.STATUS_SUCCESS,
+	})
+}
+
+func (c *eligibilityRuleController) DeleteEligibilityRule(ctx *gin.Context) {
+	id := ctx.Param("id")
+	err := c.eligibilityRuleService.DeleteEligibilityRule(ctx, id, nil)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.Response{
+		Message: dto.MESSAGE_ELIGIBILITY_RULE_DELETE_SUCCESS,
+		Status:  dto.STATUS_SUCCESS,
+	})
+}
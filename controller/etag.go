@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"registration-service/dto"
+	"registration-service/service"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setETag exposes the lock version of the returned row, clients send it back
+// in If-Match to make sure nobody changed the row in between
+func setETag(ctx *gin.Context, lockVersion int64) {
+	ctx.Header("ETag", strconv.Quote(strconv.FormatInt(lockVersion, 10)))
+}
+
+// ifMatchVersion reads the lock version from If-Match, nil when the header is
+// missing or "*" so clients that don't send it keep working
+func ifMatchVersion(ctx *gin.Context) (*int64, error) {
+	value := strings.TrimSpace(ctx.GetHeader("If-Match"))
+	if value == "" || value == "*" {
+		return nil, nil
+	}
+
+	value = strings.Trim(strings.TrimPrefix(value, "W/"), `"`)
+	version, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid If-Match header %q", ctx.GetHeader("If-Match"))
+	}
+
+	return &version, nil
+}
+
+// staleVersion returns the StaleVersionError in err, if any
+func staleVersion(err error) (*service.StaleVersionError, bool) {
+	var stale *service.StaleVersionError
+	if errors.As(err, &stale) {
+		return stale, true
+	}
+
+	return nil, false
+}
+
+// abortStaleRegistration answers a stale write with 409 and the registration
+// as it is now, so the client can merge and retry with the new ETag
+func (c *registrationController) abortStaleRegistration(ctx *gin.Context, stale *service.StaleVersionError, token string) {
+	response := dto.Response{
+		Status:  dto.STATUS_ERROR,
+		Message: stale.Error(),
+	}
+
+	registration, err := c.registrationService.FindRegistrationByID(ctx, stale.ID, token, nil)
+	if err == nil {
+		setETag(ctx, registration.LockVersion)
+		response.Data = registration
+	}
+
+	ctx.AbortWithStatusJSON(http.StatusConflict, response)
+}
+
+// abortStaleDocument is abortStaleRegistration for documents
+func (c *documentController) abortStaleDocument(ctx *gin.Context, stale *service.StaleVersionError) {
+	response := dto.Response{
+		Status:  dto.STATUS_ERROR,
+		Message: stale.Error(),
+	}
+
+	document, err := c.documentService.FindDocumentById(ctx, stale.ID, nil)
+	if err == nil {
+		setETag(ctx, document.LockVersion)
+		response.Data = document
+	}
+
+	ctx.AbortWithStatusJSON(http.StatusConflict, response)
+}
@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"net/http"
+	"registration-service/dto"
+	"registration-service/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type healthController struct {
+	healthService service.HealthService
+}
+
+type HealthController interface {
+	Live(ctx *gin.Context)
+	Ready(ctx *gin.Context)
+}
+
+func NewHealthController(healthService service.HealthService) HealthController {
+	return &healthController{healthService: healthService}
+}
+
+func (c *healthController) Live(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, dto.Response{
+		Message: dto.MESSAGE_HEALTH_LIVE,
+		Status:  dto.STATUS_SUCCESS,
+	})
+}
+
+func (c *healthController) Ready(ctx *gin.Context) {
+	health, ready := c.healthService.Ready(ctx)
+	if !ready {
+		ctx.AbortWithStatusJSON(http.StatusServiceUnavailable, dto.Response{
+			Message: dto.MESSAGE_HEALTH_NOT_READY,
+			Status:  dto.STATUS_ERROR,
+			Data:    health,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.Response{
+		Message: dto.MESSAGE_HEALTH_READY,
+		Status:  dto.STATUS_SUCCESS,
+		Data:    health,
+	})
+}
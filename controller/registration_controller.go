@@ -1,6 +1,9 @@
 package controller
 
 import (
+	"errors"
+	"log"
+	"mime"
 	"net/http"
 	"registration-service/dto"
 	"registration-service/helper"
@@ -18,6 +21,7 @@ type RegistrationController interface {
 	GetRegistrationByID(ctx *gin.Context)
 	CreateRegistration(ctx *gin.Context)
 	UpdateRegistration(ctx *gin.Context)
+	PatchRegistration(ctx *gin.Context)
 	DeleteRegistration(ctx *gin.Context)
 	GetRegistrationsByAdvisor(ctx *gin.Context)
 	GetRegistrationsByLOMBKM(ctx *gin.Context)
@@ -29,6 +33,15 @@ type RegistrationController interface {
 	GetStudentRegistrationsWithMatching(ctx *gin.Context)
 	CheckRegistrationEligibility(ctx *gin.Context)
 	GetTotalRegistrationByAdvisorEmail(ctx *gin.Context)
+	CreateDraftRegistration(ctx *gin.Context)
+	SubmitRegistration(ctx *gin.Context)
+	WithdrawRegistration(ctx *gin.Context)
+	ApproveWithdrawal(ctx *gin.Context)
+	GetRegistrationHistory(ctx *gin.Context)
+	GetRegistrationDocumentsArchive(ctx *gin.Context)
+	GetActivityDocumentsArchive(ctx *gin.Context)
+	ImportRegistrations(ctx *gin.Context)
+	ReassignAdvisor(ctx *gin.Context)
 }
 
 func NewRegistrationController(registrationService service.RegistrationService) RegistrationController {
@@ -47,7 +60,7 @@ func (c *registrationController) GetTotalRegistrationByAdvisorEmail(ctx *gin.Con
 		return
 	}
 
-	registrationCount, err := c.registrationService.FindTotalRegistrationByAdvisorEmail(ctx, token, nil)
+	registrationCount, err := c.registrationService.FindTotalRegistrationByAdvisorEmail(ctx, token, ctx.Query("academic_period_id"), nil)
 	if err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
 			Status:  dto.STATUS_ERROR,
@@ -74,6 +87,18 @@ func (c *registrationController) ApproveRegistration(ctx *gin.Context) {
 		return
 	}
 
+	request.LockVersion, err = ifMatchVersion(ctx)
+	if err == nil && request.LockVersion != nil && len(request.ID) != 1 {
+		err = errors.New("If-Match can only be used when approving a single registration")
+	}
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
 	token := ctx.GetHeader("Authorization")
 	if token == "" {
 		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.Response{
@@ -104,6 +129,10 @@ func (c *registrationController) ApproveRegistration(ctx *gin.Context) {
 
 	if userRoleStr == "DOSEN PEMBIMBING" {
 		err := c.registrationService.AdvisorRegistrationApproval(ctx, token, request, nil)
+		if stale, ok := staleVersion(err); ok {
+			c.abortStaleRegistration(ctx, stale, token)
+			return
+		}
 		if err != nil {
 			ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
 				Status:  dto.STATUS_ERROR,
@@ -113,6 +142,10 @@ func (c *registrationController) ApproveRegistration(ctx *gin.Context) {
 		}
 	} else if userRoleStr == "ADMIN" {
 		err := c.registrationService.LORegistrationApproval(ctx, token, request, nil)
+		if stale, ok := staleVersion(err); ok {
+			c.abortStaleRegistration(ctx, stale, token)
+			return
+		}
 		if err != nil {
 			ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
 				Status:  dto.STATUS_ERROR,
@@ -122,6 +155,10 @@ func (c *registrationController) ApproveRegistration(ctx *gin.Context) {
 		}
 	} else if userRoleStr == "LO-MBKM" {
 		err := c.registrationService.LORegistrationApproval(ctx, token, request, nil)
+		if stale, ok := staleVersion(err); ok {
+			c.abortStaleRegistration(ctx, stale, token)
+			return
+		}
 		if err != nil {
 			ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
 				Status:  dto.STATUS_ERROR,
@@ -165,7 +202,15 @@ func (c *registrationController) GetRegistrationsByStudent(ctx *gin.Context) {
 		return
 	}
 
-	pagReq := helper.Pagination(ctx)
+	pagReq, err := helper.CursorPagination(ctx)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
 	registrations, metaData, err := c.registrationService.FindRegistrationByStudent(ctx, pagReq, request, token, nil)
 
 	if err != nil {
@@ -205,7 +250,20 @@ func (c *registrationController) GetRegistrationsByAdvisor(ctx *gin.Context) {
 		return
 	}
 
-	pagReq := helper.Pagination(ctx)
+	if ctx.Query("export") != "" {
+		c.exportRegistrations(ctx, service.EXPORT_SCOPE_ADVISOR, request, token)
+		return
+	}
+
+	pagReq, err := helper.CursorPagination(ctx)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
 	registrations, metaData, err := c.registrationService.FindRegistrationByAdvisor(ctx, pagReq, request, token, nil)
 
 	if err != nil {
@@ -245,7 +303,20 @@ func (c *registrationController) GetRegistrationsByLOMBKM(ctx *gin.Context) {
 		return
 	}
 
-	pagReq := helper.Pagination(ctx)
+	if ctx.Query("export") != "" {
+		c.exportRegistrations(ctx, service.EXPORT_SCOPE_LO_MBKM, request, token)
+		return
+	}
+
+	pagReq, err := helper.CursorPagination(ctx)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
 	registrations, metaData, err := c.registrationService.FindRegistrationByLOMBKM(ctx, pagReq, request, token, nil)
 
 	if err != nil {
@@ -285,7 +356,20 @@ func (c *registrationController) GetAllRegistrations(ctx *gin.Context) {
 		return
 	}
 
-	pagReq := helper.Pagination(ctx)
+	if ctx.Query("export") != "" {
+		c.exportRegistrations(ctx, service.EXPORT_SCOPE_ALL, request, token)
+		return
+	}
+
+	pagReq, err := helper.CursorPagination(ctx)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
 	registrations, metaData, err := c.registrationService.FindAllRegistrations(ctx, pagReq, request, nil, token)
 
 	if err != nil {
@@ -344,7 +428,7 @@ func (c *registrationController) CreateRegistration(ctx *gin.Context) {
 
 	err = c.registrationService.CreateRegistration(ctx, request, file, geoletter, nil, token)
 	if err != nil {
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+		ctx.AbortWithStatusJSON(errorStatus(err), dto.Response{
 			Status:  dto.STATUS_ERROR,
 			Message: err.Error(),
 		})
@@ -357,6 +441,152 @@ func (c *registrationController) CreateRegistration(ctx *gin.Context) {
 	})
 }
 
+func (c *registrationController) ImportRegistrations(ctx *gin.Context) {
+	var request dto.RegistrationImportRequest
+	err := ctx.ShouldBind(&request)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// get header token
+	token := ctx.GetHeader("Authorization")
+	if token == "" {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: dto.MESSAGE_UNAUTHORIZED,
+		})
+		return
+	}
+
+	result, err := c.registrationService.ImportRegistrations(ctx, file, request, token, nil)
+	if err != nil {
+		ctx.AbortWithStatusJSON(errorStatus(err), dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.Response{
+		Message: dto.MESSAGE_REGISTRATION_IMPORT_SUCCESS,
+		Status:  dto.STATUS_SUCCESS,
+		Data:    result,
+	})
+}
+
+func (c *registrationController) ReassignAdvisor(ctx *gin.Context) {
+	var request dto.AdvisorReassignmentRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// get header token
+	token := ctx.GetHeader("Authorization")
+	if token == "" {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: dto.MESSAGE_UNAUTHORIZED,
+		})
+		return
+	}
+
+	result, err := c.registrationService.ReassignAdvisor(ctx, request, token, nil)
+	if err != nil {
+		ctx.AbortWithStatusJSON(errorStatus(err), dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.Response{
+		Message: dto.MESSAGE_ADVISOR_REASSIGN_SUCCESS,
+		Status:  dto.STATUS_SUCCESS,
+		Data:    result,
+	})
+}
+
+func (c *registrationController) CreateDraftRegistration(ctx *gin.Context) {
+	var request dto.CreateDraftRegistrationRequest
+	err := ctx.ShouldBind(&request)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// get header token
+	token := ctx.GetHeader("Authorization")
+	if token == "" {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: dto.MESSAGE_UNAUTHORIZED,
+		})
+		return
+	}
+
+	registration, err := c.registrationService.CreateDraftRegistration(ctx, request, token, nil)
+	if err != nil {
+		ctx.AbortWithStatusJSON(errorStatus(err), dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dto.Response{
+		Message: dto.MESSAGE_REGISTRATION_DRAFT_SUCCESS,
+		Status:  dto.STATUS_SUCCESS,
+		Data:    registration,
+	})
+}
+
+func (c *registrationController) SubmitRegistration(ctx *gin.Context) {
+	token := ctx.GetHeader("Authorization")
+	if token == "" {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: dto.MESSAGE_UNAUTHORIZED,
+		})
+		return
+	}
+
+	id := ctx.Param("id")
+	err := c.registrationService.SubmitRegistration(ctx, id, token, nil)
+	if err != nil {
+		ctx.AbortWithStatusJSON(errorStatus(err), dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.Response{
+		Message: dto.MESSAGE_REGISTRATION_SUBMIT_SUCCESS,
+		Status:  dto.STATUS_SUCCESS,
+	})
+}
+
 func (c *registrationController) GetRegistrationByID(ctx *gin.Context) {
 	token := ctx.GetHeader("Authorization")
 	if token == "" {
@@ -377,6 +607,7 @@ func (c *registrationController) GetRegistrationByID(ctx *gin.Context) {
 		return
 	}
 
+	setETag(ctx, activity.LockVersion)
 	ctx.JSON(http.StatusOK, dto.Response{
 		Message: dto.MESSAGE_REGISTRATION_GET_SUCCESS,
 		Status:  dto.STATUS_SUCCESS,
@@ -405,7 +636,69 @@ func (c *registrationController) UpdateRegistration(ctx *gin.Context) {
 		return
 	}
 
+	request.LockVersion, err = ifMatchVersion(ctx)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
 	err = c.registrationService.UpdateRegistration(ctx, id, request, token, nil)
+	if stale, ok := staleVersion(err); ok {
+		c.abortStaleRegistration(ctx, stale, token)
+		return
+	}
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.Response{
+		Message: dto.MESSAGE_REGISTRATION_UPDATE_SUCCESS,
+		Status:  dto.STATUS_SUCCESS,
+	})
+}
+
+func (c *registrationController) PatchRegistration(ctx *gin.Context) {
+	token := ctx.GetHeader("Authorization")
+	if token == "" {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: dto.MESSAGE_UNAUTHORIZED,
+		})
+		return
+	}
+
+	id := ctx.Param("id")
+	var request dto.PatchRegistrationRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	request.LockVersion, err = ifMatchVersion(ctx)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err = c.registrationService.PatchRegistration(ctx, id, request, token, nil)
+	if stale, ok := staleVersion(err); ok {
+		c.abortStaleRegistration(ctx, stale, token)
+		return
+	}
 	if err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
 			Status:  dto.STATUS_ERROR,
@@ -632,3 +925,249 @@ func (c *registrationController) CheckRegistrationEligibility(ctx *gin.Context)
 		Data:    eligibility,
 	})
 }
+
+func (c *registrationController) WithdrawRegistration(ctx *gin.Context) {
+	token := ctx.GetHeader("Authorization")
+	if token == "" {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: dto.MESSAGE_UNAUTHORIZED,
+		})
+		return
+	}
+
+	id := ctx.Param("id")
+	var request dto.WithdrawalRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err = c.registrationService.RequestWithdrawal(ctx, id, request, token, nil)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.Response{
+		Message: dto.MESSAGE_REGISTRATION_WITHDRAW_SUCCESS,
+		Status:  dto.STATUS_SUCCESS,
+	})
+}
+
+func (c *registrationController) ApproveWithdrawal(ctx *gin.Context) {
+	var request dto.ApprovalRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	request.LockVersion, err = ifMatchVersion(ctx)
+	if err == nil && request.LockVersion != nil && len(request.ID) != 1 {
+		err = errors.New("If-Match can only be used when approving a single registration")
+	}
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	token := ctx.GetHeader("Authorization")
+	if token == "" {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: dto.MESSAGE_UNAUTHORIZED,
+		})
+		return
+	}
+
+	userRole, exists := ctx.Get("userRole")
+	if !exists {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: dto.MESSAGE_UNAUTHORIZED,
+		})
+		return
+	}
+
+	userRoleStr, ok := userRole.(string)
+	if !ok {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: dto.MESSAGE_UNAUTHORIZED,
+		})
+		return
+	}
+
+	switch userRoleStr {
+	case "DOSEN PEMBIMBING":
+		err = c.registrationService.AdvisorWithdrawalApproval(ctx, token, request, nil)
+	case "ADMIN", "LO-MBKM":
+		err = c.registrationService.LOWithdrawalApproval(ctx, token, request, nil)
+	default:
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: dto.MESSAGE_UNAUTHORIZED,
+		})
+		return
+	}
+
+	if stale, ok := staleVersion(err); ok {
+		c.abortStaleRegistration(ctx, stale, token)
+		return
+	}
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.Response{
+		Status:  dto.STATUS_SUCCESS,
+		Message: dto.MESSAGE_REGISTRATION_WITHDRAWAL_SUCCESS,
+	})
+}
+
+func (c *registrationController) GetRegistrationHistory(ctx *gin.Context) {
+	token := ctx.GetHeader("Authorization")
+	if token == "" {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: dto.MESSAGE_UNAUTHORIZED,
+		})
+		return
+	}
+
+	id := ctx.Param("id")
+	histories, err := c.registrationService.FindRegistrationHistory(ctx, id, token, nil)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.Response{
+		Message: dto.MESSAGE_REGISTRATION_HISTORY_SUCCESS,
+		Status:  dto.STATUS_SUCCESS,
+		Data:    histories,
+	})
+}
+
+func (c *registrationController) GetRegistrationDocumentsArchive(ctx *gin.Context) {
+	token := ctx.GetHeader("Authorization")
+	if token == "" {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: dto.MESSAGE_UNAUTHORIZED,
+		})
+		return
+	}
+
+	id := ctx.Param("id")
+	archive, err := c.registrationService.RegistrationDocumentsArchive(ctx, id, token, nil)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	streamArchive(ctx, archive)
+}
+
+func (c *registrationController) GetActivityDocumentsArchive(ctx *gin.Context) {
+	token := ctx.GetHeader("Authorization")
+	if token == "" {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: dto.MESSAGE_UNAUTHORIZED,
+		})
+		return
+	}
+
+	activityID := ctx.Param("activityID")
+	archive, err := c.registrationService.ActivityDocumentsArchive(ctx, activityID, token, nil)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	streamArchive(ctx, archive)
+}
+
+// streamArchive writes the ZIP as it is built. Once the first bytes are sent
+// the status can't change anymore, so a late failure is only logged and the
+// client ends up with a truncated archive.
+func streamArchive(ctx *gin.Context, archive service.DocumentArchive) {
+	ctx.Header("Content-Type", "application/zip")
+	ctx.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": archive.Name}))
+	ctx.Status(http.StatusOK)
+
+	err := archive.Write(ctx, ctx.Writer)
+	if err != nil {
+		log.Printf("ERROR WRITING ARCHIVE %s: %v", archive.Name, err)
+	}
+}
+
+// exportRegistrations answers a listing endpoint with a CSV or XLSX file
+// instead of the paginated JSON when the export query parameter is set
+func (c *registrationController) exportRegistrations(ctx *gin.Context, scope string, filter dto.FilterRegistrationRequest, token string) {
+	var request dto.RegistrationExportRequest
+	err := ctx.ShouldBindQuery(&request)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	export, err := c.registrationService.ExportRegistrations(ctx, scope, filter, request, token, nil)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.Header("Content-Type", export.ContentType)
+	ctx.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": export.Name}))
+	ctx.Status(http.StatusOK)
+
+	err = export.Write(ctx, ctx.Writer)
+	if err != nil {
+		log.Printf("ERROR WRITING EXPORT %s: %v", export.Name, err)
+	}
+}
+
+// errorStatus answers a clash with an existing registration with 409, every
+// other service error stays a bad request
+func errorStatus(err error) int {
+	if errors.Is(err, service.ErrConflict) {
+		return http.StatusConflict
+	}
+
+	return http.StatusBadRequest
+}
@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"net/http"
+	"registration-service/dto"
+	"registration-service/helper"
+	"registration-service/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type registrationTrashController struct {
+	registrationTrashService service.RegistrationTrashService
+}
+
+type RegistrationTrashController interface {
+	GetDeletedRegistrations(ctx *gin.Context)
+	GetDeletedRegistrationByID(ctx *gin.Context)
+	RestoreRegistration(ctx *gin.Context)
+}
+
+func NewRegistrationTrashController(registrationTrashService service.RegistrationTrashService) RegistrationTrashController {
+	return &registrationTrashController{registrationTrashService: registrationTrashService}
+}
+
+func (c *registrationTrashController) GetDeletedRegistrations(ctx *gin.Context) {
+	pagReq := helper.Pagination(ctx)
+	registrations, metaData, err := c.registrationTrashService.FindDeletedRegistrations(ctx, pagReq, nil)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.Response{
+		Message:            dto.MESSAGE_REGISTRATION_TRASH_GET_ALL_SUCCESS,
+		Status:             dto.STATUS_SUCCESS,
+		Data:               registrations,
+		PaginationResponse: &metaData,
+	})
+}
+
+func (c *registrationTrashController) GetDeletedRegistrationByID(ctx *gin.Context) {
+	id := ctx.Param("id")
+	registration, err := c.registrationTrashService.FindDeletedRegistrationByID(ctx, id, nil)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.Response{
+		Message: dto.MESSAGE_REGISTRATION_TRASH_GET_SUCCESS,
+		Status:  dto.STATUS_SUCCESS,
+		Data:    registration,
+	})
+}
+
+func (c *registrationTrashController) RestoreRegistration(ctx *gin.Context) {
+	token := ctx.GetHeader("Authorization")
+	if token == "" {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: dto.MESSAGE_UNAUTHORIZED,
+		})
+		return
+	}
+
+	id := ctx.Param("id")
+	err := c.registrationTrashService.RestoreRegistration(ctx, id, token, nil)
+	if err != nil {
+		ctx.AbortWithStatusJSON(errorStatus(err), dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.Response{
+		Message: dto.MESSAGE_REGISTRATION_RESTORE_SUCCESS,
+		Status:  dto.STATUS_SUCCESS,
+	})
+}
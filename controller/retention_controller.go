@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"net/http"
+	"registration-service/dto"
+	"registration-service/helper"
+	"registration-service/service"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type retentionController struct {
+	retentionService service.RetentionService
+}
+
+type RetentionController interface {
+	GetRetentionReport(ctx *gin.Context)
+	RunRetention(ctx *gin.Context)
+}
+
+func NewRetentionController(retentionService service.RetentionService) RetentionController {
+	return &retentionController{retentionService: retentionService}
+}
+
+func (c *retentionController) GetRetentionReport(ctx *gin.Context) {
+	var request dto.RetentionReportRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	pagReq := helper.Pagination(ctx)
+	report, metaData, err := c.retentionService.RetentionReport(ctx, request, pagReq, nil)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.Response{
+		Message:            dto.MESSAGE_RETENTION_REPORT_SUCCESS,
+		Status:             dto.STATUS_SUCCESS,
+		Data:               report,
+		PaginationResponse: &metaData,
+	})
+}
+
+// RunRetention anonymizes one batch of due registrations right away, with
+// dry_run it only reports them
+func (c *retentionController) RunRetention(ctx *gin.Context) {
+	var request dto.RetentionRunRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	result, err := c.retentionService.AnonymizeDueRegistrations(ctx, time.Now(), request.DryRun)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.Response{
+			Status:  dto.STATUS_ERROR,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.Response{
+		Message: dto.MESSAGE_RETENTION_RUN_SUCCESS,
+		Status:  dto.STATUS_SUCCESS,
+		Data:    result,
+	})
+}
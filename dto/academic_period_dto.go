@@ -0,0 +1,35 @@
+package dto
+
+import "time"
+
+const (
+	MESSAGE_ACADEMIC_PERIOD_GET_ALL_SUCCESS = "Get all academic periods success"
+	MESSAGE_ACADEMIC_PERIOD_GET_SUCCESS     = "Get academic period success"
+	MESSAGE_ACADEMIC_PERIOD_CREATE_SUCCESS  = "Create academic period success"
+	MESSAGE_ACADEMIC_PERIOD_UPDATE_SUCCESS  = "Update academic period success"
+	MESSAGE_ACADEMIC_PERIOD_DELETE_SUCCESS  = "Delete academic period success"
+)
+
+type (
+	AcademicPeriodRequest struct {
+		Year                       string    `json:"year" binding:"required"`
+		Term                       string    `json:"term" binding:"required,oneof=ODD EVEN"`
+		StartDate                  time.Time `json:"start_date" binding:"required"`
+		EndDate                    time.Time `json:"end_date" binding:"required"`
+		MaxRegistrationsPerStudent int       `json:"max_registrations_per_student" binding:"gte=0"`
+	}
+
+	FilterAcademicPeriodRequest struct {
+		Year string `form:"year"`
+		Term string `form:"term"`
+	}
+
+	AcademicPeriodResponse struct {
+		ID                         string    `json:"id"`
+		Year                       string    `json:"year"`
+		Term                       string    `json:"term"`
+		StartDate                  time.Time `json:"start_date"`
+		EndDate                    time.Time `json:"end_date"`
+		MaxRegistrationsPerStudent int       `json:"max_registrations_per_student"`
+	}
+)
@@ -0,0 +1,62 @@
+package dto
+
+import "time"
+
+const (
+	MESSAGE_ADVISOR_DELEGATION_GET_ALL_SUCCESS = "Get all advisor delegations success"
+	MESSAGE_ADVISOR_DELEGATION_GET_SUCCESS     = "Get advisor delegation success"
+	MESSAGE_ADVISOR_DELEGATION_CREATE_SUCCESS  = "Create advisor delegation success"
+	MESSAGE_ADVISOR_DELEGATION_REVOKE_SUCCESS  = "Revoke advisor delegation success"
+	MESSAGE_ADVISOR_REASSIGN_SUCCESS           = "Reassign academic advisor success"
+)
+
+type (
+	AdvisorDelegationRequest struct {
+		AdvisorEmail  string    `json:"advisor_email" binding:"required,email"`
+		DelegateEmail string    `json:"delegate_email" binding:"required,email"`
+		StartsAt      time.Time `json:"starts_at" binding:"required"`
+		EndsAt        time.Time `json:"ends_at" binding:"required"`
+		Reason        string    `json:"reason"`
+	}
+
+	// FilterAdvisorDelegationRequest with Active set only lists the
+	// delegations in force right now
+	FilterAdvisorDelegationRequest struct {
+		AdvisorEmail  string `form:"advisor_email"`
+		DelegateEmail string `form:"delegate_email"`
+		Active        bool   `form:"active"`
+	}
+
+	AdvisorDelegationResponse struct {
+		ID            string    `json:"id"`
+		AdvisorEmail  string    `json:"advisor_email"`
+		AdvisorName   string    `json:"advisor_name"`
+		DelegateEmail string    `json:"delegate_email"`
+		DelegateName  string    `json:"delegate_name"`
+		StartsAt      time.Time `json:"starts_at"`
+		EndsAt        time.Time `json:"ends_at"`
+		Reason        string    `json:"reason"`
+		Active        bool      `json:"active"`
+		CreatedByName string    `json:"created_by_name"`
+	}
+
+	// AdvisorReassignmentRequest moves registrations to another academic
+	// advisor, either the listed ones or every open registration of
+	// FromAdvisorEmail. ResetApproval also re-opens approvals the previous
+	// advisor already gave.
+	AdvisorReassignmentRequest struct {
+		RegistrationIDs  []string `json:"registration_ids"`
+		FromAdvisorEmail string   `json:"from_advisor_email"`
+		AdvisorEmail     string   `json:"advisor_email" binding:"required,email"`
+		Reason           string   `json:"reason"`
+		ResetApproval    bool     `json:"reset_approval"`
+	}
+
+	AdvisorReassignmentResponse struct {
+		AdvisorEmail    string   `json:"advisor_email"`
+		AdvisorName     string   `json:"advisor_name"`
+		Reassigned      int      `json:"reassigned"`
+		RegistrationIDs []string `json:"registration_ids"`
+		Skipped         []string `json:"skipped"`
+	}
+)
@@ -1,11 +1,15 @@
 package dto
 
 const (
-	MESSAGE_DOCUMENT_GET_ALL_SUCCESS = "Get all documents success"
-	MESSAGE_DOCUMENT_GET_SUCCESS     = "Get document success"
-	MESSAGE_DOCUMENT_CREATE_SUCCESS  = "Create document success"
-	MESSAGE_DOCUMENT_UPDATE_SUCCESS  = "Update document success"
-	MESSAGE_DOCUMENT_DELETE_SUCCESS  = "Delete document success"
+	MESSAGE_DOCUMENT_GET_ALL_SUCCESS  = "Get all documents success"
+	MESSAGE_DOCUMENT_GET_SUCCESS      = "Get document success"
+	MESSAGE_DOCUMENT_CREATE_SUCCESS   = "Create document success"
+	MESSAGE_DOCUMENT_UPDATE_SUCCESS   = "Update document success"
+	MESSAGE_DOCUMENT_DELETE_SUCCESS   = "Delete document success"
+	MESSAGE_DOCUMENT_VERSIONS_SUCCESS = "Get document versions success"
+	MESSAGE_DOCUMENT_VERSION_SUCCESS  = "Get document version success"
+	MESSAGE_DOCUMENT_LINK_SUCCESS     = "Create document download link success"
+	MESSAGE_DOCUMENT_REVIEW_SUCCESS   = "Review document success"
 )
 
 type (
@@ -15,8 +19,17 @@ type (
 		DocumentType   string `json:"document_type" form:"document_type" binding:"required"`
 	}
 
+	// UpdateDocumentRequest and DocumentReviewRequest carry the lock version
+	// from If-Match, nil when the client didn't send one
 	UpdateDocumentRequest struct {
 		RegistrationID string `json:"registration_id" form:"registration_id" binding:"required"`
+		LockVersion    *int64 `json:"-" form:"-"`
+	}
+
+	DocumentReviewRequest struct {
+		Status      string `json:"status" binding:"required,oneof=ACCEPTED NEEDS_REVISION"`
+		Comment     string `json:"comment"`
+		LockVersion *int64 `json:"-"`
 	}
 
 	DocumentResponse struct {
@@ -25,5 +38,35 @@ type (
 		FileStorageID  string `json:"file_storage_id"`
 		Name           string `json:"name"`
 		DocumentType   string `json:"document_type"`
+		Checksum       string `json:"checksum"`
+		ContentType    string `json:"content_type"`
+		Size           int64  `json:"size"`
+		CurrentVersion int    `json:"current_version"`
+		LockVersion    int64  `json:"lock_version"`
+		ReviewStatus   string `json:"review_status"`
+		ReviewerName   string `json:"reviewer_name"`
+		ReviewerEmail  string `json:"reviewer_email"`
+		ReviewComment  string `json:"review_comment"`
+		ReviewedAt     string `json:"reviewed_at"`
+	}
+
+	DocumentVersionResponse struct {
+		ID              string `json:"id"`
+		DocumentID      string `json:"document_id"`
+		Version         int    `json:"version"`
+		FileStorageID   string `json:"file_storage_id"`
+		Name            string `json:"name"`
+		Checksum        string `json:"checksum"`
+		ContentType     string `json:"content_type"`
+		Size            int64  `json:"size"`
+		UploadedByID    string `json:"uploaded_by_id"`
+		UploadedByName  string `json:"uploaded_by_name"`
+		UploadedByEmail string `json:"uploaded_by_email"`
+		CreatedAt       string `json:"created_at"`
+	}
+
+	DocumentDownloadLinkResponse struct {
+		URL       string `json:"url"`
+		ExpiresAt string `json:"expires_at"`
 	}
 )
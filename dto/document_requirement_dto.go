@@ -0,0 +1,47 @@
+package dto
+
+const (
+	MESSAGE_DOCUMENT_REQUIREMENT_GET_ALL_SUCCESS = "Get all document requirements success"
+	MESSAGE_DOCUMENT_REQUIREMENT_GET_SUCCESS     = "Get document requirement success"
+	MESSAGE_DOCUMENT_REQUIREMENT_CREATE_SUCCESS  = "Create document requirement success"
+	MESSAGE_DOCUMENT_REQUIREMENT_UPDATE_SUCCESS  = "Update document requirement success"
+	MESSAGE_DOCUMENT_REQUIREMENT_DELETE_SUCCESS  = "Delete document requirement success"
+)
+
+type (
+	DocumentRequirementRequest struct {
+		ProgramTypeID    string   `json:"program_type_id"`
+		ActivityID       string   `json:"activity_id"`
+		DocumentType     string   `json:"document_type" binding:"required"`
+		Description      string   `json:"description"`
+		Required         bool     `json:"required"`
+		AllowedMimeTypes []string `json:"allowed_mime_types"`
+		MaxSizeBytes     int64    `json:"max_size_bytes" binding:"gte=0"`
+	}
+
+	FilterDocumentRequirementRequest struct {
+		ProgramTypeID string `form:"program_type_id"`
+		ActivityID    string `form:"activity_id"`
+	}
+
+	DocumentRequirementResponse struct {
+		ID               string   `json:"id"`
+		ProgramTypeID    string   `json:"program_type_id"`
+		ActivityID       string   `json:"activity_id"`
+		DocumentType     string   `json:"document_type"`
+		Description      string   `json:"description"`
+		Required         bool     `json:"required"`
+		AllowedMimeTypes []string `json:"allowed_mime_types"`
+		MaxSizeBytes     int64    `json:"max_size_bytes"`
+	}
+
+	DocumentCompletenessResponse struct {
+		Complete bool     `json:"complete"`
+		Required []string `json:"required"`
+		Missing  []string `json:"missing"`
+		// Accepted is true once every required document has been accepted by a reviewer
+		Accepted      bool     `json:"accepted"`
+		PendingReview []string `json:"pending_review"`
+		NeedsRevision []string `json:"needs_revision"`
+	}
+)
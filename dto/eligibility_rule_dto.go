@@ -0,0 +1,46 @@
+package dto
+
+const (
+	MESSAGE_ELIGIBILITY_RULE_GET_ALL_SUCCESS = "Get all eligibility rules success"
+	MESSAGE_ELIGIBILITY_RULE_GET_SUCCESS     = "Get eligibility rule success"
+	MESSAGE_ELIGIBILITY_RULE_CREATE_SUCCESS  = "Create eligibility rule success"
+	MESSAGE_ELIGIBILITY_RULE_UPDATE_SUCCESS  = "Update eligibility rule success"
+	MESSAGE_ELIGIBILITY_RULE_DELETE_SUCCESS  = "Delete eligibility rule success"
+)
+
+type (
+	EligibilityRuleRequest struct {
+		ProgramTypeID string `json:"program_type_id"`
+		LevelID       string `json:"level_id"`
+		Rule          string `json:"rule" binding:"required"`
+		Value         int    `json:"value" binding:"gte=0"`
+		Severity      string `json:"severity" binding:"omitempty,oneof=FAIL WARN"`
+		Enabled       *bool  `json:"enabled"`
+		Description   string `json:"description"`
+	}
+
+	FilterEligibilityRuleRequest struct {
+		ProgramTypeID string `form:"program_type_id"`
+		LevelID       string `form:"level_id"`
+		Rule          string `form:"rule"`
+	}
+
+	EligibilityRuleResponse struct {
+		ID            string `json:"id"`
+		ProgramTypeID string `json:"program_type_id"`
+		LevelID       string `json:"level_id"`
+		Rule          string `json:"rule"`
+		Value         int    `json:"value"`
+		Severity      string `json:"severity"`
+		Enabled       bool   `json:"enabled"`
+		Description   string `json:"description"`
+	}
+
+	// EligibilityRuleResultResponse is the outcome of one rule for a student
+	// and an activity, Result is PASS, WARN or FAIL
+	EligibilityRuleResultResponse struct {
+		Rule   string `json:"rule"`
+		Result string `json:"result"`
+		Reason string `json:"reason"`
+	}
+)
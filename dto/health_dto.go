@@ -0,0 +1,16 @@
+package dto
+
+const (
+	MESSAGE_HEALTH_LIVE      = "Service is alive"
+	MESSAGE_HEALTH_READY     = "Service is ready"
+	MESSAGE_HEALTH_NOT_READY = "Service is not ready"
+)
+
+type (
+	HealthResponse struct {
+		Database              string `json:"database"`
+		SchemaVersion         int64  `json:"schema_version"`
+		ExpectedSchemaVersion int64  `json:"expected_schema_version"`
+		Error                 string `json:"error,omitempty"`
+	}
+)
@@ -1,5 +1,10 @@
 package dto
 
+import (
+	"net/url"
+	"time"
+)
+
 type (
 	PaginationResponse struct {
 		CurrentPage  int    `json:"current_page"`
@@ -12,11 +17,27 @@ type (
 		To           int    `json:"to"`
 		Total        int64  `json:"total"`
 		TotalPages   int64  `json:"total_pages"`
+		// set for cursor pagination only
+		*ResponseMeta
 	}
 
 	PaginationRequest struct {
 		URL    string `json:"url"`
 		Offset int    `json:"offset"`
 		Limit  int    `json:"limit"`
+		// Keyset switches to cursor pagination on (created_at, id), Offset is
+		// ignored and After or Before holds the decoded cursor
+		Keyset    bool    `json:"-"`
+		After     *Cursor `json:"-"`
+		Before    *Cursor `json:"-"`
+		WithTotal bool    `json:"-"`
+		// Query is the query of the request, the cursor links keep it
+		Query url.Values `json:"-"`
+	}
+
+	// Cursor points at the row a keyset page starts after or ends before
+	Cursor struct {
+		CreatedAt time.Time `json:"t"`
+		ID        string    `json:"id"`
 	}
 )
@@ -0,0 +1,21 @@
+package dto
+
+import "encoding/json"
+
+// Optional is a field of a PATCH request. Set tells whether the field was
+// sent at all, Null whether it was sent as null to clear it.
+type Optional[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	if string(data) == "null" {
+		o.Null = true
+		return nil
+	}
+
+	return json.Unmarshal(data, &o.Value)
+}
@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 const (
 	MESSAGE_REGISTRATION_GET_ALL_SUCCESS     = "Get all activities success"
 	MESSAGE_REGISTRATION_GET_SUCCESS         = "Get registration success"
@@ -11,39 +13,130 @@ const (
 	MESSAGE_REGISTRATION_MATCHING_SUCCESS    = "Get registration with matching success"
 	MESSAGE_REGISTRATION_ELIGIBILITY_SUCCESS = "Check registration eligibility success"
 	MESSAGE_REGISTRATION_GET_TOTAL_SUCCESS   = "Get total registration success"
+	MESSAGE_REGISTRATION_WITHDRAW_SUCCESS    = "Request registration withdrawal success"
+	MESSAGE_REGISTRATION_WITHDRAWAL_SUCCESS  = "Update registration withdrawal success"
+	MESSAGE_REGISTRATION_WITHDRAWAL_ERROR    = "Update registration withdrawal failed"
+	MESSAGE_REGISTRATION_HISTORY_SUCCESS     = "Get registration history success"
+	MESSAGE_REGISTRATION_DRAFT_SUCCESS       = "Create registration draft success"
+	MESSAGE_REGISTRATION_SUBMIT_SUCCESS      = "Submit registration success"
+	MESSAGE_REGISTRATION_IMPORT_SUCCESS      = "Import registration success"
 )
 
 type (
 	GetRegistrationResponse struct {
-		ID                        string             `json:"id"`
-		ActivityID                string             `json:"activity_id"`
-		UserID                    string             `json:"user_id"`
-		UserNRP                   string             `json:"user_nrp"`
-		UserName                  string             `json:"user_name"`
-		AdvisingConfirmation      bool               `json:"advising_confirmation"`
-		AcademicAdvisor           string             `json:"academic_advisor"`
-		AcademicAdvisorEmail      string             `json:"academic_advisor_email"`
-		MentorName                string             `json:"mentor_name"`
-		MentorEmail               string             `json:"mentor_email"`
-		LOValidation              string             `json:"lo_validation"`
-		AcademicAdvisorValidation string             `json:"academic_advisor_validation"`
-		Semester                  int                `json:"semester"`
-		TotalSKS                  int                `json:"total_sks"`
-		ActivityName              string             `json:"activity_name"`
-		ApprovalStatus            bool               `json:"approval_status"`
-		Documents                 []DocumentResponse `json:"documents"`
-		Equivalents               interface{}        `json:"equivalents"`
-		Matching                  interface{}        `json:"matching"`
+		ID                        string                        `json:"id"`
+		ActivityID                string                        `json:"activity_id"`
+		UserID                    string                        `json:"user_id"`
+		UserNRP                   string                        `json:"user_nrp"`
+		UserName                  string                        `json:"user_name"`
+		AdvisingConfirmation      bool                          `json:"advising_confirmation"`
+		AcademicAdvisor           string                        `json:"academic_advisor"`
+		AcademicAdvisorEmail      string                        `json:"academic_advisor_email"`
+		MentorName                string                        `json:"mentor_name"`
+		MentorEmail               string                        `json:"mentor_email"`
+		LOValidation              string                        `json:"lo_validation"`
+		AcademicAdvisorValidation string                        `json:"academic_advisor_validation"`
+		Semester                  int                           `json:"semester"`
+		TotalSKS                  int                           `json:"total_sks"`
+		ActivityName              string                        `json:"activity_name"`
+		AcademicPeriodID          string                        `json:"academic_period_id"`
+		ApprovalStatus            bool                          `json:"approval_status"`
+		Status                    string                        `json:"status"`
+		WithdrawalReason          string                        `json:"withdrawal_reason"`
+		LockVersion               int64                         `json:"lock_version"`
+		Documents                 []DocumentResponse            `json:"documents"`
+		DocumentCompleteness      *DocumentCompletenessResponse `json:"document_completeness"`
+		AcademicRecord            *AcademicRecordResponse       `json:"academic_record"`
+		Equivalents               interface{}                   `json:"equivalents"`
+		Matching                  interface{}                   `json:"matching"`
+	}
+
+	// AcademicRecordResponse tells reviewers how the semester and SKS of a
+	// registration compare with the student's academic record
+	AcademicRecordResponse struct {
+		Status           string     `json:"status"`
+		ReportedSemester int        `json:"reported_semester"`
+		ReportedTotalSKS int        `json:"reported_total_sks"`
+		RecordSemester   int        `json:"record_semester"`
+		RecordTotalSKS   int        `json:"record_total_sks"`
+		CheckedAt        *time.Time `json:"checked_at"`
 	}
 
+	// FilterRegistrationRequest narrows a registration listing. Name fields
+	// match partially and case-insensitively, UserNRP and AcademicAdvisorEmail
+	// stay exact because the services use them to scope a listing to its owner.
 	FilterRegistrationRequest struct {
 		ActivityName              string `json:"activity_name"`
 		UserName                  string `json:"user_name"`
 		UserNRP                   string `json:"user_nrp"`
+		UserNRPLike               string `json:"user_nrp_like"`
+		AcademicAdvisor           string `json:"academic_advisor"`
 		AcademicAdvisorEmail      string `json:"academic_advisor_email"`
-		ApprovalStatus            bool   `json:"approval_status"`
+		MentorName                string `json:"mentor_name"`
+		ApprovalStatus            *bool  `json:"approval_status"`
+		AdvisingConfirmation      *bool  `json:"advising_confirmation"`
 		LOValidation              string `json:"lo_validation"`
 		AcademicAdvisorValidation string `json:"academic_advisor_validation"`
+		Status                    string `json:"status"`
+		AcademicPeriodID          string `json:"academic_period_id"`
+		AcademicRecordStatus      string `json:"academic_record_status"`
+
+		ActivityIDs                []string `json:"activity_ids"`
+		ProgramTypeIDs             []string `json:"program_type_ids"`
+		UserNRPs                   []string `json:"user_nrps"`
+		Statuses                   []string `json:"statuses"`
+		LOValidations              []string `json:"lo_validations"`
+		AcademicAdvisorValidations []string `json:"academic_advisor_validations"`
+
+		// AcademicAdvisorEmails scopes an advisor listing to the advisor and
+		// the advisors they currently stand in for, it is set by the service
+		AcademicAdvisorEmails []string `json:"-"`
+
+		CreatedFrom *time.Time `json:"created_from"`
+		CreatedTo   *time.Time `json:"created_to"`
+		SemesterMin *int       `json:"semester_min" binding:"omitempty,min=0"`
+		SemesterMax *int       `json:"semester_max" binding:"omitempty,min=0"`
+		TotalSKSMin *int       `json:"total_sks_min" binding:"omitempty,min=0"`
+		TotalSKSMax *int       `json:"total_sks_max" binding:"omitempty,min=0"`
+
+		// Search is a full-text query over the activity, student, advisor
+		// and mentor names
+		Search        string `json:"search"`
+		SortBy        string `json:"sort_by" binding:"omitempty,oneof=created_at updated_at activity_name user_name user_nrp academic_advisor semester total_sks status approval_status relevance"`
+		SortDirection string `json:"sort_direction" binding:"omitempty,oneof=asc desc ASC DESC"`
+
+		// IncludeDraft is set by the service for the owner's own listing only
+		IncludeDraft bool `json:"-"`
+	}
+
+	// RegistrationExportRequest is read from the query string of the listing
+	// endpoints, an empty Format keeps the paginated JSON response
+	RegistrationExportRequest struct {
+		Format  string   `form:"export" binding:"omitempty,oneof=csv xlsx"`
+		Columns []string `form:"columns"`
+		Lang    string   `form:"lang" binding:"omitempty,oneof=en id"`
+	}
+
+	RegistrationImportRequest struct {
+		DryRun bool `form:"dry_run"`
+	}
+
+	RegistrationImportRowResponse struct {
+		Row            int      `json:"row"`
+		UserNRP        string   `json:"user_nrp"`
+		ActivityID     string   `json:"activity_id"`
+		Valid          bool     `json:"valid"`
+		Reasons        []string `json:"reasons,omitempty"`
+		RegistrationID string   `json:"registration_id,omitempty"`
+	}
+
+	RegistrationImportResponse struct {
+		DryRun      bool                            `json:"dry_run"`
+		TotalRows   int                             `json:"total_rows"`
+		ValidRows   int                             `json:"valid_rows"`
+		InvalidRows int                             `json:"invalid_rows"`
+		Created     int                             `json:"created"`
+		Rows        []RegistrationImportRowResponse `json:"rows"`
 	}
 
 	FilterDataRequest struct {
@@ -55,31 +148,83 @@ type (
 		AcademicAdvisorValidation string   `json:"academic_advisor_validation"`
 	}
 
+	// CreateRegistrationRequest leaves the advisor fields to the service, they
+	// may be empty when the advisor is filled in from user management
 	CreateRegistrationRequest struct {
 		ActivityID           string `form:"activity_id" binding:"required"`
-		AcademicAdvisorID    string `form:"academic_advisor_id" binding:"required"`
+		AcademicAdvisorID    string `form:"academic_advisor_id"`
 		AdvisingConfirmation bool   `form:"advising_confirmation" binding:"required"`
-		AcademicAdvisor      string `form:"academic_advisor" binding:"required"` // This field doesn't match what's in your form
-		AcademicAdvisorEmail string `form:"academic_advisor_email" binding:"required"`
+		AcademicAdvisor      string `form:"academic_advisor"` // This field doesn't match what's in your form
+		AcademicAdvisorEmail string `form:"academic_advisor_email"`
 		MentorName           string `form:"mentor_name" binding:"required"`
 		MentorEmail          string `form:"mentor_email" binding:"required"`
 		Semester             int    `form:"semester" binding:"required"`
 		TotalSKS             int    `form:"total_sks" binding:"required"`
 	}
 
+	CreateDraftRegistrationRequest struct {
+		ActivityID           string `json:"activity_id" form:"activity_id" binding:"required"`
+		AcademicAdvisorID    string `json:"academic_advisor_id" form:"academic_advisor_id"`
+		AdvisingConfirmation bool   `json:"advising_confirmation" form:"advising_confirmation"`
+		AcademicAdvisor      string `json:"academic_advisor" form:"academic_advisor"`
+		AcademicAdvisorEmail string `json:"academic_advisor_email" form:"academic_advisor_email"`
+		MentorName           string `json:"mentor_name" form:"mentor_name"`
+		MentorEmail          string `json:"mentor_email" form:"mentor_email"`
+		Semester             int    `json:"semester" form:"semester"`
+		TotalSKS             int    `json:"total_sks" form:"total_sks"`
+	}
+
+	// ApprovalRequest carries the lock version from If-Match, it can only be
+	// sent for a single registration
 	ApprovalRequest struct {
-		Status string   `json:"status" binding:"required"`
-		ID     []string `json:"id" binding:"required"`
+		Status      string   `json:"status" binding:"required"`
+		ID          []string `json:"id" binding:"required"`
+		LockVersion *int64   `json:"-"`
+	}
+
+	WithdrawalRequest struct {
+		Reason string `json:"reason" binding:"required"`
+	}
+
+	RegistrationHistoryResponse struct {
+		ID             string `json:"id"`
+		RegistrationID string `json:"registration_id"`
+		Action         string `json:"action"`
+		FromStatus     string `json:"from_status"`
+		ToStatus       string `json:"to_status"`
+		ActorID        string `json:"actor_id"`
+		ActorName      string `json:"actor_name"`
+		ActorEmail     string `json:"actor_email"`
+		ActorRole      string `json:"actor_role"`
+		Note           string `json:"note"`
+		CreatedAt      string `json:"created_at"`
 	}
 
 	UpdateRegistrationDataRequest struct {
 		AdvisingConfirmation bool   `json:"advising_confirmation"`
+		AcademicAdvisorID    string `json:"academic_advisor_id"`
 		AcademicAdvisor      string `json:"academic_advisor"`
 		AcademicAdvisorEmail string `json:"academic_advisor_email"`
 		MentorName           string `json:"mentor_name"`
 		MentorEmail          string `json:"mentor_email"`
 		Semester             int    `json:"semester"`
 		TotalSKS             int    `json:"total_sks"`
+		LockVersion          *int64 `json:"-"`
+	}
+
+	// PatchRegistrationRequest only changes the fields it carries, null
+	// clears a field. Which fields may change depends on the role and the
+	// state of the registration. LockVersion is taken from If-Match.
+	PatchRegistrationRequest struct {
+		AdvisingConfirmation Optional[bool]   `json:"advising_confirmation"`
+		AcademicAdvisorID    Optional[string] `json:"academic_advisor_id"`
+		AcademicAdvisor      Optional[string] `json:"academic_advisor"`
+		AcademicAdvisorEmail Optional[string] `json:"academic_advisor_email"`
+		MentorName           Optional[string] `json:"mentor_name"`
+		MentorEmail          Optional[string] `json:"mentor_email"`
+		Semester             Optional[int]    `json:"semester"`
+		TotalSKS             Optional[int]    `json:"total_sks"`
+		LockVersion          *int64           `json:"-"`
 	}
 
 	TranscriptResponse struct {
@@ -133,25 +278,30 @@ type (
 	}
 
 	StudentRegistrationWithMatchingResponse struct {
-		ID                        string             `json:"id"`
-		ActivityID                string             `json:"activity_id"`
-		UserID                    string             `json:"user_id"`
-		UserNRP                   string             `json:"user_nrp"`
-		UserName                  string             `json:"user_name"`
-		AdvisingConfirmation      bool               `json:"advising_confirmation"`
-		AcademicAdvisor           string             `json:"academic_advisor"`
-		AcademicAdvisorEmail      string             `json:"academic_advisor_email"`
-		MentorName                string             `json:"mentor_name"`
-		MentorEmail               string             `json:"mentor_email"`
-		LOValidation              string             `json:"lo_validation"`
-		AcademicAdvisorValidation string             `json:"academic_advisor_validation"`
-		Semester                  int                `json:"semester"`
-		TotalSKS                  int                `json:"total_sks"`
-		ActivityName              string             `json:"activity_name"`
-		ApprovalStatus            bool               `json:"approval_status"`
-		Documents                 []DocumentResponse `json:"documents"`
-		Equivalents               interface{}        `json:"equivalents"`
-		Matching                  interface{}        `json:"matching"`
+		ID                        string                        `json:"id"`
+		ActivityID                string                        `json:"activity_id"`
+		UserID                    string                        `json:"user_id"`
+		UserNRP                   string                        `json:"user_nrp"`
+		UserName                  string                        `json:"user_name"`
+		AdvisingConfirmation      bool                          `json:"advising_confirmation"`
+		AcademicAdvisor           string                        `json:"academic_advisor"`
+		AcademicAdvisorEmail      string                        `json:"academic_advisor_email"`
+		MentorName                string                        `json:"mentor_name"`
+		MentorEmail               string                        `json:"mentor_email"`
+		LOValidation              string                        `json:"lo_validation"`
+		AcademicAdvisorValidation string                        `json:"academic_advisor_validation"`
+		Semester                  int                           `json:"semester"`
+		TotalSKS                  int                           `json:"total_sks"`
+		ActivityName              string                        `json:"activity_name"`
+		ApprovalStatus            bool                          `json:"approval_status"`
+		Status                    string                        `json:"status"`
+		WithdrawalReason          string                        `json:"withdrawal_reason"`
+		LockVersion               int64                         `json:"lock_version"`
+		Documents                 []DocumentResponse            `json:"documents"`
+		DocumentCompleteness      *DocumentCompletenessResponse `json:"document_completeness"`
+		AcademicRecord            *AcademicRecordResponse       `json:"academic_record"`
+		Equivalents               interface{}                   `json:"equivalents"`
+		Matching                  interface{}                   `json:"matching"`
 	}
 
 	StudentRegistrationsWithMatchingResponse struct {
@@ -162,7 +312,23 @@ type (
 	}
 
 	RegistrationEligibilityResponse struct {
-		Eligible bool   `json:"eligible"`
-		Message  string `json:"message"`
+		Eligible  bool                            `json:"eligible"`
+		Message   string                          `json:"message"`
+		Rules     []EligibilityRuleResultResponse `json:"rules"`
+		Conflicts []RegistrationConflictResponse  `json:"conflicts,omitempty"`
+		// AcademicPeriod is the period the activity starts in, nil when no
+		// period covers its start
+		AcademicPeriod *AcademicPeriodResponse `json:"academic_period"`
+	}
+
+	// RegistrationConflictResponse is an active registration of the student
+	// whose activity period overlaps the activity being registered for
+	RegistrationConflictResponse struct {
+		RegistrationID string    `json:"registration_id"`
+		ActivityID     string    `json:"activity_id"`
+		ActivityName   string    `json:"activity_name"`
+		Status         string    `json:"status"`
+		StartPeriod    time.Time `json:"start_period"`
+		EndPeriod      time.Time `json:"end_period"`
 	}
 )
@@ -0,0 +1,37 @@
+package dto
+
+import "time"
+
+const (
+	MESSAGE_REGISTRATION_TRASH_GET_ALL_SUCCESS = "Get deleted registrations success"
+	MESSAGE_REGISTRATION_TRASH_GET_SUCCESS     = "Get deleted registration success"
+	MESSAGE_REGISTRATION_RESTORE_SUCCESS       = "Restore registration success"
+)
+
+type (
+	// DeletedRegistrationResponse is a registration in the trash, PurgeAt is
+	// when the purge job removes it for good
+	DeletedRegistrationResponse struct {
+		ID                        string             `json:"id"`
+		ActivityID                string             `json:"activity_id"`
+		ActivityName              string             `json:"activity_name"`
+		UserID                    string             `json:"user_id"`
+		UserNRP                   string             `json:"user_nrp"`
+		UserName                  string             `json:"user_name"`
+		AcademicAdvisor           string             `json:"academic_advisor"`
+		AcademicAdvisorEmail      string             `json:"academic_advisor_email"`
+		Status                    string             `json:"status"`
+		LOValidation              string             `json:"lo_validation"`
+		AcademicAdvisorValidation string             `json:"academic_advisor_validation"`
+		Documents                 []DocumentResponse `json:"documents"`
+		DeletedAt                 time.Time          `json:"deleted_at"`
+		PurgeAt                   time.Time          `json:"purge_at"`
+	}
+
+	// RegistrationPurgeResult is what a purge run did, registrations whose
+	// files couldn't be deleted are left in the trash for the next run
+	RegistrationPurgeResult struct {
+		Purged  []string `json:"purged"`
+		Skipped []string `json:"skipped"`
+	}
+)
@@ -0,0 +1,53 @@
+package dto
+
+import "time"
+
+const (
+	MESSAGE_RETENTION_REPORT_SUCCESS = "Get retention report success"
+	MESSAGE_RETENTION_RUN_SUCCESS    = "Run retention success"
+
+	// RETENTION_STATUS_DUE lists what the next run anonymizes,
+	// RETENTION_STATUS_ANONYMIZED what earlier runs did
+	RETENTION_STATUS_DUE        = "due"
+	RETENTION_STATUS_ANONYMIZED = "anonymized"
+)
+
+type (
+	RetentionReportRequest struct {
+		Status string `form:"status"`
+	}
+
+	RetentionRunRequest struct {
+		DryRun bool `form:"dry_run"`
+	}
+
+	// RetentionReportItem leaves out every personal field, DueAt is when the
+	// registration is or was due for anonymization
+	RetentionReportItem struct {
+		RegistrationID   string     `json:"registration_id"`
+		ActivityID       string     `json:"activity_id"`
+		ActivityName     string     `json:"activity_name"`
+		AcademicPeriodID string     `json:"academic_period_id"`
+		Status           string     `json:"status"`
+		DocumentCount    int64      `json:"document_count"`
+		RetainedFrom     time.Time  `json:"retained_from"`
+		DueAt            time.Time  `json:"due_at"`
+		AnonymizedAt     *time.Time `json:"anonymized_at"`
+	}
+
+	RetentionReportResponse struct {
+		Status         string                `json:"status"`
+		RetentionYears int64                 `json:"retention_years"`
+		Cutoff         time.Time             `json:"cutoff"`
+		Registrations  []RetentionReportItem `json:"registrations"`
+	}
+
+	// RetentionRunResponse lists the registrations a run anonymized, with
+	// DryRun the ones it would have anonymized
+	RetentionRunResponse struct {
+		DryRun     bool                  `json:"dry_run"`
+		Cutoff     time.Time             `json:"cutoff"`
+		Anonymized []RetentionReportItem `json:"anonymized"`
+		Skipped    []string              `json:"skipped"`
+	}
+)
@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ACADEMIC_TERM_ODD  = "ODD"
+	ACADEMIC_TERM_EVEN = "EVEN"
+)
+
+type (
+	// AcademicPeriod is one term of an academic year, e.g. the odd term of
+	// 2024/2025. A registration belongs to the period its activity starts in.
+	AcademicPeriod struct {
+		ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+		Year      string    `json:"year" gorm:"not null"`
+		Term      string    `json:"term" gorm:"not null"`
+		StartDate time.Time `json:"start_date" gorm:"not null"`
+		EndDate   time.Time `json:"end_date" gorm:"not null"`
+		// MaxRegistrationsPerStudent limits the registrations a student holds
+		// in the period, 0 means no limit
+		MaxRegistrationsPerStudent int `json:"max_registrations_per_student" gorm:"not null;default:0"`
+		BaseModel
+	}
+)
@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// AdvisorDelegation lets a substitute approve on behalf of an academic
+	// advisor between StartsAt and EndsAt, deleting it revokes the delegation
+	AdvisorDelegation struct {
+		ID            uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+		AdvisorEmail  string    `json:"advisor_email" gorm:"not null;index"`
+		AdvisorName   string    `json:"advisor_name"`
+		DelegateEmail string    `json:"delegate_email" gorm:"not null;index"`
+		DelegateName  string    `json:"delegate_name"`
+		StartsAt      time.Time `json:"starts_at" gorm:"not null"`
+		EndsAt        time.Time `json:"ends_at" gorm:"not null"`
+		Reason        string    `json:"reason"`
+		CreatedByID   string    `json:"created_by_id"`
+		CreatedByName string    `json:"created_by_name"`
+		BaseModel
+	}
+)
@@ -1,14 +1,38 @@
 package entity
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DOCUMENT_TYPE_ACCEPTANCE_LETTER = "Acceptence Letter"
+	DOCUMENT_TYPE_GEOLETTER         = "Geoletter"
+
+	DOCUMENT_REVIEW_PENDING        = "PENDING"
+	DOCUMENT_REVIEW_ACCEPTED       = "ACCEPTED"
+	DOCUMENT_REVIEW_NEEDS_REVISION = "NEEDS_REVISION"
+)
 
 type (
 	Document struct {
-		ID             uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
-		RegistrationID string    `json:"registration_id" gorm:"not null"`
-		FileStorageID  string    `json:"file_storage_id" gorm:"not null;unique"`
-		Name           string    `json:"name" gorm:"not null"`
-		DocumentType   string    `json:"document_type" gorm:"not null"`
+		ID             uuid.UUID  `json:"id" gorm:"type:uuid;primary_key"`
+		RegistrationID string     `json:"registration_id" gorm:"not null"`
+		FileStorageID  string     `json:"file_storage_id" gorm:"not null;unique"`
+		Name           string     `json:"name" gorm:"not null"`
+		DocumentType   string     `json:"document_type" gorm:"not null"`
+		Checksum       string     `json:"checksum" gorm:"size:64"`
+		ContentType    string     `json:"content_type"`
+		Size           int64      `json:"size"`
+		CurrentVersion int        `json:"current_version" gorm:"not null;default:1"`
+		ReviewStatus   string     `json:"review_status" gorm:"not null;default:'PENDING'"`
+		ReviewerID     string     `json:"reviewer_id"`
+		ReviewerName   string     `json:"reviewer_name"`
+		ReviewerEmail  string     `json:"reviewer_email"`
+		ReviewComment  string     `json:"review_comment"`
+		ReviewedAt     *time.Time `json:"reviewed_at"`
+		LockVersion    int64      `json:"lock_version" gorm:"not null;default:1"`
 		Registration   *Registration
 		BaseModel
 	}
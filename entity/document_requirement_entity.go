@@ -0,0 +1,22 @@
+package entity
+
+import "github.com/google/uuid"
+
+type (
+	// DocumentRequirement is one entry of the document checklist. An entry with
+	// an ActivityID applies to that activity only, an entry with only a
+	// ProgramTypeID applies to every activity of the program type and an entry
+	// with neither is the default checklist.
+	DocumentRequirement struct {
+		ID            uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+		ProgramTypeID string    `json:"program_type_id" gorm:"index"`
+		ActivityID    string    `json:"activity_id" gorm:"index"`
+		DocumentType  string    `json:"document_type" gorm:"not null"`
+		Description   string    `json:"description"`
+		Required      bool      `json:"required" gorm:"not null"`
+		// AllowedMimeTypes is a comma separated list, empty means the upload defaults
+		AllowedMimeTypes string `json:"allowed_mime_types"`
+		MaxSizeBytes     int64  `json:"max_size_bytes"`
+		BaseModel
+	}
+)
@@ -0,0 +1,22 @@
+package entity
+
+import "github.com/google/uuid"
+
+type (
+	// DocumentVersion keeps every file uploaded for a document, the newest
+	// version is the one referenced by Document.FileStorageID.
+	DocumentVersion struct {
+		ID              uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+		DocumentID      string    `json:"document_id" gorm:"not null;index"`
+		Version         int       `json:"version" gorm:"not null"`
+		FileStorageID   string    `json:"file_storage_id" gorm:"not null"`
+		Name            string    `json:"name"`
+		Checksum        string    `json:"checksum" gorm:"size:64"`
+		ContentType     string    `json:"content_type"`
+		Size            int64     `json:"size"`
+		UploadedByID    string    `json:"uploaded_by_id"`
+		UploadedByName  string    `json:"uploaded_by_name"`
+		UploadedByEmail string    `json:"uploaded_by_email"`
+		BaseModel
+	}
+)
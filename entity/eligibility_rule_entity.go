@@ -0,0 +1,33 @@
+package entity
+
+import "github.com/google/uuid"
+
+const (
+	ELIGIBILITY_RULE_MIN_SEMESTER             = "min_semester"
+	ELIGIBILITY_RULE_MIN_TOTAL_SKS            = "min_total_sks"
+	ELIGIBILITY_RULE_MAX_CONCURRENT_PROGRAMS  = "max_concurrent_programs"
+	ELIGIBILITY_RULE_ADVISOR_APPROVAL_HISTORY = "advisor_approval_history"
+
+	ELIGIBILITY_RESULT_PASS = "PASS"
+	ELIGIBILITY_RESULT_WARN = "WARN"
+	ELIGIBILITY_RESULT_FAIL = "FAIL"
+)
+
+type (
+	// EligibilityRule configures one registered rule. A rule with a
+	// ProgramTypeID and or a LevelID applies to those activities only, the
+	// most specific entry of a rule wins so a scope can also switch a default
+	// rule off. Severity is the result given when the rule isn't met, FAIL
+	// blocks the registration and WARN only reports it.
+	EligibilityRule struct {
+		ID            uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+		ProgramTypeID string    `json:"program_type_id" gorm:"index"`
+		LevelID       string    `json:"level_id" gorm:"index"`
+		Rule          string    `json:"rule" gorm:"not null"`
+		Value         int       `json:"value" gorm:"not null"`
+		Severity      string    `json:"severity" gorm:"not null;default:'FAIL'"`
+		Enabled       bool      `json:"enabled" gorm:"not null;default:true"`
+		Description   string    `json:"description"`
+		BaseModel
+	}
+)
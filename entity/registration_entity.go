@@ -1,27 +1,64 @@
 package entity
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	REGISTRATION_STATUS_DRAFT                = "DRAFT"
+	REGISTRATION_STATUS_ACTIVE               = "ACTIVE"
+	REGISTRATION_STATUS_WITHDRAWAL_REQUESTED = "WITHDRAWAL_REQUESTED"
+	REGISTRATION_STATUS_WITHDRAWN            = "WITHDRAWN"
+)
+
+// outcome of comparing the semester and SKS a student typed in with the
+// academic record, CORRECTED means the record values replaced the typed ones
+const (
+	ACADEMIC_RECORD_STATUS_VERIFIED    = "VERIFIED"
+	ACADEMIC_RECORD_STATUS_MISMATCH    = "MISMATCH"
+	ACADEMIC_RECORD_STATUS_CORRECTED   = "CORRECTED"
+	ACADEMIC_RECORD_STATUS_UNAVAILABLE = "UNAVAILABLE"
+)
 
 type (
 	Registration struct {
-		ID                        uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
-		ActivityID                string    `json:"activity_id" gorm:"not null"`
-		ActivityName              string    `json:"activity_name" gorm:"not null"`
-		UserID                    string    `json:"user_id" gorm:"not null"`
-		UserName                  string    `json:"user_name" gorm:"not null"`
-		UserNRP                   string    `json:"user_nrp" gorm:"not null"`
-		AdvisingConfirmation      bool      `json:"advising_confirmation" gorm:"not null"`
-		AcademicAdvisorID         string    `json:"academic_advisor_id" gorm:"not null"`
-		AcademicAdvisor           string    `json:"academic_advisor" gorm:"not null"`
-		AcademicAdvisorEmail      string    `json:"academic_advisor_email" gorm:"not null"`
-		MentorName                string    `json:"mentor_name" gorm:"not null"`
-		MentorEmail               string    `json:"mentor_email" gorm:"not null"`
-		LOValidation              string    `json:"lo_validation" gorm:"not null"`
-		AcademicAdvisorValidation string    `json:"academic_advisor_validation" gorm:"not null"`
-		Semester                  int       `json:"semester" gorm:"not null"`
-		TotalSKS                  int       `json:"total_sks" gorm:"not null"`
-		ApprovalStatus            bool      `json:"approval_status" gorm:"not null"`
-		Document                  []Document
+		ID                          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key"`
+		ActivityID                  string     `json:"activity_id" gorm:"not null"`
+		ActivityName                string     `json:"activity_name" gorm:"not null"`
+		ProgramTypeID               string     `json:"program_type_id"`
+		AcademicPeriodID            string     `json:"academic_period_id" gorm:"index"`
+		UserID                      string     `json:"user_id" gorm:"not null"`
+		UserName                    string     `json:"user_name" gorm:"not null"`
+		UserNRP                     string     `json:"user_nrp" gorm:"not null"`
+		AdvisingConfirmation        bool       `json:"advising_confirmation" gorm:"not null"`
+		AcademicAdvisorID           string     `json:"academic_advisor_id" gorm:"not null"`
+		AcademicAdvisor             string     `json:"academic_advisor" gorm:"not null"`
+		AcademicAdvisorEmail        string     `json:"academic_advisor_email" gorm:"not null"`
+		MentorName                  string     `json:"mentor_name" gorm:"not null"`
+		MentorEmail                 string     `json:"mentor_email" gorm:"not null"`
+		LOValidation                string     `json:"lo_validation" gorm:"not null"`
+		AcademicAdvisorValidation   string     `json:"academic_advisor_validation" gorm:"not null"`
+		Semester                    int        `json:"semester" gorm:"not null"`
+		TotalSKS                    int        `json:"total_sks" gorm:"not null"`
+		ReportedSemester            int        `json:"reported_semester"`
+		ReportedTotalSKS            int        `json:"reported_total_sks"`
+		RecordSemester              int        `json:"record_semester"`
+		RecordTotalSKS              int        `json:"record_total_sks"`
+		AcademicRecordStatus        string     `json:"academic_record_status" gorm:"index"`
+		AcademicRecordCheckedAt     *time.Time `json:"academic_record_checked_at"`
+		ApprovalStatus              bool       `json:"approval_status" gorm:"not null"`
+		Status                      string     `json:"status" gorm:"not null;default:'ACTIVE'"`
+		WithdrawalReason            string     `json:"withdrawal_reason"`
+		WithdrawalAdvisorValidation string     `json:"withdrawal_advisor_validation"`
+		WithdrawalLOValidation      string     `json:"withdrawal_lo_validation"`
+		WithdrawalRequestedAt       *time.Time `json:"withdrawal_requested_at"`
+		StatusBeforeWithdrawal      string     `json:"status_before_withdrawal"`
+		WithdrawnAt                 *time.Time `json:"withdrawn_at"`
+		AnonymizedAt                *time.Time `json:"anonymized_at"`
+		LockVersion                 int64      `json:"lock_version" gorm:"not null;default:1"`
+		Document                    []Document
 		BaseModel
 	}
 
@@ -0,0 +1,35 @@
+package entity
+
+import "github.com/google/uuid"
+
+const (
+	REGISTRATION_HISTORY_DRAFT_CREATED        = "DRAFT_CREATED"
+	REGISTRATION_HISTORY_SUBMITTED            = "SUBMITTED"
+	REGISTRATION_HISTORY_WITHDRAWAL_REQUESTED = "WITHDRAWAL_REQUESTED"
+	REGISTRATION_HISTORY_WITHDRAWAL_APPROVED  = "WITHDRAWAL_APPROVED"
+	REGISTRATION_HISTORY_WITHDRAWAL_REJECTED  = "WITHDRAWAL_REJECTED"
+	REGISTRATION_HISTORY_WITHDRAWN            = "WITHDRAWN"
+	REGISTRATION_HISTORY_IMPORTED             = "IMPORTED"
+	REGISTRATION_HISTORY_ADVISOR_REASSIGNED   = "ADVISOR_REASSIGNED"
+	REGISTRATION_HISTORY_DELEGATED_APPROVAL   = "DELEGATED_APPROVAL"
+	REGISTRATION_HISTORY_UPDATED              = "UPDATED"
+	REGISTRATION_HISTORY_APPROVAL_REOPENED    = "APPROVAL_REOPENED"
+	REGISTRATION_HISTORY_DELETED              = "DELETED"
+	REGISTRATION_HISTORY_RESTORED             = "RESTORED"
+)
+
+type (
+	RegistrationHistory struct {
+		ID             uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+		RegistrationID string    `json:"registration_id" gorm:"not null;index"`
+		Action         string    `json:"action" gorm:"not null"`
+		FromStatus     string    `json:"from_status"`
+		ToStatus       string    `json:"to_status"`
+		ActorID        string    `json:"actor_id"`
+		ActorName      string    `json:"actor_name"`
+		ActorEmail     string    `json:"actor_email"`
+		ActorRole      string    `json:"actor_role"`
+		Note           string    `json:"note"`
+		BaseModel
+	}
+)
@@ -0,0 +1,172 @@
+package helper
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"registration-service/dto"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+var errCursorDisabled = errors.New("cursor pagination is not configured")
+
+var cursorSigningKey []byte
+
+// SetCursorSigningKey sets the key cursors are signed with, a cursor signed
+// with another key is rejected. Without a key cursor pagination is refused,
+// a guessable key would let clients forge positions.
+func SetCursorSigningKey(key string) {
+	cursorSigningKey = []byte(key)
+}
+
+// EncodeCursor returns an opaque cursor, the payload is signed so a client
+// can't forge a position
+func EncodeCursor(cursor dto.Cursor) string {
+	payload, _ := json.Marshal(cursor)
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+
+	return encoded + "." + cursorSignature(encoded)
+}
+
+func DecodeCursor(value string) (dto.Cursor, error) {
+	if len(cursorSigningKey) == 0 {
+		return dto.Cursor{}, errCursorDisabled
+	}
+
+	encoded, signature, found := strings.Cut(value, ".")
+	if !found || !hmac.Equal([]byte(signature), []byte(cursorSignature(encoded))) {
+		return dto.Cursor{}, errInvalidCursor
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return dto.Cursor{}, errInvalidCursor
+	}
+
+	var cursor dto.Cursor
+	err = json.Unmarshal(payload, &cursor)
+	if err != nil || cursor.ID == "" || cursor.CreatedAt.IsZero() {
+		return dto.Cursor{}, errInvalidCursor
+	}
+
+	return cursor, nil
+}
+
+func cursorSignature(encoded string) string {
+	mac := hmac.New(sha256.New, cursorSigningKey)
+	mac.Write([]byte(encoded))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// CursorPagination reads the after or before cursor on top of page/limit. An
+// empty after parameter asks for the first page in cursor mode, without
+// either parameter the request stays on page/limit.
+func CursorPagination(ctx *gin.Context) (dto.PaginationRequest, error) {
+	// Pagination clears the query of the request URL
+	query := ctx.Request.URL.Query()
+	pagReq := Pagination(ctx)
+	pagReq.Query = query
+	if pagReq.Limit <= 0 {
+		pagReq.Limit = 10
+	}
+
+	pagReq.WithTotal, _ = strconv.ParseBool(ctx.DefaultQuery("with_total", "false"))
+
+	after, hasAfter := ctx.GetQuery("after")
+	before, hasBefore := ctx.GetQuery("before")
+	if !hasAfter && !hasBefore {
+		return pagReq, nil
+	}
+
+	if len(cursorSigningKey) == 0 {
+		return dto.PaginationRequest{}, errCursorDisabled
+	}
+
+	if after != "" && before != "" {
+		return dto.PaginationRequest{}, errors.New("after and before can't be used together")
+	}
+
+	pagReq.Keyset = true
+	pagReq.Offset = 0
+
+	if after != "" {
+		cursor, err := DecodeCursor(after)
+		if err != nil {
+			return dto.PaginationRequest{}, err
+		}
+		pagReq.After = &cursor
+	}
+
+	if before != "" {
+		cursor, err := DecodeCursor(before)
+		if err != nil {
+			return dto.PaginationRequest{}, err
+		}
+		pagReq.Before = &cursor
+	}
+
+	return pagReq, nil
+}
+
+// CursorMetaData describes a keyset page. first and last are the cursors of
+// the first and last row of the page and hasMore tells whether rows are left
+// in the direction the page was read. Total is only counted on request.
+func CursorMetaData(pagReq dto.PaginationRequest, first *dto.Cursor, last *dto.Cursor, hasMore bool, total int64) dto.PaginationResponse {
+	hasNext := hasMore
+	hasPrev := pagReq.After != nil
+	if pagReq.Before != nil {
+		hasNext = true
+		hasPrev = hasMore
+	}
+
+	meta := &dto.ResponseMeta{}
+	metaData := dto.PaginationResponse{
+		PerPage:      pagReq.Limit,
+		ResponseMeta: meta,
+	}
+
+	// a row without created_at gives no cursor, DecodeCursor would refuse it
+	if hasNext && last != nil && !last.CreatedAt.IsZero() {
+		cursor := EncodeCursor(*last)
+		meta.AfterCursor = &cursor
+		metaData.NextPageUrl = cursorPageURL(pagReq, "after", cursor)
+	}
+
+	if hasPrev && first != nil && !first.CreatedAt.IsZero() {
+		cursor := EncodeCursor(*first)
+		meta.BeforeCursor = &cursor
+		metaData.PrevPageUrl = cursorPageURL(pagReq, "before", cursor)
+	}
+
+	if pagReq.WithTotal {
+		metaData.Total = total
+		metaData.TotalPages = TotalPages(pagReq.Limit, total)
+	}
+
+	return metaData
+}
+
+// cursorPageURL links to the page at the cursor, the query of the request is
+// kept so sort, filters and with_total carry over
+func cursorPageURL(pagReq dto.PaginationRequest, param string, cursor string) string {
+	query := url.Values{}
+	for key, values := range pagReq.Query {
+		query[key] = append([]string(nil), values...)
+	}
+	query.Del("after")
+	query.Del("before")
+	query.Del("page")
+	query.Set(param, cursor)
+	query.Set("limit", strconv.Itoa(pagReq.Limit))
+
+	return pagReq.URL + "?" + query.Encode()
+}
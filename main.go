@@ -1,11 +1,14 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
 	"registration-service/config"
 	localConfig "registration-service/config"
 	"registration-service/helper"
 	"registration-service/middleware"
+	"registration-service/migration"
 	"registration-service/routes"
 	"registration-service/service"
 	"strconv"
@@ -37,22 +40,124 @@ func main() {
 
 	db := localConfig.SetupDatabaseConnection()
 
-	config, err := storageService.LoadConfig()
+	migrator, err := migration.NewMigrator(db)
 	if err != nil {
 		helper.PanicIfError(err)
 	}
 
-	cache := storageService.NewMemoryCache()
+	// "main migrate up|down [steps]|version" only touches the schema
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		defer localConfig.CloseDatabaseConnection(db)
+		err = migration.Command(context.Background(), migrator, os.Args[2:])
+		if err != nil {
+			log.Fatalln(err)
+		}
+		return
+	}
+
+	if cfg.Migration.OnStartup {
+		applied, err := migrator.Up(context.Background())
+		if err != nil {
+			helper.PanicIfError(err)
+		}
+
+		for _, version := range applied {
+			log.Println("Applied migration", version)
+		}
+	}
+
+	helper.SetCursorSigningKey(cfg.CursorSigningKey)
+	if cfg.CursorSigningKey == "" {
+		log.Println("CURSOR_SIGNING_KEY is not set, cursor pagination is disabled")
+	}
+
+	// the file storage service is only needed when documents are kept in gcs
+	var config *storageService.Config
+	var tokenManager *storageService.CacheTokenManager
+	if cfg.Storage.Driver == service.STORAGE_DRIVER_GCS {
+		config, err = storageService.LoadConfig()
+		if err != nil {
+			helper.PanicIfError(err)
+		}
+
+		cache := storageService.NewMemoryCache()
+
+		tokenManager = storageService.NewCacheTokenManager(config, cache)
+	}
+
+	registrationController, err := InitializeRegistration(db, localConfig.SecretKey(secretKeyService), localConfig.UserManagementbaseURI(userManagementServiceURI), localConfig.ActivityManagementbaseURI(activityManagementServiceURI), localConfig.MatchingManagementbaseURI(matchingManagementServiceURI), localConfig.MonitoringManagementbaseURI(monitoringManagementServiceURI), localConfig.BrokerbaseURI(brokerbaseURI), []string{"/async"}, cfg.Upload, cfg.Download, cfg.Storage, cfg.Export, cfg.Import, cfg.AcademicRecord, cfg.Advisor, cfg.RegistrationEdit, config, tokenManager)
+
+	if err != nil {
+		helper.PanicIfError(err)
+	}
+
+	documentController, err := InitializeDocument(db, cfg.Upload, cfg.Download, cfg.Storage, localConfig.UserManagementbaseURI(userManagementServiceURI), localConfig.BrokerbaseURI(brokerbaseURI), []string{"/async"}, config, tokenManager)
+
+	if err != nil {
+		helper.PanicIfError(err)
+	}
+
+	documentRequirementController, err := InitializeDocumentRequirement(db)
+
+	if err != nil {
+		helper.PanicIfError(err)
+	}
 
-	tokenManager := storageService.NewCacheTokenManager(config, cache)
+	eligibilityRuleController, err := InitializeEligibilityRule(db)
 
-	registrationController, err := InitializeRegistration(db, localConfig.SecretKey(secretKeyService), localConfig.UserManagementbaseURI(userManagementServiceURI), localConfig.ActivityManagementbaseURI(activityManagementServiceURI), localConfig.MatchingManagementbaseURI(matchingManagementServiceURI), localConfig.MonitoringManagementbaseURI(monitoringManagementServiceURI), localConfig.BrokerbaseURI(brokerbaseURI), []string{"/async"}, config, tokenManager)
+	if err != nil {
+		helper.PanicIfError(err)
+	}
+
+	academicPeriodController, err := InitializeAcademicPeriod(db)
 
 	if err != nil {
 		helper.PanicIfError(err)
 	}
 
-	documentController, err := InitializeDocument(db, config, tokenManager)
+	advisorDelegationController, err := InitializeAdvisorDelegation(db, localConfig.UserManagementbaseURI(userManagementServiceURI), []string{"/async"})
+
+	if err != nil {
+		helper.PanicIfError(err)
+	}
+
+	registrationTrashController, err := InitializeRegistrationTrash(db, cfg.Storage, cfg.Purge, localConfig.UserManagementbaseURI(userManagementServiceURI), localConfig.ActivityManagementbaseURI(activityManagementServiceURI), []string{"/async"}, config, tokenManager)
+
+	if err != nil {
+		helper.PanicIfError(err)
+	}
+
+	// deleted registrations are only removed for good by the purge job
+	if cfg.Purge.Enabled {
+		purgeJob, err := InitializeRegistrationPurgeJob(db, cfg.Storage, cfg.Purge, localConfig.UserManagementbaseURI(userManagementServiceURI), localConfig.ActivityManagementbaseURI(activityManagementServiceURI), []string{"/async"}, config, tokenManager)
+		if err != nil {
+			helper.PanicIfError(err)
+		}
+
+		purgeJob.Start(context.Background())
+	}
+
+	retentionController, err := InitializeRetention(db, cfg.Storage, cfg.Retention, config, tokenManager)
+
+	if err != nil {
+		helper.PanicIfError(err)
+	}
+
+	// anonymizing is off unless enabled, RETENTION_DRY_RUN only logs
+	if cfg.Retention.Enabled {
+		if cfg.Retention.PseudonymKey == "" {
+			log.Println("RETENTION_PSEUDONYM_KEY is not set, the retention job is not started")
+		} else {
+			retentionJob, err := InitializeRetentionJob(db, cfg.Storage, cfg.Retention, config, tokenManager)
+			if err != nil {
+				helper.PanicIfError(err)
+			}
+
+			retentionJob.Start(context.Background())
+		}
+	}
+
+	healthController, err := InitializeHealth(db, migrator)
 
 	if err != nil {
 		helper.PanicIfError(err)
@@ -71,11 +176,23 @@ func main() {
 
 	server := localConfig.NewServer()
 	server.Use(middleware.CORS())
+	routes.HealthRoutes(server, healthController)
+	if cfg.Download.SigningKey != "" {
+		routes.SignedDocumentRoutes(server, documentController)
+	} else {
+		log.Println("DOWNLOAD_SIGNING_KEY is not set, signed download links are disabled")
+	}
 	server.Use(securityMiddleware.AccessKeyMiddleware(secretKeyService, expireSeconds, &frontendConfig))
 
 	userService := service.NewUserManagementService(userManagementServiceURI, []string{"/async"})
 
 	routes.RegistrationRoutes(server, registrationController, *userService)
-	routes.DocumentRoutes(server, documentController)
+	routes.DocumentRoutes(server, documentController, *userService)
+	routes.DocumentRequirementRoutes(server, documentRequirementController, *userService)
+	routes.EligibilityRuleRoutes(server, eligibilityRuleController, *userService)
+	routes.AcademicPeriodRoutes(server, academicPeriodController, *userService)
+	routes.AdvisorDelegationRoutes(server, advisorDelegationController, *userService)
+	routes.RegistrationTrashRoutes(server, registrationTrashController, *userService)
+	routes.RetentionRoutes(server, retentionController, *userService)
 	server.Run(":" + port)
 }
@@ -0,0 +1,58 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+)
+
+// Command runs the migrate subcommand, args are what follows "migrate":
+// up, down [steps] or version
+func Command(ctx context.Context, m *Migrator, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: migrate up | down [steps] | version")
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := m.Up(ctx)
+		for _, version := range applied {
+			log.Println("Applied migration", version)
+		}
+		if err != nil {
+			return err
+		}
+
+		if len(applied) == 0 {
+			log.Println("Schema is up to date at version", m.Latest())
+		}
+		return nil
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			var err error
+			steps, err = strconv.Atoi(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid steps %s", args[1])
+			}
+		}
+
+		reverted, err := m.Down(ctx, steps)
+		for _, version := range reverted {
+			log.Println("Reverted migration", version)
+		}
+		return err
+	case "version":
+		version, err := m.Version(ctx)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("Schema version %d, latest migration %d\n", version, m.Latest())
+		return nil
+	default:
+		return fmt.Errorf("unknown migrate command %s", args[0])
+	}
+}
@@ -0,0 +1,283 @@
+package migration
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed sql/*.sql
+var files embed.FS
+
+const (
+	MIGRATION_TABLE = "schema_migrations"
+
+	// every runner takes the same transaction level advisory lock so two
+	// instances starting together apply each migration only once
+	migrationLockKey = 7318240591
+)
+
+// Migration is one versioned schema change read from sql/, the files are named
+// <version>_<name>.up.sql and <version>_<name>.down.sql
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// SchemaMigration is a row of the schema_migrations table, one per applied migration
+type SchemaMigration struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (SchemaMigration) TableName() string {
+	return MIGRATION_TABLE
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	migrations, err := load(files)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+func load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, "sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		fileName := entry.Name()
+
+		var direction string
+		switch {
+		case strings.HasSuffix(fileName, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(fileName, ".down.sql"):
+			direction = "down"
+		default:
+			return nil, fmt.Errorf("migration %s is neither an up nor a down file", fileName)
+		}
+
+		base := strings.TrimSuffix(fileName, "."+direction+".sql")
+		versionText, name, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %s has no name", fileName)
+		}
+
+		version, err := strconv.ParseInt(versionText, 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s has an invalid version", fileName)
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join("sql", fileName))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		}
+
+		if migration.Name != name {
+			return nil, fmt.Errorf("migration version %d is used by %s and %s", version, migration.Name, name)
+		}
+
+		if direction == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Latest is the version of the newest embedded migration, the version the
+// schema is expected to have
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	return m.db.WithContext(ctx).Exec(`CREATE TABLE IF NOT EXISTS ` + MIGRATION_TABLE + ` (
+		version bigint PRIMARY KEY,
+		name text NOT NULL,
+		applied_at timestamptz NOT NULL
+	)`).Error
+}
+
+// Version returns the newest applied migration, 0 when none is applied or the
+// migration table doesn't exist yet
+func (m *Migrator) Version(ctx context.Context) (int64, error) {
+	var exists bool
+	err := m.db.WithContext(ctx).Raw("SELECT to_regclass(?) IS NOT NULL", MIGRATION_TABLE).Scan(&exists).Error
+	if err != nil {
+		return 0, err
+	}
+
+	if !exists {
+		return 0, nil
+	}
+
+	return currentVersion(m.db.WithContext(ctx))
+}
+
+func currentVersion(tx *gorm.DB) (int64, error) {
+	var version int64
+	err := tx.Model(&SchemaMigration{}).Select("COALESCE(MAX(version), 0)").Scan(&version).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return version, nil
+}
+
+// Up applies every pending migration in order, each one in its own
+// transaction, and returns the versions it applied
+func (m *Migrator) Up(ctx context.Context) ([]int64, error) {
+	err := m.ensureTable(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []int64
+	for _, migration := range m.migrations {
+		migration := migration
+		ran := false
+		err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLockKey).Error
+			if err != nil {
+				return err
+			}
+
+			var count int64
+			err = tx.Model(&SchemaMigration{}).Where("version = ?", migration.Version).Count(&count).Error
+			if err != nil {
+				return err
+			}
+
+			if count > 0 {
+				return nil
+			}
+
+			err = tx.Exec(migration.Up).Error
+			if err != nil {
+				return err
+			}
+
+			ran = true
+			return tx.Create(&SchemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return applied, fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+
+		if ran {
+			applied = append(applied, migration.Version)
+		}
+	}
+
+	return applied, nil
+}
+
+// Down reverts the newest steps applied migrations and returns the versions it reverted
+func (m *Migrator) Down(ctx context.Context, steps int) ([]int64, error) {
+	if steps <= 0 {
+		return nil, errors.New("steps must be positive")
+	}
+
+	err := m.ensureTable(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var reverted []int64
+	for i := 0; i < steps; i++ {
+		var version int64
+		var name string
+		err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLockKey).Error
+			if err != nil {
+				return err
+			}
+
+			version, err = currentVersion(tx)
+			if err != nil || version == 0 {
+				return err
+			}
+
+			migration, ok := m.find(version)
+			if !ok {
+				return fmt.Errorf("applied migration %d is not known to this build", version)
+			}
+			name = migration.Name
+
+			err = tx.Exec(migration.Down).Error
+			if err != nil {
+				return err
+			}
+
+			return tx.Where("version = ?", version).Delete(&SchemaMigration{}).Error
+		})
+		if err != nil {
+			return reverted, fmt.Errorf("migration %d_%s: %w", version, name, err)
+		}
+
+		if version == 0 {
+			break
+		}
+		reverted = append(reverted, version)
+	}
+
+	return reverted, nil
+}
+
+func (m *Migrator) find(version int64) (Migration, bool) {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration, true
+		}
+	}
+
+	return Migration{}, false
+}
@@ -0,0 +1,33 @@
+-- the statements are idempotent so environments whose schema was created by
+-- hand can adopt the migrations without being rebuilt
+CREATE TABLE IF NOT EXISTS registrations (
+    id uuid PRIMARY KEY,
+    activity_id text NOT NULL,
+    activity_name text NOT NULL,
+    program_type_id text,
+    user_id text NOT NULL,
+    user_name text NOT NULL,
+    user_nrp text NOT NULL,
+    advising_confirmation boolean NOT NULL,
+    academic_advisor_id text NOT NULL,
+    academic_advisor text NOT NULL,
+    academic_advisor_email text NOT NULL,
+    mentor_name text NOT NULL,
+    mentor_email text NOT NULL,
+    lo_validation text NOT NULL,
+    academic_advisor_validation text NOT NULL,
+    semester bigint NOT NULL,
+    total_sks bigint NOT NULL,
+    approval_status boolean NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+
+ALTER TABLE registrations ADD COLUMN IF NOT EXISTS program_type_id text;
+ALTER TABLE registrations ADD COLUMN IF NOT EXISTS status text NOT NULL DEFAULT 'ACTIVE';
+ALTER TABLE registrations ADD COLUMN IF NOT EXISTS withdrawal_reason text;
+ALTER TABLE registrations ADD COLUMN IF NOT EXISTS withdrawal_advisor_validation text;
+ALTER TABLE registrations ADD COLUMN IF NOT EXISTS withdrawal_lo_validation text;
+ALTER TABLE registrations ADD COLUMN IF NOT EXISTS withdrawal_requested_at timestamptz;
+ALTER TABLE registrations ADD COLUMN IF NOT EXISTS withdrawn_at timestamptz;
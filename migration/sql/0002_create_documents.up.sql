@@ -0,0 +1,45 @@
+CREATE TABLE IF NOT EXISTS documents (
+    id uuid PRIMARY KEY,
+    registration_id text NOT NULL,
+    file_storage_id text NOT NULL,
+    name text NOT NULL,
+    document_type text NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS checksum varchar(64);
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS content_type text;
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS size bigint;
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS current_version bigint NOT NULL DEFAULT 1;
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS review_status text NOT NULL DEFAULT 'PENDING';
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS reviewer_id text;
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS reviewer_name text;
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS reviewer_email text;
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS review_comment text;
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS reviewed_at timestamptz;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_documents_file_storage_id ON documents (file_storage_id);
+CREATE INDEX IF NOT EXISTS idx_documents_registration_id ON documents (registration_id);
+CREATE INDEX IF NOT EXISTS idx_documents_created_at ON documents (created_at DESC, id DESC);
+
+CREATE TABLE IF NOT EXISTS document_versions (
+    id uuid PRIMARY KEY,
+    document_id text NOT NULL,
+    version bigint NOT NULL,
+    file_storage_id text NOT NULL,
+    name text,
+    checksum varchar(64),
+    content_type text,
+    size bigint,
+    uploaded_by_id text,
+    uploaded_by_name text,
+    uploaded_by_email text,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS idx_document_versions_document_id ON document_versions (document_id);
+CREATE INDEX IF NOT EXISTS idx_document_versions_created_at ON document_versions (created_at);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS registration_histories (
+    id uuid PRIMARY KEY,
+    registration_id text NOT NULL,
+    action text NOT NULL,
+    from_status text,
+    to_status text,
+    actor_id text,
+    actor_name text,
+    actor_email text,
+    actor_role text,
+    note text,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS idx_registration_histories_registration_id ON registration_histories (registration_id);
+CREATE INDEX IF NOT EXISTS idx_registration_histories_created_at ON registration_histories (created_at);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS document_requirements (
+    id uuid PRIMARY KEY,
+    program_type_id text,
+    activity_id text,
+    document_type text NOT NULL,
+    description text,
+    required boolean NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+
+ALTER TABLE document_requirements ADD COLUMN IF NOT EXISTS allowed_mime_types text;
+ALTER TABLE document_requirements ADD COLUMN IF NOT EXISTS max_size_bytes bigint;
+
+CREATE INDEX IF NOT EXISTS idx_document_requirements_program_type_id ON document_requirements (program_type_id);
+CREATE INDEX IF NOT EXISTS idx_document_requirements_activity_id ON document_requirements (activity_id);
+CREATE INDEX IF NOT EXISTS idx_document_requirements_created_at ON document_requirements (created_at);
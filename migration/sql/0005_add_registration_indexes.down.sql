@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_registrations_search_vector;
+DROP INDEX IF EXISTS idx_registrations_created_at;
+DROP INDEX IF EXISTS idx_registrations_activity_id_user_nrp;
+DROP INDEX IF EXISTS idx_registrations_deleted_at;
+DROP INDEX IF EXISTS idx_registrations_activity_id;
+DROP INDEX IF EXISTS idx_registrations_academic_advisor_email;
+DROP INDEX IF EXISTS idx_registrations_user_nrp;
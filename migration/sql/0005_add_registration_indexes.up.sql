@@ -0,0 +1,16 @@
+CREATE INDEX IF NOT EXISTS idx_registrations_user_nrp ON registrations (user_nrp);
+CREATE INDEX IF NOT EXISTS idx_registrations_academic_advisor_email ON registrations (academic_advisor_email);
+CREATE INDEX IF NOT EXISTS idx_registrations_activity_id ON registrations (activity_id);
+CREATE INDEX IF NOT EXISTS idx_registrations_deleted_at ON registrations (deleted_at);
+
+-- a student registers once per activity, soft deleted rows don't count.
+-- Duplicates already in the table have to be cleaned up before this applies.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_registrations_activity_id_user_nrp ON registrations (activity_id, user_nrp) WHERE deleted_at IS NULL;
+
+-- serves the default created_at ordering and the keyset pagination
+CREATE INDEX IF NOT EXISTS idx_registrations_created_at ON registrations (created_at DESC, id DESC);
+
+-- must stay identical to REGISTRATION_SEARCH_VECTOR for the planner to use it
+CREATE INDEX IF NOT EXISTS idx_registrations_search_vector ON registrations USING gin (
+    to_tsvector('simple', coalesce(activity_name, '') || ' ' || coalesce(user_name, '') || ' ' || coalesce(academic_advisor, '') || ' ' || coalesce(mentor_name, ''))
+);
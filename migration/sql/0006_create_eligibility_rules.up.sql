@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS eligibility_rules (
+    id uuid PRIMARY KEY,
+    program_type_id text,
+    level_id text,
+    rule text NOT NULL,
+    value bigint NOT NULL,
+    severity text NOT NULL DEFAULT 'FAIL',
+    enabled boolean NOT NULL DEFAULT true,
+    description text,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS idx_eligibility_rules_program_type_id ON eligibility_rules (program_type_id);
+CREATE INDEX IF NOT EXISTS idx_eligibility_rules_level_id ON eligibility_rules (level_id);
+CREATE INDEX IF NOT EXISTS idx_eligibility_rules_created_at ON eligibility_rules (created_at);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_registrations_academic_period_id;
+ALTER TABLE registrations DROP COLUMN IF EXISTS academic_period_id;
+DROP TABLE IF EXISTS academic_periods;
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS academic_periods (
+    id uuid PRIMARY KEY,
+    year text NOT NULL,
+    term text NOT NULL,
+    start_date timestamptz NOT NULL,
+    end_date timestamptz NOT NULL,
+    max_registrations_per_student bigint NOT NULL DEFAULT 0,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_academic_periods_year_term ON academic_periods (year, term) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_academic_periods_start_date_end_date ON academic_periods (start_date, end_date);
+CREATE INDEX IF NOT EXISTS idx_academic_periods_created_at ON academic_periods (created_at);
+
+ALTER TABLE registrations ADD COLUMN IF NOT EXISTS academic_period_id text;
+CREATE INDEX IF NOT EXISTS idx_registrations_academic_period_id ON registrations (academic_period_id);
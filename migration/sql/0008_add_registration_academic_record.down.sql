@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_registrations_academic_record_status;
+ALTER TABLE registrations DROP COLUMN IF EXISTS academic_record_checked_at;
+ALTER TABLE registrations DROP COLUMN IF EXISTS academic_record_status;
+ALTER TABLE registrations DROP COLUMN IF EXISTS record_total_sks;
+ALTER TABLE registrations DROP COLUMN IF EXISTS record_semester;
+ALTER TABLE registrations DROP COLUMN IF EXISTS reported_total_sks;
+ALTER TABLE registrations DROP COLUMN IF EXISTS reported_semester;
@@ -0,0 +1,8 @@
+ALTER TABLE registrations ADD COLUMN IF NOT EXISTS reported_semester bigint NOT NULL DEFAULT 0;
+ALTER TABLE registrations ADD COLUMN IF NOT EXISTS reported_total_sks bigint NOT NULL DEFAULT 0;
+ALTER TABLE registrations ADD COLUMN IF NOT EXISTS record_semester bigint NOT NULL DEFAULT 0;
+ALTER TABLE registrations ADD COLUMN IF NOT EXISTS record_total_sks bigint NOT NULL DEFAULT 0;
+ALTER TABLE registrations ADD COLUMN IF NOT EXISTS academic_record_status text NOT NULL DEFAULT '';
+ALTER TABLE registrations ADD COLUMN IF NOT EXISTS academic_record_checked_at timestamptz;
+
+CREATE INDEX IF NOT EXISTS idx_registrations_academic_record_status ON registrations (academic_record_status);
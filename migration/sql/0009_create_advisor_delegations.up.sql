@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS advisor_delegations (
+    id uuid PRIMARY KEY,
+    advisor_email text NOT NULL,
+    advisor_name text,
+    delegate_email text NOT NULL,
+    delegate_name text,
+    starts_at timestamptz NOT NULL,
+    ends_at timestamptz NOT NULL,
+    reason text,
+    created_by_id text,
+    created_by_name text,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS idx_advisor_delegations_advisor_email ON advisor_delegations (advisor_email);
+CREATE INDEX IF NOT EXISTS idx_advisor_delegations_delegate_email ON advisor_delegations (delegate_email);
+CREATE INDEX IF NOT EXISTS idx_advisor_delegations_created_at ON advisor_delegations (created_at);
@@ -0,0 +1,2 @@
+ALTER TABLE documents DROP COLUMN IF EXISTS lock_version;
+ALTER TABLE registrations DROP COLUMN IF EXISTS lock_version;
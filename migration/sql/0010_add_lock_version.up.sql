@@ -0,0 +1,4 @@
+-- lock_version is bumped by every update, an update carrying an older value
+-- matches no row and is rejected as stale
+ALTER TABLE registrations ADD COLUMN IF NOT EXISTS lock_version bigint NOT NULL DEFAULT 1;
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS lock_version bigint NOT NULL DEFAULT 1;
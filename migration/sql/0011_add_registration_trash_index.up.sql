@@ -0,0 +1,2 @@
+-- the trash view and the purge job only read deleted registrations
+CREATE INDEX IF NOT EXISTS idx_registrations_trashed_at ON registrations (deleted_at, id) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_registrations_anonymized_at;
+ALTER TABLE registrations DROP COLUMN IF EXISTS anonymized_at;
@@ -0,0 +1,4 @@
+-- set once the personal fields of a registration were replaced by pseudonyms
+ALTER TABLE registrations ADD COLUMN IF NOT EXISTS anonymized_at timestamptz;
+
+CREATE INDEX IF NOT EXISTS idx_registrations_anonymized_at ON registrations (anonymized_at);
@@ -0,0 +1 @@
+ALTER TABLE registrations DROP COLUMN IF EXISTS status_before_withdrawal;
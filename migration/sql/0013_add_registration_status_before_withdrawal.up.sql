@@ -0,0 +1,2 @@
+-- the status a withdrawal request came from, a rejected request goes back to it
+ALTER TABLE registrations ADD COLUMN IF NOT EXISTS status_before_withdrawal text;
@@ -0,0 +1,58 @@
+package repository_mock
+
+import (
+	"context"
+	"registration-service/dto"
+	"registration-service/entity"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockAcademicPeriodRepository is a mock implementation of repository.AcademicPeriodRepository
+type MockAcademicPeriodRepository struct {
+	mock.Mock
+}
+
+// Index mocks the Index method
+func (m *MockAcademicPeriodRepository) Index(ctx context.Context, filter dto.FilterAcademicPeriodRequest, tx *gorm.DB) ([]entity.AcademicPeriod, error) {
+	args := m.Called(ctx, filter, tx)
+	return args.Get(0).([]entity.AcademicPeriod), args.Error(1)
+}
+
+// FindByID mocks the FindByID method
+func (m *MockAcademicPeriodRepository) FindByID(ctx context.Context, id string, tx *gorm.DB) (entity.AcademicPeriod, error) {
+	args := m.Called(ctx, id, tx)
+	return args.Get(0).(entity.AcademicPeriod), args.Error(1)
+}
+
+// FindByDate mocks the FindByDate method
+func (m *MockAcademicPeriodRepository) FindByDate(ctx context.Context, date time.Time, tx *gorm.DB) (entity.AcademicPeriod, error) {
+	args := m.Called(ctx, date, tx)
+	return args.Get(0).(entity.AcademicPeriod), args.Error(1)
+}
+
+// FindOverlapping mocks the FindOverlapping method
+func (m *MockAcademicPeriodRepository) FindOverlapping(ctx context.Context, start time.Time, end time.Time, excludeID string, tx *gorm.DB) ([]entity.AcademicPeriod, error) {
+	args := m.Called(ctx, start, end, excludeID, tx)
+	return args.Get(0).([]entity.AcademicPeriod), args.Error(1)
+}
+
+// Create mocks the Create method
+func (m *MockAcademicPeriodRepository) Create(ctx context.Context, period entity.AcademicPeriod, tx *gorm.DB) (entity.AcademicPeriod, error) {
+	args := m.Called(ctx, period, tx)
+	return args.Get(0).(entity.AcademicPeriod), args.Error(1)
+}
+
+// Update mocks the Update method
+func (m *MockAcademicPeriodRepository) Update(ctx context.Context, id string, period entity.AcademicPeriod, tx *gorm.DB) error {
+	args := m.Called(ctx, id, period, tx)
+	return args.Error(0)
+}
+
+// Destroy mocks the Destroy method
+func (m *MockAcademicPeriodRepository) Destroy(ctx context.Context, id string, tx *gorm.DB) error {
+	args := m.Called(ctx, id, tx)
+	return args.Error(0)
+}
@@ -0,0 +1,46 @@
+package repository_mock
+
+import (
+	"context"
+	"registration-service/dto"
+	"registration-service/entity"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockAdvisorDelegationRepository is a mock implementation of repository.AdvisorDelegationRepository
+type MockAdvisorDelegationRepository struct {
+	mock.Mock
+}
+
+// Index mocks the Index method
+func (m *MockAdvisorDelegationRepository) Index(ctx context.Context, filter dto.FilterAdvisorDelegationRequest, tx *gorm.DB) ([]entity.AdvisorDelegation, error) {
+	args := m.Called(ctx, filter, tx)
+	return args.Get(0).([]entity.AdvisorDelegation), args.Error(1)
+}
+
+// FindByID mocks the FindByID method
+func (m *MockAdvisorDelegationRepository) FindByID(ctx context.Context, id string, tx *gorm.DB) (entity.AdvisorDelegation, error) {
+	args := m.Called(ctx, id, tx)
+	return args.Get(0).(entity.AdvisorDelegation), args.Error(1)
+}
+
+// FindActiveByDelegateEmail mocks the FindActiveByDelegateEmail method
+func (m *MockAdvisorDelegationRepository) FindActiveByDelegateEmail(ctx context.Context, email string, at time.Time, tx *gorm.DB) ([]entity.AdvisorDelegation, error) {
+	args := m.Called(ctx, email, at, tx)
+	return args.Get(0).([]entity.AdvisorDelegation), args.Error(1)
+}
+
+// Create mocks the Create method
+func (m *MockAdvisorDelegationRepository) Create(ctx context.Context, delegation entity.AdvisorDelegation, tx *gorm.DB) (entity.AdvisorDelegation, error) {
+	args := m.Called(ctx, delegation, tx)
+	return args.Get(0).(entity.AdvisorDelegation), args.Error(1)
+}
+
+// Destroy mocks the Destroy method
+func (m *MockAdvisorDelegationRepository) Destroy(ctx context.Context, id string, tx *gorm.DB) error {
+	args := m.Called(ctx, id, tx)
+	return args.Error(0)
+}
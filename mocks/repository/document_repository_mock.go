@@ -20,6 +20,11 @@ func (m *MockDocumentRepository) Index(ctx context.Context, pagReq dto.Paginatio
 	return args.Get(0).([]entity.Document), args.Get(1).(int64), args.Error(2)
 }
 
+func (m *MockDocumentRepository) IndexKeyset(ctx context.Context, pagReq dto.PaginationRequest, tx *gorm.DB) ([]entity.Document, bool, int64, error) {
+	args := m.Called(ctx, pagReq, tx)
+	return args.Get(0).([]entity.Document), args.Bool(1), args.Get(2).(int64), args.Error(3)
+}
+
 // Create mocks the Create method
 func (m *MockDocumentRepository) Create(ctx context.Context, document entity.Document, tx *gorm.DB) (entity.Document, error) {
 	args := m.Called(ctx, document, tx)
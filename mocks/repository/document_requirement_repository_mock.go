@@ -0,0 +1,57 @@
+package repository_mock
+
+import (
+	"context"
+	"registration-service/dto"
+	"registration-service/entity"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockDocumentRequirementRepository is a mock implementation of repository.DocumentRequirementRepository
+type MockDocumentRequirementRepository struct {
+	mock.Mock
+}
+
+// Index mocks the Index method
+func (m *MockDocumentRequirementRepository) Index(ctx context.Context, filter dto.FilterDocumentRequirementRequest, tx *gorm.DB) ([]entity.DocumentRequirement, error) {
+	args := m.Called(ctx, filter, tx)
+	return args.Get(0).([]entity.DocumentRequirement), args.Error(1)
+}
+
+// FindByID mocks the FindByID method
+func (m *MockDocumentRequirementRepository) FindByID(ctx context.Context, id string, tx *gorm.DB) (entity.DocumentRequirement, error) {
+	args := m.Called(ctx, id, tx)
+	return args.Get(0).(entity.DocumentRequirement), args.Error(1)
+}
+
+// FindByScope mocks the FindByScope method
+func (m *MockDocumentRequirementRepository) FindByScope(ctx context.Context, activityID string, programTypeID string, tx *gorm.DB) ([]entity.DocumentRequirement, error) {
+	args := m.Called(ctx, activityID, programTypeID, tx)
+	return args.Get(0).([]entity.DocumentRequirement), args.Error(1)
+}
+
+// FindByScopes mocks the FindByScopes method
+func (m *MockDocumentRequirementRepository) FindByScopes(ctx context.Context, activityIDs []string, programTypeIDs []string, tx *gorm.DB) ([]entity.DocumentRequirement, error) {
+	args := m.Called(ctx, activityIDs, programTypeIDs, tx)
+	return args.Get(0).([]entity.DocumentRequirement), args.Error(1)
+}
+
+// Create mocks the Create method
+func (m *MockDocumentRequirementRepository) Create(ctx context.Context, requirement entity.DocumentRequirement, tx *gorm.DB) (entity.DocumentRequirement, error) {
+	args := m.Called(ctx, requirement, tx)
+	return args.Get(0).(entity.DocumentRequirement), args.Error(1)
+}
+
+// Update mocks the Update method
+func (m *MockDocumentRequirementRepository) Update(ctx context.Context, id string, requirement entity.DocumentRequirement, tx *gorm.DB) error {
+	args := m.Called(ctx, id, requirement, tx)
+	return args.Error(0)
+}
+
+// Destroy mocks the Destroy method
+func (m *MockDocumentRequirementRepository) Destroy(ctx context.Context, id string, tx *gorm.DB) error {
+	args := m.Called(ctx, id, tx)
+	return args.Error(0)
+}
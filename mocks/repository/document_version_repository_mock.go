@@ -0,0 +1,38 @@
+package repository_mock
+
+import (
+	"context"
+	"registration-service/entity"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockDocumentVersionRepository is a mock implementation of repository.DocumentVersionRepository
+type MockDocumentVersionRepository struct {
+	mock.Mock
+}
+
+// Create mocks the Create method
+func (m *MockDocumentVersionRepository) Create(ctx context.Context, version entity.DocumentVersion, tx *gorm.DB) (entity.DocumentVersion, error) {
+	args := m.Called(ctx, version, tx)
+	return args.Get(0).(entity.DocumentVersion), args.Error(1)
+}
+
+// FindByDocumentID mocks the FindByDocumentID method
+func (m *MockDocumentVersionRepository) FindByDocumentID(ctx context.Context, documentID string, tx *gorm.DB) ([]entity.DocumentVersion, error) {
+	args := m.Called(ctx, documentID, tx)
+	return args.Get(0).([]entity.DocumentVersion), args.Error(1)
+}
+
+// FindByDocumentIDAndVersion mocks the FindByDocumentIDAndVersion method
+func (m *MockDocumentVersionRepository) FindByDocumentIDAndVersion(ctx context.Context, documentID string, version int, tx *gorm.DB) (entity.DocumentVersion, error) {
+	args := m.Called(ctx, documentID, version, tx)
+	return args.Get(0).(entity.DocumentVersion), args.Error(1)
+}
+
+// DeleteByID mocks the DeleteByID method
+func (m *MockDocumentVersionRepository) DeleteByID(ctx context.Context, id string, tx *gorm.DB) error {
+	args := m.Called(ctx, id, tx)
+	return args.Error(0)
+}
@@ -0,0 +1,51 @@
+package repository_mock
+
+import (
+	"context"
+	"registration-service/dto"
+	"registration-service/entity"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockEligibilityRuleRepository is a mock implementation of repository.EligibilityRuleRepository
+type MockEligibilityRuleRepository struct {
+	mock.Mock
+}
+
+// Index mocks the Index method
+func (m *MockEligibilityRuleRepository) Index(ctx context.Context, filter dto.FilterEligibilityRuleRequest, tx *gorm.DB) ([]entity.EligibilityRule, error) {
+	args := m.Called(ctx, filter, tx)
+	return args.Get(0).([]entity.EligibilityRule), args.Error(1)
+}
+
+// FindByID mocks the FindByID method
+func (m *MockEligibilityRuleRepository) FindByID(ctx context.Context, id string, tx *gorm.DB) (entity.EligibilityRule, error) {
+	args := m.Called(ctx, id, tx)
+	return args.Get(0).(entity.EligibilityRule), args.Error(1)
+}
+
+// FindByScope mocks the FindByScope method
+func (m *MockEligibilityRuleRepository) FindByScope(ctx context.Context, programTypeID string, levelID string, tx *gorm.DB) ([]entity.EligibilityRule, error) {
+	args := m.Called(ctx, programTypeID, levelID, tx)
+	return args.Get(0).([]entity.EligibilityRule), args.Error(1)
+}
+
+// Create mocks the Create method
+func (m *MockEligibilityRuleRepository) Create(ctx context.Context, rule entity.EligibilityRule, tx *gorm.DB) (entity.EligibilityRule, error) {
+	args := m.Called(ctx, rule, tx)
+	return args.Get(0).(entity.EligibilityRule), args.Error(1)
+}
+
+// Update mocks the Update method
+func (m *MockEligibilityRuleRepository) Update(ctx context.Context, id string, rule entity.EligibilityRule, tx *gorm.DB) error {
+	args := m.Called(ctx, id, rule, tx)
+	return args.Error(0)
+}
+
+// Destroy mocks the Destroy method
+func (m *MockEligibilityRuleRepository) Destroy(ctx context.Context, id string, tx *gorm.DB) error {
+	args := m.Called(ctx, id, tx)
+	return args.Error(0)
+}
@@ -0,0 +1,26 @@
+package repository_mock
+
+import (
+	"context"
+	"registration-service/entity"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockRegistrationHistoryRepository is a mock implementation of repository.RegistrationHistoryRepository
+type MockRegistrationHistoryRepository struct {
+	mock.Mock
+}
+
+// Create mocks the Create method
+func (m *MockRegistrationHistoryRepository) Create(ctx context.Context, history entity.RegistrationHistory, tx *gorm.DB) (entity.RegistrationHistory, error) {
+	args := m.Called(ctx, history, tx)
+	return args.Get(0).(entity.RegistrationHistory), args.Error(1)
+}
+
+// FindByRegistrationID mocks the FindByRegistrationID method
+func (m *MockRegistrationHistoryRepository) FindByRegistrationID(ctx context.Context, registrationID string, tx *gorm.DB) ([]entity.RegistrationHistory, error) {
+	args := m.Called(ctx, registrationID, tx)
+	return args.Get(0).([]entity.RegistrationHistory), args.Error(1)
+}
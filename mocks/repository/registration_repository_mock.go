@@ -4,6 +4,7 @@ import (
 	"context"
 	"registration-service/dto"
 	"registration-service/entity"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	"gorm.io/gorm"
@@ -14,8 +15,8 @@ type MockRegistrationRepository struct {
 	mock.Mock
 }
 
-func (m *MockRegistrationRepository) FindTotalRegistrationByAdvisorEmail(ctx context.Context, email string, tx *gorm.DB) (entity.RegistrationCount, error) {
-	args := m.Called(ctx, email, tx)
+func (m *MockRegistrationRepository) FindTotalRegistrationByAdvisorEmail(ctx context.Context, email string, academicPeriodID string, tx *gorm.DB) (entity.RegistrationCount, error) {
+	args := m.Called(ctx, email, academicPeriodID, tx)
 	return args.Get(0).(entity.RegistrationCount), args.Error(1)
 }
 
@@ -25,6 +26,24 @@ func (m *MockRegistrationRepository) Index(ctx context.Context, tx *gorm.DB, pag
 	return args.Get(0).([]entity.Registration), args.Get(1).(int64), args.Error(2)
 }
 
+// IndexKeyset mocks the IndexKeyset method
+func (m *MockRegistrationRepository) IndexKeyset(ctx context.Context, tx *gorm.DB, pagReq dto.PaginationRequest, filter dto.FilterRegistrationRequest) ([]entity.Registration, bool, int64, error) {
+	args := m.Called(ctx, tx, pagReq, filter)
+	return args.Get(0).([]entity.Registration), args.Bool(1), args.Get(2).(int64), args.Error(3)
+}
+
+// IndexIDs mocks the IndexIDs method
+func (m *MockRegistrationRepository) IndexIDs(ctx context.Context, tx *gorm.DB, limit int, filter dto.FilterRegistrationRequest) ([]string, int64, error) {
+	args := m.Called(ctx, tx, limit, filter)
+	return args.Get(0).([]string), args.Get(1).(int64), args.Error(2)
+}
+
+// FindByIDs mocks the FindByIDs method
+func (m *MockRegistrationRepository) FindByIDs(ctx context.Context, ids []string, tx *gorm.DB) ([]entity.Registration, error) {
+	args := m.Called(ctx, ids, tx)
+	return args.Get(0).([]entity.Registration), args.Error(1)
+}
+
 // Create mocks the Create method
 func (m *MockRegistrationRepository) Create(ctx context.Context, registration entity.Registration, tx *gorm.DB) (entity.Registration, error) {
 	args := m.Called(ctx, registration, tx)
@@ -49,6 +68,48 @@ func (m *MockRegistrationRepository) Destroy(ctx context.Context, id string, tx
 	return args.Error(0)
 }
 
+// IndexDeleted mocks the IndexDeleted method
+func (m *MockRegistrationRepository) IndexDeleted(ctx context.Context, pagReq dto.PaginationRequest, tx *gorm.DB) ([]entity.Registration, int64, error) {
+	args := m.Called(ctx, pagReq, tx)
+	return args.Get(0).([]entity.Registration), args.Get(1).(int64), args.Error(2)
+}
+
+// FindDeletedByID mocks the FindDeletedByID method
+func (m *MockRegistrationRepository) FindDeletedByID(ctx context.Context, id string, tx *gorm.DB) (entity.Registration, error) {
+	args := m.Called(ctx, id, tx)
+	return args.Get(0).(entity.Registration), args.Error(1)
+}
+
+// FindDeletedBefore mocks the FindDeletedBefore method
+func (m *MockRegistrationRepository) FindDeletedBefore(ctx context.Context, before time.Time, after *dto.Cursor, limit int, tx *gorm.DB) ([]entity.Registration, error) {
+	args := m.Called(ctx, before, after, limit, tx)
+	return args.Get(0).([]entity.Registration), args.Error(1)
+}
+
+// LockDeleted mocks the LockDeleted method
+func (m *MockRegistrationRepository) LockDeleted(ctx context.Context, id string, tx *gorm.DB) (entity.Registration, error) {
+	args := m.Called(ctx, id, tx)
+	return args.Get(0).(entity.Registration), args.Error(1)
+}
+
+// Lock mocks the Lock method
+func (m *MockRegistrationRepository) Lock(ctx context.Context, id string, tx *gorm.DB) (entity.Registration, error) {
+	args := m.Called(ctx, id, tx)
+	return args.Get(0).(entity.Registration), args.Error(1)
+}
+
+// Restore mocks the Restore method
+func (m *MockRegistrationRepository) Restore(ctx context.Context, id string, tx *gorm.DB) error {
+	args := m.Called(ctx, id, tx)
+	return args.Error(0)
+}
+
+// Purge mocks the Purge method
+func (m *MockRegistrationRepository) Purge(ctx context.Context, id string, tx *gorm.DB) error {
+	args := m.Called(ctx, id, tx)
+	return args.Error(0)
+}
+
 // FilterSubQuery mocks the FilterSubQuery method
 func (m *MockRegistrationRepository) FilterSubQuery(ctx context.Context, tx *gorm.DB, filter dto.FilterRegistrationRequest) *gorm.DB {
 	args := m.Called(ctx, tx, filter)
@@ -61,6 +122,12 @@ func (m *MockRegistrationRepository) FindTotal(ctx context.Context, filter dto.F
 	return args.Get(0).(int64), args.Error(1)
 }
 
+// FindByAdvisorEmail mocks the FindByAdvisorEmail method
+func (m *MockRegistrationRepository) FindByAdvisorEmail(ctx context.Context, email string, tx *gorm.DB) ([]entity.Registration, error) {
+	args := m.Called(ctx, email, tx)
+	return args.Get(0).([]entity.Registration), args.Error(1)
+}
+
 // FindRegistrationByAdvisiorEmail mocks the FindRegistrationByAdvisiorEmail method
 func (m *MockRegistrationRepository) FindRegistrationByAdvisiorEmail(ctx context.Context, email string, tx *gorm.DB) (entity.Registration, error) {
 	args := m.Called(ctx, email, tx)
@@ -73,8 +140,36 @@ func (m *MockRegistrationRepository) FindByNRP(ctx context.Context, nrp string,
 	return args.Get(0).(entity.Registration), args.Error(1)
 }
 
+// FindActiveByNRP mocks the FindActiveByNRP method
+func (m *MockRegistrationRepository) FindActiveByNRP(ctx context.Context, nrp string, tx *gorm.DB) ([]entity.Registration, error) {
+	args := m.Called(ctx, nrp, tx)
+	return args.Get(0).([]entity.Registration), args.Error(1)
+}
+
 // FindByActivityIDAndNRP mocks the FindByActivityIDAndNRP method
 func (m *MockRegistrationRepository) FindByActivityIDAndNRP(ctx context.Context, activityID string, nrp string, tx *gorm.DB) (entity.Registration, error) {
 	args := m.Called(ctx, activityID, nrp, tx)
 	return args.Get(0).(entity.Registration), args.Error(1)
 }
+
+// FindByActivityID mocks the FindByActivityID method
+func (m *MockRegistrationRepository) FindByActivityID(ctx context.Context, activityID string, tx *gorm.DB) ([]entity.Registration, error) {
+	args := m.Called(ctx, activityID, tx)
+	return args.Get(0).([]entity.Registration), args.Error(1)
+}
+
+// WithTransaction mocks the WithTransaction method, a func returned by the
+// expectation runs in place of the transaction
+func (m *MockRegistrationRepository) WithTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	args := m.Called(ctx, fn)
+	if run, ok := args.Get(0).(func(ctx context.Context, fn func(tx *gorm.DB) error) error); ok {
+		return run(ctx, fn)
+	}
+	return args.Error(0)
+}
+
+// LockNRP mocks the LockNRP method
+func (m *MockRegistrationRepository) LockNRP(ctx context.Context, nrp string, tx *gorm.DB) error {
+	args := m.Called(ctx, nrp, tx)
+	return args.Error(0)
+}
@@ -0,0 +1,35 @@
+package repository_mock
+
+import (
+	"context"
+	"registration-service/dto"
+	"registration-service/entity"
+	"registration-service/repository"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockRetentionRepository is a mock implementation of repository.RetentionRepository
+type MockRetentionRepository struct {
+	mock.Mock
+}
+
+// IndexDue mocks the IndexDue method
+func (m *MockRetentionRepository) IndexDue(ctx context.Context, cutoff time.Time, pagReq dto.PaginationRequest, tx *gorm.DB) ([]repository.RetentionCandidate, int64, error) {
+	args := m.Called(ctx, cutoff, pagReq, tx)
+	return args.Get(0).([]repository.RetentionCandidate), args.Get(1).(int64), args.Error(2)
+}
+
+// IndexAnonymized mocks the IndexAnonymized method
+func (m *MockRetentionRepository) IndexAnonymized(ctx context.Context, pagReq dto.PaginationRequest, tx *gorm.DB) ([]repository.RetentionCandidate, int64, error) {
+	args := m.Called(ctx, pagReq, tx)
+	return args.Get(0).([]repository.RetentionCandidate), args.Get(1).(int64), args.Error(2)
+}
+
+// Anonymize mocks the Anonymize method
+func (m *MockRetentionRepository) Anonymize(ctx context.Context, registration entity.Registration, histories []entity.RegistrationHistory, tx *gorm.DB) error {
+	args := m.Called(ctx, registration, histories, tx)
+	return args.Error(0)
+}
@@ -0,0 +1,52 @@
+package service_mock
+
+import (
+	"context"
+	"registration-service/dto"
+	"registration-service/entity"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockAcademicPeriodService struct {
+	mock.Mock
+}
+
+func NewMockAcademicPeriodService() *MockAcademicPeriodService {
+	return &MockAcademicPeriodService{}
+}
+
+func (m *MockAcademicPeriodService) FindAllAcademicPeriods(ctx context.Context, filter dto.FilterAcademicPeriodRequest, tx *gorm.DB) ([]dto.AcademicPeriodResponse, error) {
+	args := m.Called(ctx, filter, tx)
+	return args.Get(0).([]dto.AcademicPeriodResponse), args.Error(1)
+}
+
+func (m *MockAcademicPeriodService) FindAcademicPeriodByID(ctx context.Context, id string, tx *gorm.DB) (dto.AcademicPeriodResponse, error) {
+	args := m.Called(ctx, id, tx)
+	return args.Get(0).(dto.AcademicPeriodResponse), args.Error(1)
+}
+
+func (m *MockAcademicPeriodService) CreateAcademicPeriod(ctx context.Context, request dto.AcademicPeriodRequest, tx *gorm.DB) error {
+	args := m.Called(ctx, request, tx)
+	return args.Error(0)
+}
+
+func (m *MockAcademicPeriodService) UpdateAcademicPeriod(ctx context.Context, id string, request dto.AcademicPeriodRequest, tx *gorm.DB) error {
+	args := m.Called(ctx, id, request, tx)
+	return args.Error(0)
+}
+
+func (m *MockAcademicPeriodService) DeleteAcademicPeriod(ctx context.Context, id string, tx *gorm.DB) error {
+	args := m.Called(ctx, id, tx)
+	return args.Error(0)
+}
+
+func (m *MockAcademicPeriodService) FindPeriodForDate(ctx context.Context, date time.Time, tx *gorm.DB) (*entity.AcademicPeriod, error) {
+	args := m.Called(ctx, date, tx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.AcademicPeriod), args.Error(1)
+}
@@ -0,0 +1,37 @@
+package service_mock
+
+import (
+	"context"
+	"registration-service/dto"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockAdvisorDelegationService struct {
+	mock.Mock
+}
+
+func NewMockAdvisorDelegationService() *MockAdvisorDelegationService {
+	return &MockAdvisorDelegationService{}
+}
+
+func (m *MockAdvisorDelegationService) FindAllAdvisorDelegations(ctx context.Context, filter dto.FilterAdvisorDelegationRequest, tx *gorm.DB) ([]dto.AdvisorDelegationResponse, error) {
+	args := m.Called(ctx, filter, tx)
+	return args.Get(0).([]dto.AdvisorDelegationResponse), args.Error(1)
+}
+
+func (m *MockAdvisorDelegationService) FindAdvisorDelegationByID(ctx context.Context, id string, tx *gorm.DB) (dto.AdvisorDelegationResponse, error) {
+	args := m.Called(ctx, id, tx)
+	return args.Get(0).(dto.AdvisorDelegationResponse), args.Error(1)
+}
+
+func (m *MockAdvisorDelegationService) CreateAdvisorDelegation(ctx context.Context, request dto.AdvisorDelegationRequest, token string, tx *gorm.DB) (dto.AdvisorDelegationResponse, error) {
+	args := m.Called(ctx, request, token, tx)
+	return args.Get(0).(dto.AdvisorDelegationResponse), args.Error(1)
+}
+
+func (m *MockAdvisorDelegationService) RevokeAdvisorDelegation(ctx context.Context, id string, tx *gorm.DB) error {
+	args := m.Called(ctx, id, tx)
+	return args.Error(0)
+}
@@ -0,0 +1,63 @@
+package service_mock
+
+import (
+	"context"
+	"registration-service/dto"
+	"registration-service/entity"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockDocumentRequirementService struct {
+	mock.Mock
+}
+
+func NewMockDocumentRequirementService() *MockDocumentRequirementService {
+	return &MockDocumentRequirementService{}
+}
+
+func (m *MockDocumentRequirementService) FindAllDocumentRequirements(ctx context.Context, filter dto.FilterDocumentRequirementRequest, tx *gorm.DB) ([]dto.DocumentRequirementResponse, error) {
+	args := m.Called(ctx, filter, tx)
+	return args.Get(0).([]dto.DocumentRequirementResponse), args.Error(1)
+}
+
+func (m *MockDocumentRequirementService) FindDocumentRequirementByID(ctx context.Context, id string, tx *gorm.DB) (dto.DocumentRequirementResponse, error) {
+	args := m.Called(ctx, id, tx)
+	return args.Get(0).(dto.DocumentRequirementResponse), args.Error(1)
+}
+
+func (m *MockDocumentRequirementService) CreateDocumentRequirement(ctx context.Context, request dto.DocumentRequirementRequest, tx *gorm.DB) error {
+	args := m.Called(ctx, request, tx)
+	return args.Error(0)
+}
+
+func (m *MockDocumentRequirementService) UpdateDocumentRequirement(ctx context.Context, id string, request dto.DocumentRequirementRequest, tx *gorm.DB) error {
+	args := m.Called(ctx, id, request, tx)
+	return args.Error(0)
+}
+
+func (m *MockDocumentRequirementService) DeleteDocumentRequirement(ctx context.Context, id string, tx *gorm.DB) error {
+	args := m.Called(ctx, id, tx)
+	return args.Error(0)
+}
+
+func (m *MockDocumentRequirementService) FindRequirementsForRegistration(ctx context.Context, registration entity.Registration, tx *gorm.DB) ([]entity.DocumentRequirement, error) {
+	args := m.Called(ctx, registration, tx)
+	return args.Get(0).([]entity.DocumentRequirement), args.Error(1)
+}
+
+func (m *MockDocumentRequirementService) ValidateDocumentType(ctx context.Context, registration entity.Registration, documentType string, tx *gorm.DB) (entity.DocumentRequirement, error) {
+	args := m.Called(ctx, registration, documentType, tx)
+	return args.Get(0).(entity.DocumentRequirement), args.Error(1)
+}
+
+func (m *MockDocumentRequirementService) CheckCompleteness(ctx context.Context, registration entity.Registration, tx *gorm.DB) (dto.DocumentCompletenessResponse, error) {
+	args := m.Called(ctx, registration, tx)
+	return args.Get(0).(dto.DocumentCompletenessResponse), args.Error(1)
+}
+
+func (m *MockDocumentRequirementService) CheckCompletenessForRegistrations(ctx context.Context, registrations []entity.Registration, tx *gorm.DB) (map[string]dto.DocumentCompletenessResponse, error) {
+	args := m.Called(ctx, registrations, tx)
+	return args.Get(0).(map[string]dto.DocumentCompletenessResponse), args.Error(1)
+}
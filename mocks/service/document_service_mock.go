@@ -4,6 +4,7 @@ import (
 	"context"
 	"mime/multipart"
 	"registration-service/dto"
+	"registration-service/service"
 
 	"github.com/stretchr/testify/mock"
 	"gorm.io/gorm"
@@ -27,13 +28,13 @@ func (m *MockDocumentService) FindDocumentById(ctx context.Context, id string, t
 	return args.Get(0).(dto.DocumentResponse), args.Error(1)
 }
 
-func (m *MockDocumentService) CreateDocument(ctx context.Context, document dto.DocumentRequest, file *multipart.FileHeader, tx *gorm.DB) error {
-	args := m.Called(ctx, document, file, tx)
+func (m *MockDocumentService) CreateDocument(ctx context.Context, document dto.DocumentRequest, file *multipart.FileHeader, token string, tx *gorm.DB) error {
+	args := m.Called(ctx, document, file, token, tx)
 	return args.Error(0)
 }
 
-func (m *MockDocumentService) UpdateDocument(ctx context.Context, id string, document dto.UpdateDocumentRequest, file *multipart.FileHeader, tx *gorm.DB) error {
-	args := m.Called(ctx, id, document, file, tx)
+func (m *MockDocumentService) UpdateDocument(ctx context.Context, id string, document dto.UpdateDocumentRequest, file *multipart.FileHeader, token string, tx *gorm.DB) error {
+	args := m.Called(ctx, id, document, file, token, tx)
 	return args.Error(0)
 }
 
@@ -41,3 +42,33 @@ func (m *MockDocumentService) DeleteDocument(ctx context.Context, id string, tx
 	args := m.Called(ctx, id, tx)
 	return args.Error(0)
 }
+
+func (m *MockDocumentService) FindDocumentVersions(ctx context.Context, id string, token string, tx *gorm.DB) ([]dto.DocumentVersionResponse, error) {
+	args := m.Called(ctx, id, token, tx)
+	return args.Get(0).([]dto.DocumentVersionResponse), args.Error(1)
+}
+
+func (m *MockDocumentService) FindDocumentVersion(ctx context.Context, id string, version int, token string, tx *gorm.DB) (dto.DocumentVersionResponse, error) {
+	args := m.Called(ctx, id, version, token, tx)
+	return args.Get(0).(dto.DocumentVersionResponse), args.Error(1)
+}
+
+func (m *MockDocumentService) DownloadDocument(ctx context.Context, id string, version int, token string, tx *gorm.DB) (service.DocumentDownload, error) {
+	args := m.Called(ctx, id, version, token, tx)
+	return args.Get(0).(service.DocumentDownload), args.Error(1)
+}
+
+func (m *MockDocumentService) DownloadSignedDocument(ctx context.Context, id string, version int, expires int64, signature string, tx *gorm.DB) (service.DocumentDownload, error) {
+	args := m.Called(ctx, id, version, expires, signature, tx)
+	return args.Get(0).(service.DocumentDownload), args.Error(1)
+}
+
+func (m *MockDocumentService) CreateDownloadLink(ctx context.Context, id string, version int, token string, tx *gorm.DB) (dto.DocumentDownloadLinkResponse, error) {
+	args := m.Called(ctx, id, version, token, tx)
+	return args.Get(0).(dto.DocumentDownloadLinkResponse), args.Error(1)
+}
+
+func (m *MockDocumentService) ReviewDocument(ctx context.Context, id string, review dto.DocumentReviewRequest, token string, tx *gorm.DB) error {
+	args := m.Called(ctx, id, review, token, tx)
+	return args.Error(0)
+}
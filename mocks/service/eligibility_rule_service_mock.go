@@ -0,0 +1,48 @@
+package service_mock
+
+import (
+	"context"
+	"registration-service/dto"
+	"registration-service/entity"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockEligibilityRuleService struct {
+	mock.Mock
+}
+
+func NewMockEligibilityRuleService() *MockEligibilityRuleService {
+	return &MockEligibilityRuleService{}
+}
+
+func (m *MockEligibilityRuleService) FindAllEligibilityRules(ctx context.Context, filter dto.FilterEligibilityRuleRequest, tx *gorm.DB) ([]dto.EligibilityRuleResponse, error) {
+	args := m.Called(ctx, filter, tx)
+	return args.Get(0).([]dto.EligibilityRuleResponse), args.Error(1)
+}
+
+func (m *MockEligibilityRuleService) FindEligibilityRuleByID(ctx context.Context, id string, tx *gorm.DB) (dto.EligibilityRuleResponse, error) {
+	args := m.Called(ctx, id, tx)
+	return args.Get(0).(dto.EligibilityRuleResponse), args.Error(1)
+}
+
+func (m *MockEligibilityRuleService) CreateEligibilityRule(ctx context.Context, request dto.EligibilityRuleRequest, tx *gorm.DB) error {
+	args := m.Called(ctx, request, tx)
+	return args.Error(0)
+}
+
+func (m *MockEligibilityRuleService) UpdateEligibilityRule(ctx context.Context, id string, request dto.EligibilityRuleRequest, tx *gorm.DB) error {
+	args := m.Called(ctx, id, request, tx)
+	return args.Error(0)
+}
+
+func (m *MockEligibilityRuleService) DeleteEligibilityRule(ctx context.Context, id string, tx *gorm.DB) error {
+	args := m.Called(ctx, id, tx)
+	return args.Error(0)
+}
+
+func (m *MockEligibilityRuleService) FindRulesForActivity(ctx context.Context, programTypeID string, levelID string, tx *gorm.DB) ([]entity.EligibilityRule, error) {
+	args := m.Called(ctx, programTypeID, levelID, tx)
+	return args.Get(0).([]entity.EligibilityRule), args.Error(1)
+}
@@ -1,7 +1,9 @@
 package service_mock
 
 import (
+	"context"
 	"mime/multipart"
+	"registration-service/service"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -32,6 +34,26 @@ func (m *MockStorageInterface) GcsDelete(fileID, projectID, bucketName string) (
 	return args.Get(0).(*FileStorageResponse), args.Error(1)
 }
 
+// Upload mocks service.DocumentStorage.Upload
+func (m *MockStorageInterface) Upload(ctx context.Context, file *multipart.FileHeader) (string, error) {
+	args := m.Called(ctx, file)
+	return args.String(0), args.Error(1)
+}
+
+// Download mocks service.DocumentStorage.Download
+func (m *MockStorageInterface) Download(ctx context.Context, fileID string) (service.FileObject, error) {
+	args := m.Called(ctx, fileID)
+	return args.Get(0).(service.FileObject), args.Error(1)
+}
+
+// Delete mocks service.DocumentStorage.Delete
+func (m *MockStorageInterface) Delete(ctx context.Context, fileID string) error {
+	args := m.Called(ctx, fileID)
+	return args.Error(0)
+}
+
+var _ service.DocumentStorage = (*MockStorageInterface)(nil)
+
 type MockFileService struct {
 	mock.Mock
 	Storage *MockStorageInterface
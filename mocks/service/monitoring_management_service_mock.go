@@ -26,3 +26,8 @@ func (m *MockMonitoringManagementService) CreateReportSchedule(data map[string]i
 	args := m.Called(data, method, token)
 	return args.Error(0)
 }
+
+func (m *MockMonitoringManagementService) CancelReportSchedulesByRegistrationID(registrationID string, token string) error {
+	args := m.Called(registrationID, token)
+	return args.Error(0)
+}
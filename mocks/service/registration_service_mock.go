@@ -4,6 +4,7 @@ import (
 	"context"
 	"mime/multipart"
 	"registration-service/dto"
+	"registration-service/service"
 
 	"github.com/stretchr/testify/mock"
 	"gorm.io/gorm"
@@ -37,6 +38,11 @@ func (m *MockRegistrationService) UpdateRegistration(ctx context.Context, id str
 	return args.Error(0)
 }
 
+func (m *MockRegistrationService) PatchRegistration(ctx context.Context, id string, request dto.PatchRegistrationRequest, token string, tx *gorm.DB) error {
+	args := m.Called(ctx, id, request, token, tx)
+	return args.Error(0)
+}
+
 func (m *MockRegistrationService) DeleteRegistration(ctx context.Context, id string, token string, tx *gorm.DB) error {
 	args := m.Called(ctx, id, token, tx)
 	return args.Error(0)
@@ -106,3 +112,58 @@ func (m *MockRegistrationService) CheckRegistrationEligibility(ctx context.Conte
 	args := m.Called(ctx, activityID, token, tx)
 	return args.Get(0).(dto.RegistrationEligibilityResponse), args.Error(1)
 }
+
+func (m *MockRegistrationService) CreateDraftRegistration(ctx context.Context, registration dto.CreateDraftRegistrationRequest, token string, tx *gorm.DB) (dto.GetRegistrationResponse, error) {
+	args := m.Called(ctx, registration, token, tx)
+	return args.Get(0).(dto.GetRegistrationResponse), args.Error(1)
+}
+
+func (m *MockRegistrationService) SubmitRegistration(ctx context.Context, id string, token string, tx *gorm.DB) error {
+	args := m.Called(ctx, id, token, tx)
+	return args.Error(0)
+}
+
+func (m *MockRegistrationService) RequestWithdrawal(ctx context.Context, id string, request dto.WithdrawalRequest, token string, tx *gorm.DB) error {
+	args := m.Called(ctx, id, request, token, tx)
+	return args.Error(0)
+}
+
+func (m *MockRegistrationService) AdvisorWithdrawalApproval(ctx context.Context, token string, approval dto.ApprovalRequest, tx *gorm.DB) error {
+	args := m.Called(ctx, token, approval, tx)
+	return args.Error(0)
+}
+
+func (m *MockRegistrationService) LOWithdrawalApproval(ctx context.Context, token string, approval dto.ApprovalRequest, tx *gorm.DB) error {
+	args := m.Called(ctx, token, approval, tx)
+	return args.Error(0)
+}
+
+func (m *MockRegistrationService) FindRegistrationHistory(ctx context.Context, id string, token string, tx *gorm.DB) ([]dto.RegistrationHistoryResponse, error) {
+	args := m.Called(ctx, id, token, tx)
+	return args.Get(0).([]dto.RegistrationHistoryResponse), args.Error(1)
+}
+
+func (m *MockRegistrationService) RegistrationDocumentsArchive(ctx context.Context, id string, token string, tx *gorm.DB) (service.DocumentArchive, error) {
+	args := m.Called(ctx, id, token, tx)
+	return args.Get(0).(service.DocumentArchive), args.Error(1)
+}
+
+func (m *MockRegistrationService) ActivityDocumentsArchive(ctx context.Context, activityID string, token string, tx *gorm.DB) (service.DocumentArchive, error) {
+	args := m.Called(ctx, activityID, token, tx)
+	return args.Get(0).(service.DocumentArchive), args.Error(1)
+}
+
+func (m *MockRegistrationService) ExportRegistrations(ctx context.Context, scope string, filter dto.FilterRegistrationRequest, request dto.RegistrationExportRequest, token string, tx *gorm.DB) (service.RegistrationExport, error) {
+	args := m.Called(ctx, scope, filter, request, token, tx)
+	return args.Get(0).(service.RegistrationExport), args.Error(1)
+}
+
+func (m *MockRegistrationService) ImportRegistrations(ctx context.Context, file *multipart.FileHeader, request dto.RegistrationImportRequest, token string, tx *gorm.DB) (dto.RegistrationImportResponse, error) {
+	args := m.Called(ctx, file, request, token, tx)
+	return args.Get(0).(dto.RegistrationImportResponse), args.Error(1)
+}
+
+func (m *MockRegistrationService) ReassignAdvisor(ctx context.Context, request dto.AdvisorReassignmentRequest, token string, tx *gorm.DB) (dto.AdvisorReassignmentResponse, error) {
+	args := m.Called(ctx, request, token, tx)
+	return args.Get(0).(dto.AdvisorReassignmentResponse), args.Error(1)
+}
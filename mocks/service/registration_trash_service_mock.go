@@ -0,0 +1,38 @@
+package service_mock
+
+import (
+	"context"
+	"registration-service/dto"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockRegistrationTrashService struct {
+	mock.Mock
+}
+
+func NewMockRegistrationTrashService() *MockRegistrationTrashService {
+	return &MockRegistrationTrashService{}
+}
+
+func (m *MockRegistrationTrashService) FindDeletedRegistrations(ctx context.Context, pagReq dto.PaginationRequest, tx *gorm.DB) ([]dto.DeletedRegistrationResponse, dto.PaginationResponse, error) {
+	args := m.Called(ctx, pagReq, tx)
+	return args.Get(0).([]dto.DeletedRegistrationResponse), args.Get(1).(dto.PaginationResponse), args.Error(2)
+}
+
+func (m *MockRegistrationTrashService) FindDeletedRegistrationByID(ctx context.Context, id string, tx *gorm.DB) (dto.DeletedRegistrationResponse, error) {
+	args := m.Called(ctx, id, tx)
+	return args.Get(0).(dto.DeletedRegistrationResponse), args.Error(1)
+}
+
+func (m *MockRegistrationTrashService) RestoreRegistration(ctx context.Context, id string, token string, tx *gorm.DB) error {
+	args := m.Called(ctx, id, token, tx)
+	return args.Error(0)
+}
+
+func (m *MockRegistrationTrashService) PurgeDeletedRegistrations(ctx context.Context, now time.Time) (dto.RegistrationPurgeResult, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).(dto.RegistrationPurgeResult), args.Error(1)
+}
@@ -0,0 +1,28 @@
+package service_mock
+
+import (
+	"context"
+	"registration-service/dto"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockRetentionService struct {
+	mock.Mock
+}
+
+func NewMockRetentionService() *MockRetentionService {
+	return &MockRetentionService{}
+}
+
+func (m *MockRetentionService) RetentionReport(ctx context.Context, request dto.RetentionReportRequest, pagReq dto.PaginationRequest, tx *gorm.DB) (dto.RetentionReportResponse, dto.PaginationResponse, error) {
+	args := m.Called(ctx, request, pagReq, tx)
+	return args.Get(0).(dto.RetentionReportResponse), args.Get(1).(dto.PaginationResponse), args.Error(2)
+}
+
+func (m *MockRetentionService) AnonymizeDueRegistrations(ctx context.Context, now time.Time, dryRun bool) (dto.RetentionRunResponse, error) {
+	args := m.Called(ctx, now, dryRun)
+	return args.Get(0).(dto.RetentionRunResponse), args.Error(1)
+}
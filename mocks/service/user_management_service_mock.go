@@ -42,4 +42,12 @@ func (m *MockUserManagementService) GetDosenDataByEmail(data map[string]interfac
 		return nil
 	}
 	return args.Get(0).(map[string]interface{})
-} 
\ No newline at end of file
+}
+
+func (m *MockUserManagementService) GetStudentAdvisor(nrp string, method string, token string) (map[string]interface{}, error) {
+	args := m.Called(nrp, method, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]interface{}), args.Error(1)
+}
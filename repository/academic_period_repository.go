@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"registration-service/dto"
+	"registration-service/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type academicPeriodRepository struct {
+	db             *gorm.DB
+	baseRepository BaseRepository
+}
+
+type AcademicPeriodRepository interface {
+	Index(ctx context.Context, filter dto.FilterAcademicPeriodRequest, tx *gorm.DB) ([]entity.AcademicPeriod, error)
+	FindByID(ctx context.Context, id string, tx *gorm.DB) (entity.AcademicPeriod, error)
+	FindByDate(ctx context.Context, date time.Time, tx *gorm.DB) (entity.AcademicPeriod, error)
+	FindOverlapping(ctx context.Context, start time.Time, end time.Time, excludeID string, tx *gorm.DB) ([]entity.AcademicPeriod, error)
+	Create(ctx context.Context, period entity.AcademicPeriod, tx *gorm.DB) (entity.AcademicPeriod, error)
+	Update(ctx context.Context, id string, period entity.AcademicPeriod, tx *gorm.DB) error
+	Destroy(ctx context.Context, id string, tx *gorm.DB) error
+}
+
+func NewAcademicPeriodRepository(db *gorm.DB) AcademicPeriodRepository {
+	return &academicPeriodRepository{db: db, baseRepository: NewBaseRepository(db)}
+}
+
+func (r *academicPeriodRepository) Index(ctx context.Context, filter dto.FilterAcademicPeriodRequest, tx *gorm.DB) ([]entity.AcademicPeriod, error) {
+	var periods []entity.AcademicPeriod
+	if tx == nil {
+		tx = r.db
+	}
+
+	query := tx.WithContext(ctx).
+		Model(&entity.AcademicPeriod{}).
+		Where("academic_periods.deleted_at IS NULL")
+
+	if filter.Year != "" {
+		query = query.Where("academic_periods.year = ?", filter.Year)
+	}
+
+	if filter.Term != "" {
+		query = query.Where("academic_periods.term = ?", filter.Term)
+	}
+
+	err := query.
+		Order("start_date DESC").
+		Find(&periods).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return periods, nil
+}
+
+func (r *academicPeriodRepository) FindByID(ctx context.Context, id string, tx *gorm.DB) (entity.AcademicPeriod, error) {
+	var period entity.AcademicPeriod
+	if tx == nil {
+		tx = r.db
+	}
+
+	err := tx.WithContext(ctx).
+		Model(&entity.AcademicPeriod{}).
+		Where("id = ?", id).
+		Where("academic_periods.deleted_at IS NULL").
+		First(&period).Error
+	if err != nil {
+		return entity.AcademicPeriod{}, err
+	}
+
+	return period, nil
+}
+
+// FindByDate returns the period the date falls in, both ends included
+func (r *academicPeriodRepository) FindByDate(ctx context.Context, date time.Time, tx *gorm.DB) (entity.AcademicPeriod, error) {
+	var period entity.AcademicPeriod
+	if tx == nil {
+		tx = r.db
+	}
+
+	err := tx.WithContext(ctx).
+		Model(&entity.AcademicPeriod{}).
+		Where("academic_periods.deleted_at IS NULL").
+		Where("start_date <= ?", date).
+		Where("end_date >= ?", date).
+		Order("start_date DESC").
+		First(&period).Error
+	if err != nil {
+		return entity.AcademicPeriod{}, err
+	}
+
+	return period, nil
+}
+
+// FindOverlapping returns the periods sharing at least one day with the
+// interval, excludeID leaves out the period being updated
+func (r *academicPeriodRepository) FindOverlapping(ctx context.Context, start time.Time, end time.Time, excludeID string, tx *gorm.DB) ([]entity.AcademicPeriod, error) {
+	var periods []entity.AcademicPeriod
+	if tx == nil {
+		tx = r.db
+	}
+
+	query := tx.WithContext(ctx).
+		Model(&entity.AcademicPeriod{}).
+		Where("academic_periods.deleted_at IS NULL").
+		Where("start_date <= ?", end).
+		Where("end_date >= ?", start)
+
+	if excludeID != "" {
+		query = query.Where("id <> ?", excludeID)
+	}
+
+	err := query.
+		Order("start_date ASC").
+		Find(&periods).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return periods, nil
+}
+
+func (r *academicPeriodRepository) Create(ctx context.Context, period entity.AcademicPeriod, tx *gorm.DB) (entity.AcademicPeriod, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	err := tx.WithContext(ctx).
+		Model(&entity.AcademicPeriod{}).
+		Create(&period).Error
+	if err != nil {
+		return entity.AcademicPeriod{}, translateError(err)
+	}
+
+	return period, nil
+}
+
+func (r *academicPeriodRepository) Update(ctx context.Context, id string, period entity.AcademicPeriod, tx *gorm.DB) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	err := tx.WithContext(ctx).
+		Model(&entity.AcademicPeriod{}).
+		Where("id = ?", id).
+		Where("academic_periods.deleted_at IS NULL").
+		Select("year", "term", "start_date", "end_date", "max_registrations_per_student").
+		Updates(&period).Error
+
+	return translateError(err)
+}
+
+func (r *academicPeriodRepository) Destroy(ctx context.Context, id string, tx *gorm.DB) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	result := tx.WithContext(ctx).
+		Model(&entity.AcademicPeriod{}).
+		Where("id = ?", id).
+		Where("academic_periods.deleted_at IS NULL").
+		Update("deleted_at", gorm.Expr("NOW()"))
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("data not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"registration-service/dto"
+	"registration-service/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type advisorDelegationRepository struct {
+	db             *gorm.DB
+	baseRepository BaseRepository
+}
+
+type AdvisorDelegationRepository interface {
+	Index(ctx context.Context, filter dto.FilterAdvisorDelegationRequest, tx *gorm.DB) ([]entity.AdvisorDelegation, error)
+	FindByID(ctx context.Context, id string, tx *gorm.DB) (entity.AdvisorDelegation, error)
+	FindActiveByDelegateEmail(ctx context.Context, email string, at time.Time, tx *gorm.DB) ([]entity.AdvisorDelegation, error)
+	Create(ctx context.Context, delegation entity.AdvisorDelegation, tx *gorm.DB) (entity.AdvisorDelegation, error)
+	Destroy(ctx context.Context, id string, tx *gorm.DB) error
+}
+
+func NewAdvisorDelegationRepository(db *gorm.DB) AdvisorDelegationRepository {
+	return &advisorDelegationRepository{db: db, baseRepository: NewBaseRepository(db)}
+}
+
+func (r *advisorDelegationRepository) Index(ctx context.Context, filter dto.FilterAdvisorDelegationRequest, tx *gorm.DB) ([]entity.AdvisorDelegation, error) {
+	var delegations []entity.AdvisorDelegation
+	if tx == nil {
+		tx = r.db
+	}
+
+	query := tx.WithContext(ctx).
+		Model(&entity.AdvisorDelegation{}).
+		Where("advisor_delegations.deleted_at IS NULL")
+
+	if filter.AdvisorEmail != "" {
+		query = query.Where("advisor_delegations.advisor_email = ?", filter.AdvisorEmail)
+	}
+
+	if filter.DelegateEmail != "" {
+		query = query.Where("advisor_delegations.delegate_email = ?", filter.DelegateEmail)
+	}
+
+	if filter.Active {
+		now := time.Now()
+		query = query.Where("starts_at <= ?", now).Where("ends_at > ?", now)
+	}
+
+	err := query.
+		Order("starts_at DESC").
+		Find(&delegations).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return delegations, nil
+}
+
+func (r *advisorDelegationRepository) FindByID(ctx context.Context, id string, tx *gorm.DB) (entity.AdvisorDelegation, error) {
+	var delegation entity.AdvisorDelegation
+	if tx == nil {
+		tx = r.db
+	}
+
+	err := tx.WithContext(ctx).
+		Model(&entity.AdvisorDelegation{}).
+		Where("id = ?", id).
+		Where("advisor_delegations.deleted_at IS NULL").
+		First(&delegation).Error
+	if err != nil {
+		return entity.AdvisorDelegation{}, err
+	}
+
+	return delegation, nil
+}
+
+// FindActiveByDelegateEmail returns the delegations the user holds at the
+// given moment, the start is inclusive and the end exclusive
+func (r *advisorDelegationRepository) FindActiveByDelegateEmail(ctx context.Context, email string, at time.Time, tx *gorm.DB) ([]entity.AdvisorDelegation, error) {
+	var delegations []entity.AdvisorDelegation
+	if tx == nil {
+		tx = r.db
+	}
+
+	err := tx.WithContext(ctx).
+		Model(&entity.AdvisorDelegation{}).
+		Where("advisor_delegations.deleted_at IS NULL").
+		Where("delegate_email = ?", email).
+		Where("starts_at <= ?", at).
+		Where("ends_at > ?", at).
+		Order("starts_at ASC").
+		Find(&delegations).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return delegations, nil
+}
+
+func (r *advisorDelegationRepository) Create(ctx context.Context, delegation entity.AdvisorDelegation, tx *gorm.DB) (entity.AdvisorDelegation, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	err := tx.WithContext(ctx).
+		Model(&entity.AdvisorDelegation{}).
+		Create(&delegation).Error
+	if err != nil {
+		return entity.AdvisorDelegation{}, translateError(err)
+	}
+
+	return delegation, nil
+}
+
+func (r *advisorDelegationRepository) Destroy(ctx context.Context, id string, tx *gorm.DB) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	result := tx.WithContext(ctx).
+		Model(&entity.AdvisorDelegation{}).
+		Where("id = ?", id).
+		Where("advisor_delegations.deleted_at IS NULL").
+		Update("deleted_at", gorm.Expr("NOW()"))
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("data not found")
+	}
+
+	return nil
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"registration-service/dto"
 
 	"gorm.io/gorm"
 )
@@ -42,3 +44,44 @@ func (r *baseRepository) CommitTx(ctx context.Context, tx *gorm.DB) (*gorm.DB, e
 func (r *baseRepository) RollbackTx(ctx context.Context, tx *gorm.DB) {
 	tx.WithContext(ctx).Debug().Rollback()
 }
+
+// keysetPage narrows a query to one keyset page on (created_at, id) of table.
+// A page read backwards is ordered the other way round and one row more than
+// the limit is read to tell whether another page follows, keysetRows undoes
+// both.
+func keysetPage(query *gorm.DB, table string, pagReq dto.PaginationRequest, descending bool) *gorm.DB {
+	backward := pagReq.Before != nil
+	cursor := pagReq.After
+	if backward {
+		cursor = pagReq.Before
+	}
+
+	direction, operator := "ASC", ">"
+	if descending != backward {
+		direction, operator = "DESC", "<"
+	}
+
+	if cursor != nil {
+		query = query.Where(fmt.Sprintf("(%s.created_at, %s.id) %s (?, ?)", table, table, operator), cursor.CreatedAt, cursor.ID)
+	}
+
+	return query.
+		Order(table + ".created_at " + direction).
+		Order(table + ".id " + direction).
+		Limit(pagReq.Limit + 1)
+}
+
+func keysetRows[T any](rows []T, pagReq dto.PaginationRequest) ([]T, bool) {
+	hasMore := len(rows) > pagReq.Limit
+	if hasMore {
+		rows = rows[:pagReq.Limit]
+	}
+
+	if pagReq.Before != nil {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+
+	return rows, hasMore
+}
@@ -16,6 +16,7 @@ type documentRepository struct {
 
 type DocumentRepository interface {
 	Index(ctx context.Context, pagReq dto.PaginationRequest, tx *gorm.DB) ([]entity.Document, int64, error)
+	IndexKeyset(ctx context.Context, pagReq dto.PaginationRequest, tx *gorm.DB) ([]entity.Document, bool, int64, error)
 	Create(ctx context.Context, document entity.Document, tx *gorm.DB) (entity.Document, error)
 	Update(ctx context.Context, id string, document entity.Document, tx *gorm.DB) error
 	FindByID(ctx context.Context, id string, tx *gorm.DB) (entity.Document, error)
@@ -63,9 +64,11 @@ func (r *documentRepository) Index(ctx context.Context, pagReq dto.PaginationReq
 	err := tx.WithContext(ctx).
 		Model(&entity.Document{}).
 		Where("documents.deleted_at IS NULL").
+		Order("documents.created_at DESC").
+		Order("documents.id DESC").
 		Offset(pagReq.Offset).
 		Limit(pagReq.Limit).
-		First(&documents).Error
+		Find(&documents).Error
 
 	if err != nil {
 		return []entity.Document{}, 0, err
@@ -79,6 +82,36 @@ func (r *documentRepository) Index(ctx context.Context, pagReq dto.PaginationReq
 	return documents, total, nil
 }
 
+// IndexKeyset reads one cursor page of documents, newest first
+func (r *documentRepository) IndexKeyset(ctx context.Context, pagReq dto.PaginationRequest, tx *gorm.DB) ([]entity.Document, bool, int64, error) {
+	var documents []entity.Document
+	if tx == nil {
+		tx = r.db
+	}
+
+	query := tx.WithContext(ctx).
+		Model(&entity.Document{}).
+		Where("documents.deleted_at IS NULL")
+
+	err := keysetPage(query, "documents", pagReq, true).
+		Find(&documents).Error
+	if err != nil {
+		return []entity.Document{}, false, 0, err
+	}
+
+	documents, hasMore := keysetRows(documents, pagReq)
+
+	var total int64
+	if pagReq.WithTotal {
+		total, err = r.FindTotal(ctx, tx)
+		if err != nil {
+			return []entity.Document{}, false, 0, err
+		}
+	}
+
+	return documents, hasMore, total, nil
+}
+
 func (r *documentRepository) FindByID(ctx context.Context, id string, tx *gorm.DB) (entity.Document, error) {
 	var document entity.Document
 
@@ -100,6 +133,15 @@ func (r *documentRepository) FindByID(ctx context.Context, id string, tx *gorm.D
 }
 
 func (r *documentRepository) Create(ctx context.Context, document entity.Document, tx *gorm.DB) (entity.Document, error) {
+	// a caller owning a transaction gets the row created inside it
+	if tx != nil {
+		err := tx.WithContext(ctx).Create(&document).Error
+		if err != nil {
+			return entity.Document{}, translateError(err)
+		}
+
+		return document, nil
+	}
 
 	tx, err := r.baseRepository.BeginTx(ctx)
 	if err != nil {
@@ -142,7 +184,15 @@ func (r *documentRepository) Update(ctx context.Context, id string, document ent
 		return err
 	}
 
-	err = r.db.WithContext(ctx).Model(&entity.Document{}).Where("id = ?", id).Updates(document).Error
+	// the update only lands when nobody updated the row since it was read
+	expectedVersion := document.LockVersion
+	document.LockVersion = expectedVersion + 1
+
+	result := r.db.WithContext(ctx).Model(&entity.Document{}).Where("id = ?", id).Where("lock_version = ?", expectedVersion).Updates(document)
+	err = result.Error
+	if err == nil && result.RowsAffected == 0 {
+		err = NewStaleVersionError("document", id)
+	}
 	if tx != nil {
 		return err
 	}
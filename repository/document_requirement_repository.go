@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"registration-service/dto"
+	"registration-service/entity"
+
+	"gorm.io/gorm"
+)
+
+type documentRequirementRepository struct {
+	db             *gorm.DB
+	baseRepository BaseRepository
+}
+
+type DocumentRequirementRepository interface {
+	Index(ctx context.Context, filter dto.FilterDocumentRequirementRequest, tx *gorm.DB) ([]entity.DocumentRequirement, error)
+	FindByID(ctx context.Context, id string, tx *gorm.DB) (entity.DocumentRequirement, error)
+	FindByScope(ctx context.Context, activityID string, programTypeID string, tx *gorm.DB) ([]entity.DocumentRequirement, error)
+	FindByScopes(ctx context.Context, activityIDs []string, programTypeIDs []string, tx *gorm.DB) ([]entity.DocumentRequirement, error)
+	Create(ctx context.Context, requirement entity.DocumentRequirement, tx *gorm.DB) (entity.DocumentRequirement, error)
+	Update(ctx context.Context, id string, requirement entity.DocumentRequirement, tx *gorm.DB) error
+	Destroy(ctx context.Context, id string, tx *gorm.DB) error
+}
+
+func NewDocumentRequirementRepository(db *gorm.DB) DocumentRequirementRepository {
+	return &documentRequirementRepository{db: db, baseRepository: NewBaseRepository(db)}
+}
+
+func (r *documentRequirementRepository) Index(ctx context.Context, filter dto.FilterDocumentRequirementRequest, tx *gorm.DB) ([]entity.DocumentRequirement, error) {
+	var requirements []entity.DocumentRequirement
+	if tx == nil {
+		tx = r.db
+	}
+
+	query := tx.WithContext(ctx).
+		Model(&entity.DocumentRequirement{}).
+		Where("document_requirements.deleted_at IS NULL")
+
+	if filter.ProgramTypeID != "" {
+		query = query.Where("document_requirements.program_type_id = ?", filter.ProgramTypeID)
+	}
+
+	if filter.ActivityID != "" {
+		query = query.Where("document_requirements.activity_id = ?", filter.ActivityID)
+	}
+
+	err := query.
+		Order("program_type_id ASC, activity_id ASC, document_type ASC").
+		Find(&requirements).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return requirements, nil
+}
+
+func (r *documentRequirementRepository) FindByID(ctx context.Context, id string, tx *gorm.DB) (entity.DocumentRequirement, error) {
+	var requirement entity.DocumentRequirement
+	if tx == nil {
+		tx = r.db
+	}
+
+	err := tx.WithContext(ctx).
+		Model(&entity.DocumentRequirement{}).
+		Where("id = ?", id).
+		Where("document_requirements.deleted_at IS NULL").
+		First(&requirement).Error
+	if err != nil {
+		return entity.DocumentRequirement{}, err
+	}
+
+	return requirement, nil
+}
+
+// FindByScope returns every entry that could apply to the activity: entries
+// for the activity itself, for its program type and the default ones.
+func (r *documentRequirementRepository) FindByScope(ctx context.Context, activityID string, programTypeID string, tx *gorm.DB) ([]entity.DocumentRequirement, error) {
+	var requirements []entity.DocumentRequirement
+	if tx == nil {
+		tx = r.db
+	}
+
+	err := tx.WithContext(ctx).
+		Model(&entity.DocumentRequirement{}).
+		Where("document_requirements.deleted_at IS NULL").
+		Where(
+			r.db.Where("activity_id = ? AND activity_id <> ''", activityID).
+				Or("activity_id = '' AND program_type_id = ? AND program_type_id <> ''", programTypeID).
+				Or("activity_id = '' AND program_type_id = ''"),
+		).
+		Order("document_type ASC").
+		Find(&requirements).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return requirements, nil
+}
+
+// FindByScopes is FindByScope for several activities at once, it lets a
+// listing load the entries of a whole page with one query.
+func (r *documentRequirementRepository) FindByScopes(ctx context.Context, activityIDs []string, programTypeIDs []string, tx *gorm.DB) ([]entity.DocumentRequirement, error) {
+	var requirements []entity.DocumentRequirement
+	if tx == nil {
+		tx = r.db
+	}
+
+	scope := r.db.Where("activity_id = '' AND program_type_id = ''")
+	if len(activityIDs) > 0 {
+		scope = scope.Or("activity_id IN ? AND activity_id <> ''", activityIDs)
+	}
+	if len(programTypeIDs) > 0 {
+		scope = scope.Or("activity_id = '' AND program_type_id IN ? AND program_type_id <> ''", programTypeIDs)
+	}
+
+	err := tx.WithContext(ctx).
+		Model(&entity.DocumentRequirement{}).
+		Where("document_requirements.deleted_at IS NULL").
+		Where(scope).
+		Order("document_type ASC").
+		Find(&requirements).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return requirements, nil
+}
+
+func (r *documentRequirementRepository) Create(ctx context.Context, requirement entity.DocumentRequirement, tx *gorm.DB) (entity.DocumentRequirement, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	err := tx.WithContext(ctx).
+		Model(&entity.DocumentRequirement{}).
+		Create(&requirement).Error
+	if err != nil {
+		return entity.DocumentRequirement{}, err
+	}
+
+	return requirement, nil
+}
+
+func (r *documentRequirementRepository) Update(ctx context.Context, id string, requirement entity.DocumentRequirement, tx *gorm.DB) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).
+		Model(&entity.DocumentRequirement{}).
+		Where("id = ?", id).
+		Where("document_requirements.deleted_at IS NULL").
+		Select("program_type_id", "activity_id", "document_type", "description", "required", "allowed_mime_types", "max_size_bytes").
+		Updates(&requirement).Error
+}
+
+func (r *documentRequirementRepository) Destroy(ctx context.Context, id string, tx *gorm.DB) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	result := tx.WithContext(ctx).
+		Model(&entity.DocumentRequirement{}).
+		Where("id = ?", id).
+		Where("document_requirements.deleted_at IS NULL").
+		Update("deleted_at", gorm.Expr("NOW()"))
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("data not found")
+	}
+
+	return nil
+}
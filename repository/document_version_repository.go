@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"registration-service/entity"
+
+	"gorm.io/gorm"
+)
+
+type documentVersionRepository struct {
+	db             *gorm.DB
+	baseRepository BaseRepository
+}
+
+type DocumentVersionRepository interface {
+	Create(ctx context.Context, version entity.DocumentVersion, tx *gorm.DB) (entity.DocumentVersion, error)
+	FindByDocumentID(ctx context.Context, documentID string, tx *gorm.DB) ([]entity.DocumentVersion, error)
+	FindByDocumentIDAndVersion(ctx context.Context, documentID string, version int, tx *gorm.DB) (entity.DocumentVersion, error)
+	DeleteByID(ctx context.Context, id string, tx *gorm.DB) error
+}
+
+func NewDocumentVersionRepository(db *gorm.DB) DocumentVersionRepository {
+	return &documentVersionRepository{db: db, baseRepository: NewBaseRepository(db)}
+}
+
+func (r *documentVersionRepository) Create(ctx context.Context, version entity.DocumentVersion, tx *gorm.DB) (entity.DocumentVersion, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	err := tx.WithContext(ctx).
+		Model(&entity.DocumentVersion{}).
+		Create(&version).Error
+	if err != nil {
+		return entity.DocumentVersion{}, err
+	}
+
+	return version, nil
+}
+
+// FindByDocumentID returns the versions of a document, newest first
+func (r *documentVersionRepository) FindByDocumentID(ctx context.Context, documentID string, tx *gorm.DB) ([]entity.DocumentVersion, error) {
+	var versions []entity.DocumentVersion
+	if tx == nil {
+		tx = r.db
+	}
+
+	err := tx.WithContext(ctx).
+		Model(&entity.DocumentVersion{}).
+		Where("document_id = ?", documentID).
+		Where("document_versions.deleted_at IS NULL").
+		Order("version DESC").
+		Find(&versions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+func (r *documentVersionRepository) FindByDocumentIDAndVersion(ctx context.Context, documentID string, version int, tx *gorm.DB) (entity.DocumentVersion, error) {
+	var documentVersion entity.DocumentVersion
+	if tx == nil {
+		tx = r.db
+	}
+
+	err := tx.WithContext(ctx).
+		Model(&entity.DocumentVersion{}).
+		Where("document_id = ?", documentID).
+		Where("version = ?", version).
+		Where("document_versions.deleted_at IS NULL").
+		First(&documentVersion).Error
+	if err != nil {
+		return entity.DocumentVersion{}, err
+	}
+
+	return documentVersion, nil
+}
+
+func (r *documentVersionRepository) DeleteByID(ctx context.Context, id string, tx *gorm.DB) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).
+		Where("id = ?", id).
+		Delete(&entity.DocumentVersion{}).Error
+}
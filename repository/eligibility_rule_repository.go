@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"registration-service/dto"
+	"registration-service/entity"
+
+	"gorm.io/gorm"
+)
+
+type eligibilityRuleRepository struct {
+	db             *gorm.DB
+	baseRepository BaseRepository
+}
+
+type EligibilityRuleRepository interface {
+	Index(ctx context.Context, filter dto.FilterEligibilityRuleRequest, tx *gorm.DB) ([]entity.EligibilityRule, error)
+	FindByID(ctx context.Context, id string, tx *gorm.DB) (entity.EligibilityRule, error)
+	FindByScope(ctx context.Context, programTypeID string, levelID string, tx *gorm.DB) ([]entity.EligibilityRule, error)
+	Create(ctx context.Context, rule entity.EligibilityRule, tx *gorm.DB) (entity.EligibilityRule, error)
+	Update(ctx context.Context, id string, rule entity.EligibilityRule, tx *gorm.DB) error
+	Destroy(ctx context.Context, id string, tx *gorm.DB) error
+}
+
+func NewEligibilityRuleRepository(db *gorm.DB) EligibilityRuleRepository {
+	return &eligibilityRuleRepository{db: db, baseRepository: NewBaseRepository(db)}
+}
+
+func (r *eligibilityRuleRepository) Index(ctx context.Context, filter dto.FilterEligibilityRuleRequest, tx *gorm.DB) ([]entity.EligibilityRule, error) {
+	var rules []entity.EligibilityRule
+	if tx == nil {
+		tx = r.db
+	}
+
+	query := tx.WithContext(ctx).
+		Model(&entity.EligibilityRule{}).
+		Where("eligibility_rules.deleted_at IS NULL")
+
+	if filter.ProgramTypeID != "" {
+		query = query.Where("eligibility_rules.program_type_id = ?", filter.ProgramTypeID)
+	}
+
+	if filter.LevelID != "" {
+		query = query.Where("eligibility_rules.level_id = ?", filter.LevelID)
+	}
+
+	if filter.Rule != "" {
+		query = query.Where("eligibility_rules.rule = ?", filter.Rule)
+	}
+
+	err := query.
+		Order("program_type_id ASC, level_id ASC, rule ASC").
+		Find(&rules).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+func (r *eligibilityRuleRepository) FindByID(ctx context.Context, id string, tx *gorm.DB) (entity.EligibilityRule, error) {
+	var rule entity.EligibilityRule
+	if tx == nil {
+		tx = r.db
+	}
+
+	err := tx.WithContext(ctx).
+		Model(&entity.EligibilityRule{}).
+		Where("id = ?", id).
+		Where("eligibility_rules.deleted_at IS NULL").
+		First(&rule).Error
+	if err != nil {
+		return entity.EligibilityRule{}, err
+	}
+
+	return rule, nil
+}
+
+// FindByScope returns every entry that could apply to an activity of the
+// program type and level, including the default entries without a scope
+func (r *eligibilityRuleRepository) FindByScope(ctx context.Context, programTypeID string, levelID string, tx *gorm.DB) ([]entity.EligibilityRule, error) {
+	var rules []entity.EligibilityRule
+	if tx == nil {
+		tx = r.db
+	}
+
+	err := tx.WithContext(ctx).
+		Model(&entity.EligibilityRule{}).
+		Where("eligibility_rules.deleted_at IS NULL").
+		Where("program_type_id = '' OR program_type_id = ?", programTypeID).
+		Where("level_id = '' OR level_id = ?", levelID).
+		Order("created_at ASC").
+		Find(&rules).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+func (r *eligibilityRuleRepository) Create(ctx context.Context, rule entity.EligibilityRule, tx *gorm.DB) (entity.EligibilityRule, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	err := tx.WithContext(ctx).
+		Model(&entity.EligibilityRule{}).
+		Create(&rule).Error
+	if err != nil {
+		return entity.EligibilityRule{}, err
+	}
+
+	return rule, nil
+}
+
+func (r *eligibilityRuleRepository) Update(ctx context.Context, id string, rule entity.EligibilityRule, tx *gorm.DB) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).
+		Model(&entity.EligibilityRule{}).
+		Where("id = ?", id).
+		Where("eligibility_rules.deleted_at IS NULL").
+		Select("program_type_id", "level_id", "rule", "value", "severity", "enabled", "description").
+		Updates(&rule).Error
+}
+
+func (r *eligibilityRuleRepository) Destroy(ctx context.Context, id string, tx *gorm.DB) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	result := tx.WithContext(ctx).
+		Model(&entity.EligibilityRule{}).
+		Where("id = ?", id).
+		Where("eligibility_rules.deleted_at IS NULL").
+		Update("deleted_at", gorm.Expr("NOW()"))
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("data not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	// postgres SQLSTATE raised when a unique index rejects a row
+	PG_UNIQUE_VIOLATION = "23505"
+
+	REGISTRATION_ACTIVITY_NRP_INDEX = "idx_registrations_activity_id_user_nrp"
+	ACADEMIC_PERIOD_YEAR_TERM_INDEX = "idx_academic_periods_year_term"
+)
+
+// ErrConflict matches every ConflictError with errors.Is
+var ErrConflict = errors.New("conflict")
+
+// ConflictError is returned when a write clashes with a row that already
+// exists, either rejected by a unique index or found by a service check
+type ConflictError struct {
+	Constraint string
+	Message    string
+}
+
+func NewConflictError(message string) *ConflictError {
+	return &ConflictError{Message: message}
+}
+
+func (e *ConflictError) Error() string {
+	return e.Message
+}
+
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// ErrStaleVersion matches every StaleVersionError with errors.Is
+var ErrStaleVersion = errors.New("stale version")
+
+// StaleVersionError is returned when a row changed after it was read, the
+// write is dropped and the client has to reload the row. It is a conflict as
+// well.
+type StaleVersionError struct {
+	Entity string
+	ID     string
+}
+
+func NewStaleVersionError(entity string, id string) *StaleVersionError {
+	return &StaleVersionError{Entity: entity, ID: id}
+}
+
+func (e *StaleVersionError) Error() string {
+	return fmt.Sprintf("%s %s was changed in the meantime, reload it and try again", e.Entity, e.ID)
+}
+
+func (e *StaleVersionError) Is(target error) bool {
+	return target == ErrStaleVersion || target == ErrConflict
+}
+
+var conflictMessages = map[string]string{
+	REGISTRATION_ACTIVITY_NRP_INDEX: "user already registered",
+	ACADEMIC_PERIOD_YEAR_TERM_INDEX: "academic period already exists",
+}
+
+// translateError turns a unique violation into a ConflictError and leaves
+// every other error as it is
+func translateError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != PG_UNIQUE_VIOLATION {
+		return err
+	}
+
+	message, ok := conflictMessages[pgErr.ConstraintName]
+	if !ok {
+		message = "record already exists"
+	}
+
+	return &ConflictError{Constraint: pgErr.ConstraintName, Message: message}
+}
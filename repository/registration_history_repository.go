@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"registration-service/entity"
+
+	"gorm.io/gorm"
+)
+
+type registrationHistoryRepository struct {
+	db             *gorm.DB
+	baseRepository BaseRepository
+}
+
+type RegistrationHistoryRepository interface {
+	Create(ctx context.Context, history entity.RegistrationHistory, tx *gorm.DB) (entity.RegistrationHistory, error)
+	FindByRegistrationID(ctx context.Context, registrationID string, tx *gorm.DB) ([]entity.RegistrationHistory, error)
+}
+
+func NewRegistrationHistoryRepository(db *gorm.DB) RegistrationHistoryRepository {
+	return &registrationHistoryRepository{db: db, baseRepository: NewBaseRepository(db)}
+}
+
+func (r *registrationHistoryRepository) Create(ctx context.Context, history entity.RegistrationHistory, tx *gorm.DB) (entity.RegistrationHistory, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	err := tx.WithContext(ctx).
+		Model(&entity.RegistrationHistory{}).
+		Create(&history).Error
+	if err != nil {
+		return entity.RegistrationHistory{}, err
+	}
+
+	return history, nil
+}
+
+func (r *registrationHistoryRepository) FindByRegistrationID(ctx context.Context, registrationID string, tx *gorm.DB) ([]entity.RegistrationHistory, error) {
+	var histories []entity.RegistrationHistory
+	if tx == nil {
+		tx = r.db
+	}
+
+	err := tx.WithContext(ctx).
+		Model(&entity.RegistrationHistory{}).
+		Where("registration_id = ?", registrationID).
+		Where("registration_histories.deleted_at IS NULL").
+		Order("created_at ASC").
+		Find(&histories).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return histories, nil
+}
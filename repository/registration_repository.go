@@ -5,11 +5,36 @@ import (
 	"errors"
 	"registration-service/dto"
 	"registration-service/entity"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// REGISTRATION_NRP_LOCK_NAMESPACE is the first key of the advisory locks taken
+// per student, the second key is the hash of the NRP
+const REGISTRATION_NRP_LOCK_NAMESPACE = 1001
+
+// REGISTRATION_SEARCH_VECTOR is the document the full-text search runs on, the
+// simple configuration is used because the names are not English
+const REGISTRATION_SEARCH_VECTOR = "to_tsvector('simple', coalesce(registrations.activity_name, '') || ' ' || coalesce(registrations.user_name, '') || ' ' || coalesce(registrations.academic_advisor, '') || ' ' || coalesce(registrations.mentor_name, ''))"
+
+var registrationSortColumns = map[string]string{
+	"created_at":       "registrations.created_at",
+	"updated_at":       "registrations.updated_at",
+	"activity_name":    "registrations.activity_name",
+	"user_name":        "registrations.user_name",
+	"user_nrp":         "registrations.user_nrp",
+	"academic_advisor": "registrations.academic_advisor",
+	"semester":         "registrations.semester",
+	"total_sks":        "registrations.total_sks",
+	"status":           "registrations.status",
+	"approval_status":  "registrations.approval_status",
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 type registrationRepository struct {
 	db             *gorm.DB
 	baseRepository BaseRepository
@@ -17,27 +42,55 @@ type registrationRepository struct {
 
 type RegistrationRepository interface {
 	Index(ctx context.Context, tx *gorm.DB, pagReq dto.PaginationRequest, filter dto.FilterRegistrationRequest) ([]entity.Registration, int64, error)
+	IndexKeyset(ctx context.Context, tx *gorm.DB, pagReq dto.PaginationRequest, filter dto.FilterRegistrationRequest) ([]entity.Registration, bool, int64, error)
+	IndexIDs(ctx context.Context, tx *gorm.DB, limit int, filter dto.FilterRegistrationRequest) ([]string, int64, error)
+	FindByIDs(ctx context.Context, ids []string, tx *gorm.DB) ([]entity.Registration, error)
 	Create(ctx context.Context, registration entity.Registration, tx *gorm.DB) (entity.Registration, error)
 	Update(ctx context.Context, id string, registration entity.Registration, tx *gorm.DB) error
 	FindByID(ctx context.Context, id string, tx *gorm.DB) (entity.Registration, error)
 	Destroy(ctx context.Context, id string, tx *gorm.DB) error
+	IndexDeleted(ctx context.Context, pagReq dto.PaginationRequest, tx *gorm.DB) ([]entity.Registration, int64, error)
+	FindDeletedByID(ctx context.Context, id string, tx *gorm.DB) (entity.Registration, error)
+	FindDeletedBefore(ctx context.Context, before time.Time, after *dto.Cursor, limit int, tx *gorm.DB) ([]entity.Registration, error)
+	LockDeleted(ctx context.Context, id string, tx *gorm.DB) (entity.Registration, error)
+	Lock(ctx context.Context, id string, tx *gorm.DB) (entity.Registration, error)
+	Restore(ctx context.Context, id string, tx *gorm.DB) error
+	Purge(ctx context.Context, id string, tx *gorm.DB) error
 	FilterSubQuery(ctx context.Context, tx *gorm.DB, filter dto.FilterRegistrationRequest) *gorm.DB
 	FindTotal(ctx context.Context, filter dto.FilterRegistrationRequest, tx *gorm.DB) (int64, error)
 	FindRegistrationByAdvisiorEmail(ctx context.Context, email string, tx *gorm.DB) (entity.Registration, error)
+	FindByAdvisorEmail(ctx context.Context, email string, tx *gorm.DB) ([]entity.Registration, error)
 	FindByNRP(ctx context.Context, nrp string, tx *gorm.DB) (entity.Registration, error)
+	FindActiveByNRP(ctx context.Context, nrp string, tx *gorm.DB) ([]entity.Registration, error)
 	FindByActivityIDAndNRP(ctx context.Context, activityID string, nrp string, tx *gorm.DB) (entity.Registration, error)
-	FindTotalRegistrationByAdvisorEmail(ctx context.Context, email string, tx *gorm.DB) (entity.RegistrationCount, error)
+	FindByActivityID(ctx context.Context, activityID string, tx *gorm.DB) ([]entity.Registration, error)
+	FindTotalRegistrationByAdvisorEmail(ctx context.Context, email string, academicPeriodID string, tx *gorm.DB) (entity.RegistrationCount, error)
+	WithTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error
+	LockNRP(ctx context.Context, nrp string, tx *gorm.DB) error
 }
 
 func NewRegistrationRepository(db *gorm.DB) RegistrationRepository {
 	return &registrationRepository{db: db, baseRepository: NewBaseRepository(db)}
 }
 
-func (r *registrationRepository) FindTotalRegistrationByAdvisorEmail(ctx context.Context, email string, tx *gorm.DB) (entity.RegistrationCount, error) {
+// FindTotalRegistrationByAdvisorEmail counts the registrations of an academic
+// advisor, limited to one academic period when academicPeriodID is set
+func (r *registrationRepository) FindTotalRegistrationByAdvisorEmail(ctx context.Context, email string, academicPeriodID string, tx *gorm.DB) (entity.RegistrationCount, error) {
 	if tx == nil {
 		tx = r.db
 	}
 
+	advisorRegistrations := func() *gorm.DB {
+		query := tx.WithContext(ctx).
+			Model(&entity.Registration{}).
+			Where("academic_advisor_email = ?", email)
+		if academicPeriodID != "" {
+			query = query.Where("academic_period_id = ?", academicPeriodID)
+		}
+
+		return query
+	}
+
 	var registrationCount entity.RegistrationCount
 	var total int64
 	var totalApproved int64
@@ -48,18 +101,14 @@ func (r *registrationRepository) FindTotalRegistrationByAdvisorEmail(ctx context
 	var thisMonthApproved int64
 	var lastMonthApproved int64
 
-	err := tx.WithContext(ctx).
-		Model(&entity.Registration{}).
-		Where("academic_advisor_email = ?", email).
+	err := advisorRegistrations().
 		Count(&total).Error
 
 	if err != nil {
 		return entity.RegistrationCount{}, err
 	}
 
-	err = tx.WithContext(ctx).
-		Model(&entity.Registration{}).
-		Where("academic_advisor_email = ?", email).
+	err = advisorRegistrations().
 		Where("approval_status = ?", true).
 		Count(&totalApproved).Error
 
@@ -68,9 +117,7 @@ func (r *registrationRepository) FindTotalRegistrationByAdvisorEmail(ctx context
 	}
 
 	// get total percentage of this month
-	err = tx.WithContext(ctx).
-		Model(&entity.Registration{}).
-		Where("academic_advisor_email = ?", email).
+	err = advisorRegistrations().
 		Where("created_at BETWEEN ? AND ?", time.Now().AddDate(0, -1, 0), time.Now()).
 		Count(&thisMonth).Error
 
@@ -79,9 +126,7 @@ func (r *registrationRepository) FindTotalRegistrationByAdvisorEmail(ctx context
 	}
 
 	// get total percentage of last month
-	err = tx.WithContext(ctx).
-		Model(&entity.Registration{}).
-		Where("academic_advisor_email = ?", email).
+	err = advisorRegistrations().
 		Where("created_at BETWEEN ? AND ?", time.Now().AddDate(0, -2, 0), time.Now().AddDate(0, -1, 0)).
 		Count(&lastMonth).Error
 
@@ -96,9 +141,7 @@ func (r *registrationRepository) FindTotalRegistrationByAdvisorEmail(ctx context
 	}
 
 	// get total percentage of approved this month
-	err = tx.WithContext(ctx).
-		Model(&entity.Registration{}).
-		Where("academic_advisor_email = ?", email).
+	err = advisorRegistrations().
 		Where("created_at BETWEEN ? AND ?", time.Now().AddDate(0, -1, 0), time.Now()).
 		Where("approval_status = ?", true).
 		Count(&thisMonthApproved).Error
@@ -108,9 +151,7 @@ func (r *registrationRepository) FindTotalRegistrationByAdvisorEmail(ctx context
 	}
 
 	// get total percentage of approved last month
-	err = tx.WithContext(ctx).
-		Model(&entity.Registration{}).
-		Where("academic_advisor_email = ?", email).
+	err = advisorRegistrations().
 		Where("created_at BETWEEN ? AND ?", time.Now().AddDate(0, -2, 0), time.Now().AddDate(0, -1, 0)).
 		Where("approval_status = ?", true).
 		Count(&lastMonthApproved).Error
@@ -148,6 +189,28 @@ func (r *registrationRepository) FindByActivityIDAndNRP(ctx context.Context, act
 	return registration, err
 }
 
+// FindByActivityID returns the submitted registrations of an activity with their documents
+func (r *registrationRepository) FindByActivityID(ctx context.Context, activityID string, tx *gorm.DB) ([]entity.Registration, error) {
+	var registrations []entity.Registration
+	if tx == nil {
+		tx = r.db
+	}
+
+	err := tx.WithContext(ctx).
+		Preload("Document").
+		Model(&entity.Registration{}).
+		Where("activity_id = ?", activityID).
+		Where("registrations.status <> ?", entity.REGISTRATION_STATUS_DRAFT).
+		Where("registrations.deleted_at IS NULL").
+		Order("user_nrp ASC").
+		Find(&registrations).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return registrations, nil
+}
+
 func (r *registrationRepository) FindByNRP(ctx context.Context, nrp string, tx *gorm.DB) (entity.Registration, error) {
 	var registration entity.Registration
 	if tx == nil {
@@ -164,6 +227,27 @@ func (r *registrationRepository) FindByNRP(ctx context.Context, nrp string, tx *
 	return registration, err
 }
 
+// FindActiveByNRP returns every registration of the student that still holds
+// its activity period, drafts, withdrawn and rejected registrations are left
+// out
+func (r *registrationRepository) FindActiveByNRP(ctx context.Context, nrp string, tx *gorm.DB) ([]entity.Registration, error) {
+	var registrations []entity.Registration
+	if tx == nil {
+		tx = r.db
+	}
+
+	err := tx.WithContext(ctx).
+		Model(&entity.Registration{}).
+		Where("user_nrp = ?", nrp).
+		Where("status NOT IN ?", []string{entity.REGISTRATION_STATUS_WITHDRAWN, entity.REGISTRATION_STATUS_DRAFT}).
+		Where("lo_validation <> ?", "REJECTED").
+		Where("academic_advisor_validation <> ?", "REJECTED").
+		Order("created_at DESC").
+		Find(&registrations).Error
+
+	return registrations, err
+}
+
 func (r *registrationRepository) FindRegistrationByAdvisiorEmail(ctx context.Context, email string, tx *gorm.DB) (entity.Registration, error) {
 	var registration entity.Registration
 	if tx == nil {
@@ -184,6 +268,28 @@ func (r *registrationRepository) FindRegistrationByAdvisiorEmail(ctx context.Con
 	return registration, nil
 }
 
+// FindByAdvisorEmail returns the registrations of the academic advisor that
+// are not withdrawn yet, oldest first
+func (r *registrationRepository) FindByAdvisorEmail(ctx context.Context, email string, tx *gorm.DB) ([]entity.Registration, error) {
+	var registrations []entity.Registration
+	if tx == nil {
+		tx = r.db
+	}
+
+	err := tx.WithContext(ctx).
+		Model(&entity.Registration{}).
+		Where("academic_advisor_email = ?", email).
+		Where("status <> ?", entity.REGISTRATION_STATUS_WITHDRAWN).
+		Where("registrations.deleted_at IS NULL").
+		Order("created_at ASC").
+		Find(&registrations).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return registrations, nil
+}
+
 func (r *registrationRepository) FindTotal(ctx context.Context, filter dto.FilterRegistrationRequest, tx *gorm.DB) (int64, error) {
 	var total int64
 	if tx == nil {
@@ -206,13 +312,12 @@ func (r *registrationRepository) Index(ctx context.Context, tx *gorm.DB, pagReq
 		tx = r.db
 	}
 
-	subQuery := r.FilterSubQuery(ctx, tx, filter)
+	subQuery := orderSubQuery(r.FilterSubQuery(ctx, tx, filter), filter)
 
 	err := subQuery.
 		Preload("Document").
 		Offset(pagReq.Offset).
 		Limit(pagReq.Limit).
-		Order("created_at DESC").
 		Where("registrations.deleted_at IS NULL").
 		Find(&registrations).Error
 
@@ -228,7 +333,109 @@ func (r *registrationRepository) Index(ctx context.Context, tx *gorm.DB, pagReq
 	return registrations, total, nil
 }
 
+// IndexKeyset reads one cursor page, the bool tells whether more rows follow
+// in the direction of the page. The total is only counted when asked for.
+func (r *registrationRepository) IndexKeyset(ctx context.Context, tx *gorm.DB, pagReq dto.PaginationRequest, filter dto.FilterRegistrationRequest) ([]entity.Registration, bool, int64, error) {
+	var registrations []entity.Registration
+	if tx == nil {
+		tx = r.db
+	}
+
+	if filter.SortBy != "" && filter.SortBy != "created_at" {
+		return nil, false, 0, errors.New("cursor pagination only supports sorting by created_at")
+	}
+
+	descending := !strings.EqualFold(filter.SortDirection, "asc")
+	subQuery := keysetPage(r.FilterSubQuery(ctx, tx, filter), "registrations", pagReq, descending)
+
+	err := subQuery.
+		Preload("Document").
+		Find(&registrations).Error
+	if err != nil {
+		return nil, false, 0, err
+	}
+
+	registrations, hasMore := keysetRows(registrations, pagReq)
+
+	var total int64
+	if pagReq.WithTotal {
+		total, err = r.FindTotal(ctx, filter, tx)
+		if err != nil {
+			return nil, false, 0, err
+		}
+	}
+
+	return registrations, hasMore, total, nil
+}
+
+// IndexIDs returns the ids of the rows Index would list, in the same order and
+// up to limit, with the total matching the filter
+func (r *registrationRepository) IndexIDs(ctx context.Context, tx *gorm.DB, limit int, filter dto.FilterRegistrationRequest) ([]string, int64, error) {
+	var ids []string
+	if tx == nil {
+		tx = r.db
+	}
+
+	err := orderSubQuery(r.FilterSubQuery(ctx, tx, filter), filter).
+		Limit(limit).
+		Pluck("registrations.id", &ids).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := r.FindTotal(ctx, filter, tx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return ids, total, nil
+}
+
+// FindByIDs loads the registrations without their documents, in the order of
+// ids. Registrations deleted in the meantime are left out.
+func (r *registrationRepository) FindByIDs(ctx context.Context, ids []string, tx *gorm.DB) ([]entity.Registration, error) {
+	var registrations []entity.Registration
+	if tx == nil {
+		tx = r.db
+	}
+
+	err := tx.WithContext(ctx).
+		Model(&entity.Registration{}).
+		Where("id IN ?", ids).
+		Where("registrations.deleted_at IS NULL").
+		Find(&registrations).Error
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]entity.Registration, len(registrations))
+	for _, registration := range registrations {
+		byID[registration.ID.String()] = registration
+	}
+
+	ordered := make([]entity.Registration, 0, len(registrations))
+	for _, id := range ids {
+		if registration, ok := byID[id]; ok {
+			ordered = append(ordered, registration)
+		}
+	}
+
+	return ordered, nil
+}
+
 func (r *registrationRepository) Create(ctx context.Context, registration entity.Registration, tx *gorm.DB) (entity.Registration, error) {
+	// a caller owning a transaction gets the row created inside it
+	if tx != nil {
+		err := tx.WithContext(ctx).
+			Model(&entity.Registration{}).
+			Create(&registration).Error
+		if err != nil {
+			return entity.Registration{}, translateError(err)
+		}
+
+		return registration, nil
+	}
+
 	tx, err := r.baseRepository.BeginTx(ctx)
 	if err != nil {
 		return entity.Registration{}, err
@@ -244,7 +451,7 @@ func (r *registrationRepository) Create(ctx context.Context, registration entity
 		Model(&entity.Registration{}).
 		Create(&registration).Error
 	if err != nil {
-		return entity.Registration{}, err
+		return entity.Registration{}, translateError(err)
 	}
 
 	r.baseRepository.CommitTx(ctx, tx)
@@ -252,18 +459,19 @@ func (r *registrationRepository) Create(ctx context.Context, registration entity
 	return registration, nil
 }
 
+// Update writes the registration through tx when the caller runs a
+// transaction, otherwise in a transaction of its own
 func (r *registrationRepository) Update(ctx context.Context, id string, registration entity.Registration, tx *gorm.DB) error {
-	tx, err := r.baseRepository.BeginTx(ctx)
-	if err != nil {
-		return err
+	if tx != nil {
+		return r.update(ctx, id, registration, tx)
 	}
 
-	defer func() {
-		if err != nil {
-			r.baseRepository.RollbackTx(ctx, tx)
-		}
-	}()
+	return r.WithTransaction(ctx, func(tx *gorm.DB) error {
+		return r.update(ctx, id, registration, tx)
+	})
+}
 
+func (r *registrationRepository) update(ctx context.Context, id string, registration entity.Registration, tx *gorm.DB) error {
 	data, err := r.FindByID(ctx, id, tx)
 	if err != nil {
 		return err
@@ -273,17 +481,24 @@ func (r *registrationRepository) Update(ctx context.Context, id string, registra
 		return nil
 	}
 
-	err = r.db.WithContext(ctx).
+	// the update only lands when nobody updated the row since it was read
+	expectedVersion := registration.LockVersion
+	registration.LockVersion = expectedVersion + 1
+
+	result := tx.WithContext(ctx).
 		Model(&entity.Registration{}).
 		Where("id = ?", id).
+		Where("lock_version = ?", expectedVersion).
 		Select("*").
-		Updates(&registration).Error
+		Updates(&registration)
+	if result.Error != nil {
+		return result.Error
+	}
 
-	if err != nil {
-		return err
+	if result.RowsAffected == 0 {
+		return NewStaleVersionError("registration", id)
 	}
 
-	r.baseRepository.CommitTx(ctx, tx)
 	return nil
 }
 
@@ -307,18 +522,19 @@ func (r *registrationRepository) FindByID(ctx context.Context, id string, tx *go
 	return registration, nil
 }
 
+// Destroy soft deletes the registration, through tx when the caller runs a
+// transaction
 func (r *registrationRepository) Destroy(ctx context.Context, id string, tx *gorm.DB) error {
-	tx, err := r.baseRepository.BeginTx(ctx)
-	if err != nil {
-		return err
+	if tx != nil {
+		return r.destroy(ctx, id, tx)
 	}
 
-	defer func() {
-		if err != nil {
-			r.baseRepository.RollbackTx(ctx, tx)
-		}
-	}()
+	return r.WithTransaction(ctx, func(tx *gorm.DB) error {
+		return r.destroy(ctx, id, tx)
+	})
+}
 
+func (r *registrationRepository) destroy(ctx context.Context, id string, tx *gorm.DB) error {
 	data, err := r.FindByID(ctx, id, tx)
 	if err != nil {
 		return err
@@ -328,21 +544,223 @@ func (r *registrationRepository) Destroy(ctx context.Context, id string, tx *gor
 		return errors.New("data not found")
 	}
 
-	err = r.db.WithContext(ctx).
+	return tx.WithContext(ctx).
 		Model(&entity.Registration{}).
 		Where("id = ?", id).
 		Update("deleted_at", gorm.Expr("NOW()")).
 		Error
+}
 
+// IndexDeleted reads a page of the trash, most recently deleted first
+func (r *registrationRepository) IndexDeleted(ctx context.Context, pagReq dto.PaginationRequest, tx *gorm.DB) ([]entity.Registration, int64, error) {
+	var registrations []entity.Registration
+	var total int64
+	if tx == nil {
+		tx = r.db
+	}
+
+	err := tx.WithContext(ctx).
+		Unscoped().
+		Model(&entity.Registration{}).
+		Where("registrations.deleted_at IS NOT NULL").
+		Count(&total).Error
 	if err != nil {
-		return err
+		return nil, 0, err
 	}
 
-	r.baseRepository.CommitTx(ctx, tx)
+	err = tx.WithContext(ctx).
+		Unscoped().
+		Preload("Document").
+		Model(&entity.Registration{}).
+		Where("registrations.deleted_at IS NOT NULL").
+		Order("registrations.deleted_at DESC").
+		Offset(pagReq.Offset).
+		Limit(pagReq.Limit).
+		Find(&registrations).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return registrations, total, nil
+}
+
+func (r *registrationRepository) FindDeletedByID(ctx context.Context, id string, tx *gorm.DB) (entity.Registration, error) {
+	var registration entity.Registration
+	if tx == nil {
+		tx = r.db
+	}
+
+	err := tx.WithContext(ctx).
+		Unscoped().
+		Preload("Document").
+		Model(&entity.Registration{}).
+		Where("id = ?", id).
+		Where("registrations.deleted_at IS NOT NULL").
+		First(&registration).Error
+	if err != nil {
+		return entity.Registration{}, err
+	}
+
+	return registration, nil
+}
+
+// FindDeletedBefore returns up to limit registrations deleted before the
+// given time, the ones deleted first come first. after is the deleted_at and
+// id of the last registration of the previous batch, so rows that stay in the
+// trash don't come back in every batch.
+func (r *registrationRepository) FindDeletedBefore(ctx context.Context, before time.Time, after *dto.Cursor, limit int, tx *gorm.DB) ([]entity.Registration, error) {
+	var registrations []entity.Registration
+	if tx == nil {
+		tx = r.db
+	}
+
+	query := tx.WithContext(ctx).
+		Unscoped().
+		Model(&entity.Registration{}).
+		Where("registrations.deleted_at IS NOT NULL").
+		Where("registrations.deleted_at < ?", before)
+	if after != nil {
+		query = query.Where("(registrations.deleted_at, registrations.id) > (?, ?)", after.CreatedAt, after.ID)
+	}
+
+	err := query.
+		Order("registrations.deleted_at ASC, registrations.id ASC").
+		Limit(limit).
+		Find(&registrations).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return registrations, nil
+}
+
+// LockDeleted reads a deleted registration and locks its row until tx ends.
+// Purge and restore both take the lock, so a registration can't be restored
+// while the purge is deleting its files.
+func (r *registrationRepository) LockDeleted(ctx context.Context, id string, tx *gorm.DB) (entity.Registration, error) {
+	var registration entity.Registration
+	if tx == nil {
+		tx = r.db
+	}
+
+	err := tx.WithContext(ctx).
+		Unscoped().
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Document").
+		Model(&entity.Registration{}).
+		Where("id = ?", id).
+		Where("registrations.deleted_at IS NOT NULL").
+		First(&registration).Error
+	if err != nil {
+		return entity.Registration{}, err
+	}
+
+	return registration, nil
+}
+
+// Lock reads a registration that isn't deleted and locks its row until tx
+// ends, so it can't be edited or deleted while it is anonymized.
+func (r *registrationRepository) Lock(ctx context.Context, id string, tx *gorm.DB) (entity.Registration, error) {
+	var registration entity.Registration
+	if tx == nil {
+		tx = r.db
+	}
+
+	err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Document").
+		Model(&entity.Registration{}).
+		Where("id = ?", id).
+		First(&registration).Error
+	if err != nil {
+		return entity.Registration{}, err
+	}
+
+	return registration, nil
+}
+
+// Restore takes a registration out of the trash. It fails with a
+// ConflictError when the student registered for the activity again meanwhile.
+func (r *registrationRepository) Restore(ctx context.Context, id string, tx *gorm.DB) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	result := tx.WithContext(ctx).
+		Unscoped().
+		Model(&entity.Registration{}).
+		Where("id = ?", id).
+		Where("deleted_at IS NOT NULL").
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("data not found")
+	}
 
 	return nil
 }
 
+// Purge removes a deleted registration for good together with its documents,
+// their versions and its history. The stored files have to be deleted first.
+func (r *registrationRepository) Purge(ctx context.Context, id string, tx *gorm.DB) error {
+	purge := func(tx *gorm.DB) error {
+		err := purgeDocuments(tx, id)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Unscoped().
+			Where("registration_id = ?", id).
+			Delete(&entity.RegistrationHistory{}).Error
+		if err != nil {
+			return err
+		}
+
+		result := tx.Unscoped().
+			Where("id = ?", id).
+			Where("deleted_at IS NOT NULL").
+			Delete(&entity.Registration{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errors.New("data not found")
+		}
+
+		return nil
+	}
+
+	if tx != nil {
+		return purge(tx.WithContext(ctx))
+	}
+
+	return r.WithTransaction(ctx, purge)
+}
+
+// purgeDocuments removes the documents of a registration for good, their
+// versions included
+func purgeDocuments(tx *gorm.DB, registrationID string) error {
+	documentIDs := tx.Unscoped().
+		Model(&entity.Document{}).
+		Select("id::text").
+		Where("registration_id = ?", registrationID)
+
+	err := tx.Unscoped().
+		Where("document_id IN (?)", documentIDs).
+		Delete(&entity.DocumentVersion{}).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Unscoped().
+		Where("registration_id = ?", registrationID).
+		Delete(&entity.Document{}).Error
+}
+
 func (r *registrationRepository) FilterSubQuery(ctx context.Context, tx *gorm.DB, filter dto.FilterRegistrationRequest) *gorm.DB {
 	subQuery := tx.WithContext(ctx).
 		Model(&entity.Registration{}).
@@ -357,24 +775,162 @@ func (r *registrationRepository) FilterSubQuery(ctx context.Context, tx *gorm.DB
 	}
 
 	if filter.ActivityName != "" {
-		subQuery = subQuery.Where("registrations.activity_name = ?", filter.ActivityName)
+		subQuery = subQuery.Where("registrations.activity_name ILIKE ?", likePattern(filter.ActivityName))
 	}
 
 	if filter.UserName != "" {
-		subQuery = subQuery.Where("registrations.user_name = ?", filter.UserName)
+		subQuery = subQuery.Where("registrations.user_name ILIKE ?", likePattern(filter.UserName))
 	}
 
 	if filter.UserNRP != "" {
 		subQuery = subQuery.Where("registrations.user_nrp = ?", filter.UserNRP)
 	}
 
+	if filter.UserNRPLike != "" {
+		subQuery = subQuery.Where("registrations.user_nrp ILIKE ?", likePattern(filter.UserNRPLike))
+	}
+
+	if filter.AcademicAdvisor != "" {
+		subQuery = subQuery.Where("registrations.academic_advisor ILIKE ?", likePattern(filter.AcademicAdvisor))
+	}
+
 	if filter.AcademicAdvisorEmail != "" {
 		subQuery = subQuery.Where("registrations.academic_advisor_email = ?", filter.AcademicAdvisorEmail)
 	}
 
-	if filter.ApprovalStatus {
-		subQuery = subQuery.Where("registrations.approval_status = ?", filter.ApprovalStatus)
+	if len(filter.AcademicAdvisorEmails) > 0 {
+		subQuery = subQuery.Where("registrations.academic_advisor_email IN ?", filter.AcademicAdvisorEmails)
+	}
+
+	if filter.MentorName != "" {
+		subQuery = subQuery.Where("registrations.mentor_name ILIKE ?", likePattern(filter.MentorName))
+	}
+
+	if filter.ApprovalStatus != nil {
+		subQuery = subQuery.Where("registrations.approval_status = ?", *filter.ApprovalStatus)
+	}
+
+	if filter.AdvisingConfirmation != nil {
+		subQuery = subQuery.Where("registrations.advising_confirmation = ?", *filter.AdvisingConfirmation)
+	}
+
+	if filter.Status != "" {
+		subQuery = subQuery.Where("registrations.status = ?", filter.Status)
+	}
+
+	if filter.AcademicPeriodID != "" {
+		subQuery = subQuery.Where("registrations.academic_period_id = ?", filter.AcademicPeriodID)
+	}
+
+	if filter.AcademicRecordStatus != "" {
+		subQuery = subQuery.Where("registrations.academic_record_status = ?", filter.AcademicRecordStatus)
+	}
+
+	if len(filter.ActivityIDs) > 0 {
+		subQuery = subQuery.Where("registrations.activity_id IN ?", filter.ActivityIDs)
+	}
+
+	if len(filter.ProgramTypeIDs) > 0 {
+		subQuery = subQuery.Where("registrations.program_type_id IN ?", filter.ProgramTypeIDs)
+	}
+
+	if len(filter.UserNRPs) > 0 {
+		subQuery = subQuery.Where("registrations.user_nrp IN ?", filter.UserNRPs)
+	}
+
+	if len(filter.Statuses) > 0 {
+		subQuery = subQuery.Where("registrations.status IN ?", filter.Statuses)
+	}
+
+	if len(filter.LOValidations) > 0 {
+		subQuery = subQuery.Where("registrations.lo_validation IN ?", filter.LOValidations)
+	}
+
+	if len(filter.AcademicAdvisorValidations) > 0 {
+		subQuery = subQuery.Where("registrations.academic_advisor_validation IN ?", filter.AcademicAdvisorValidations)
+	}
+
+	if filter.CreatedFrom != nil {
+		subQuery = subQuery.Where("registrations.created_at >= ?", *filter.CreatedFrom)
+	}
+
+	if filter.CreatedTo != nil {
+		subQuery = subQuery.Where("registrations.created_at <= ?", *filter.CreatedTo)
+	}
+
+	if filter.SemesterMin != nil {
+		subQuery = subQuery.Where("registrations.semester >= ?", *filter.SemesterMin)
+	}
+
+	if filter.SemesterMax != nil {
+		subQuery = subQuery.Where("registrations.semester <= ?", *filter.SemesterMax)
+	}
+
+	if filter.TotalSKSMin != nil {
+		subQuery = subQuery.Where("registrations.total_sks >= ?", *filter.TotalSKSMin)
+	}
+
+	if filter.TotalSKSMax != nil {
+		subQuery = subQuery.Where("registrations.total_sks <= ?", *filter.TotalSKSMax)
+	}
+
+	if strings.TrimSpace(filter.Search) != "" {
+		subQuery = subQuery.Where(REGISTRATION_SEARCH_VECTOR+" @@ websearch_to_tsquery('simple', ?)", filter.Search)
+	}
+
+	// drafts are only visible to the student who owns them
+	if !filter.IncludeDraft {
+		subQuery = subQuery.Where("registrations.status <> ?", entity.REGISTRATION_STATUS_DRAFT)
 	}
 
 	return subQuery
 }
+
+// orderSubQuery sorts a listing by the field the client picked, newest first
+// by default. The id breaks ties so pages stay stable.
+func orderSubQuery(subQuery *gorm.DB, filter dto.FilterRegistrationRequest) *gorm.DB {
+	direction := "DESC"
+	if strings.EqualFold(filter.SortDirection, "asc") {
+		direction = "ASC"
+	}
+
+	if filter.SortBy == "relevance" && strings.TrimSpace(filter.Search) != "" {
+		return subQuery.Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:  "ts_rank(" + REGISTRATION_SEARCH_VECTOR + ", websearch_to_tsquery('simple', ?)) DESC, registrations.created_at DESC, registrations.id DESC",
+			Vars: []interface{}{filter.Search},
+		}})
+	}
+
+	column, ok := registrationSortColumns[filter.SortBy]
+	if !ok {
+		column = "registrations.created_at"
+	}
+
+	return subQuery.
+		Order(column + " " + direction).
+		Order("registrations.id " + direction)
+}
+
+// likePattern wraps a value for a partial ILIKE match, wildcards typed by
+// the client are matched literally
+func likePattern(value string) string {
+	return "%" + likeEscaper.Replace(value) + "%"
+}
+
+// WithTransaction runs fn in a single database transaction, it is rolled back
+// when fn returns an error
+func (r *registrationRepository) WithTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return r.db.WithContext(ctx).Transaction(fn)
+}
+
+// LockNRP takes a transaction level advisory lock on the student, so checks
+// that read the student's registrations before inserting one run one at a time.
+// It only serializes anything when tx is an open transaction.
+func (r *registrationRepository) LockNRP(ctx context.Context, nrp string, tx *gorm.DB) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).
+		Exec("SELECT pg_advisory_xact_lock(?, hashtext(?))", REGISTRATION_NRP_LOCK_NAMESPACE, nrp).Error
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"registration-service/dto"
+	"registration-service/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RETENTION_DATE is the date the retention period of a registration counts
+// from, the end of its academic period or its creation when it has none
+const RETENTION_DATE = "COALESCE(academic_periods.end_date, registrations.created_at)"
+
+// RetentionCandidate is a registration as the retention report shows it,
+// without any personal field
+type RetentionCandidate struct {
+	RegistrationID   string
+	ActivityID       string
+	ActivityName     string
+	AcademicPeriodID string
+	Status           string
+	DocumentCount    int64
+	RetainedFrom     time.Time
+	AnonymizedAt     *time.Time
+}
+
+type retentionRepository struct {
+	db *gorm.DB
+}
+
+type RetentionRepository interface {
+	IndexDue(ctx context.Context, cutoff time.Time, pagReq dto.PaginationRequest, tx *gorm.DB) ([]RetentionCandidate, int64, error)
+	IndexAnonymized(ctx context.Context, pagReq dto.PaginationRequest, tx *gorm.DB) ([]RetentionCandidate, int64, error)
+	Anonymize(ctx context.Context, registration entity.Registration, histories []entity.RegistrationHistory, tx *gorm.DB) error
+}
+
+func NewRetentionRepository(db *gorm.DB) RetentionRepository {
+	return &retentionRepository{db: db}
+}
+
+func (r *retentionRepository) candidates(ctx context.Context, tx *gorm.DB) *gorm.DB {
+	return tx.WithContext(ctx).
+		Table("registrations").
+		Joins("LEFT JOIN academic_periods ON academic_periods.id::text = registrations.academic_period_id").
+		Where("registrations.deleted_at IS NULL")
+}
+
+// IndexDue reads a page of the registrations whose retention date lies before
+// cutoff and that aren't anonymized yet, the oldest first
+func (r *retentionRepository) IndexDue(ctx context.Context, cutoff time.Time, pagReq dto.PaginationRequest, tx *gorm.DB) ([]RetentionCandidate, int64, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	due := func() *gorm.DB {
+		return r.candidates(ctx, tx).
+			Where("registrations.anonymized_at IS NULL").
+			Where(RETENTION_DATE+" < ?", cutoff)
+	}
+
+	return r.index(due, RETENTION_DATE+" ASC, registrations.id ASC", pagReq)
+}
+
+// IndexAnonymized reads a page of the anonymized registrations, the most
+// recently anonymized first
+func (r *retentionRepository) IndexAnonymized(ctx context.Context, pagReq dto.PaginationRequest, tx *gorm.DB) ([]RetentionCandidate, int64, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	anonymized := func() *gorm.DB {
+		return r.candidates(ctx, tx).
+			Where("registrations.anonymized_at IS NOT NULL")
+	}
+
+	return r.index(anonymized, "registrations.anonymized_at DESC", pagReq)
+}
+
+func (r *retentionRepository) index(query func() *gorm.DB, order string, pagReq dto.PaginationRequest) ([]RetentionCandidate, int64, error) {
+	var total int64
+	err := query().Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var candidates []RetentionCandidate
+	err = query().
+		Select("registrations.id::text AS registration_id, registrations.activity_id, registrations.activity_name, registrations.academic_period_id, registrations.status, " +
+			"(SELECT COUNT(*) FROM documents WHERE documents.registration_id = registrations.id::text AND documents.deleted_at IS NULL) AS document_count, " +
+			RETENTION_DATE + " AS retained_from, registrations.anonymized_at").
+		Order(order).
+		Offset(pagReq.Offset).
+		Limit(pagReq.Limit).
+		Scan(&candidates).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return candidates, total, nil
+}
+
+// Anonymize stores the pseudonymized personal fields of the registration and
+// its history and removes its documents for good. The stored files have to be
+// deleted first.
+func (r *retentionRepository) Anonymize(ctx context.Context, registration entity.Registration, histories []entity.RegistrationHistory, tx *gorm.DB) error {
+	anonymize := func(tx *gorm.DB) error {
+		id := registration.ID.String()
+
+		err := purgeDocuments(tx, id)
+		if err != nil {
+			return err
+		}
+
+		result := tx.Model(&entity.Registration{}).
+			Where("id = ?", id).
+			Where("anonymized_at IS NULL").
+			Updates(map[string]interface{}{
+				"user_id":                registration.UserID,
+				"user_name":              registration.UserName,
+				"user_nrp":               registration.UserNRP,
+				"academic_advisor_id":    registration.AcademicAdvisorID,
+				"academic_advisor":       registration.AcademicAdvisor,
+				"academic_advisor_email": registration.AcademicAdvisorEmail,
+				"mentor_name":            registration.MentorName,
+				"mentor_email":           registration.MentorEmail,
+				"withdrawal_reason":      registration.WithdrawalReason,
+				"anonymized_at":          registration.AnonymizedAt,
+				"lock_version":           gorm.Expr("lock_version + 1"),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errors.New("data not found")
+		}
+
+		for _, history := range histories {
+			err = tx.Model(&entity.RegistrationHistory{}).
+				Where("id = ?", history.ID).
+				Updates(map[string]interface{}{
+					"actor_id":    history.ActorID,
+					"actor_name":  history.ActorName,
+					"actor_email": history.ActorEmail,
+					"note":        history.Note,
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if tx != nil {
+		return anonymize(tx.WithContext(ctx))
+	}
+
+	return r.db.WithContext(ctx).Transaction(anonymize)
+}
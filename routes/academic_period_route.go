@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"registration-service/controller"
+	"registration-service/middleware"
+	"registration-service/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func AcademicPeriodRoutes(router *gin.Engine, academicPeriodController controller.AcademicPeriodController, userService service.UserManagementService) {
+	academicPeriodRoute := router.Group("/registration-management/api/v1/academic-period")
+	{
+		academicPeriodRoute.GET("/", academicPeriodController.GetAllAcademicPeriods)
+		academicPeriodRoute.GET("/:id", academicPeriodController.GetAcademicPeriodByID)
+		academicPeriodRoute.POST("/", middleware.AuthorizationRole(userService, []string{"ADMIN"}), academicPeriodController.CreateAcademicPeriod)
+		academicPeriodRoute.PUT("/:id", middleware.AuthorizationRole(userService, []string{"ADMIN"}), academicPeriodController.UpdateAcademicPeriod)
+		academicPeriodRoute.DELETE("/:id", middleware.AuthorizationRole(userService, []string{"ADMIN"}), academicPeriodController.DeleteAcademicPeriod)
+	}
+}
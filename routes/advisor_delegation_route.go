@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"registration-service/controller"
+	"registration-service/middleware"
+	"registration-service/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func AdvisorDelegationRoutes(router *gin.Engine, advisorDelegationController controller.AdvisorDelegationController, userService service.UserManagementService) {
+	advisorDelegationRoute := router.Group("/registration-management/api/v1/advisor-delegation")
+	{
+		advisorDelegationRoute.GET("/", middleware.AuthorizationRole(userService, []string{"ADMIN", "LO-MBKM"}), advisorDelegationController.GetAllAdvisorDelegations)
+		advisorDelegationRoute.GET("/:id", middleware.AuthorizationRole(userService, []string{"ADMIN", "LO-MBKM"}), advisorDelegationController.GetAdvisorDelegationByID)
+		advisorDelegationRoute.POST("/", middleware.AuthorizationRole(userService, []string{"ADMIN", "LO-MBKM"}), advisorDelegationController.CreateAdvisorDelegation)
+		advisorDelegationRoute.DELETE("/:id", middleware.AuthorizationRole(userService, []string{"ADMIN", "LO-MBKM"}), advisorDelegationController.RevokeAdvisorDelegation)
+	}
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"registration-service/controller"
+	"registration-service/middleware"
+	"registration-service/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func DocumentRequirementRoutes(router *gin.Engine, documentRequirementController controller.DocumentRequirementController, userService service.UserManagementService) {
+	documentRequirementRoute := router.Group("/registration-management/api/v1/document-requirement")
+	{
+		documentRequirementRoute.GET("/", documentRequirementController.GetAllDocumentRequirements)
+		documentRequirementRoute.GET("/:id", documentRequirementController.GetDocumentRequirementByID)
+		documentRequirementRoute.POST("/", middleware.AuthorizationRole(userService, []string{"ADMIN", "LO-MBKM"}), documentRequirementController.CreateDocumentRequirement)
+		documentRequirementRoute.PUT("/:id", middleware.AuthorizationRole(userService, []string{"ADMIN", "LO-MBKM"}), documentRequirementController.UpdateDocumentRequirement)
+		documentRequirementRoute.DELETE("/:id", middleware.AuthorizationRole(userService, []string{"ADMIN", "LO-MBKM"}), documentRequirementController.DeleteDocumentRequirement)
+	}
+}
@@ -2,11 +2,13 @@ package routes
 
 import (
 	"registration-service/controller"
+	"registration-service/middleware"
+	"registration-service/service"
 
 	"github.com/gin-gonic/gin"
 )
 
-func DocumentRoutes(router *gin.Engine, documentController controller.DocumentController) {
+func DocumentRoutes(router *gin.Engine, documentController controller.DocumentController, userService service.UserManagementService) {
 	documentServiceRoute := router.Group("/registration-management/api/v1")
 	{
 		documentRoutes := documentServiceRoute.Group("/document")
@@ -16,6 +18,20 @@ func DocumentRoutes(router *gin.Engine, documentController controller.DocumentCo
 			documentRoutes.POST("/", documentController.CreateDocument)
 			documentRoutes.PUT("/:id", documentController.UpdateDocument)
 			documentRoutes.DELETE("/:id", documentController.DeleteDocument)
+			documentRoutes.GET("/:id/versions", documentController.GetDocumentVersions)
+			documentRoutes.GET("/:id/versions/:version", documentController.GetDocumentVersion)
+			documentRoutes.GET("/:id/download", documentController.DownloadDocument)
+			documentRoutes.GET("/:id/download-link", documentController.CreateDownloadLink)
+			// ADMIN reviews too: admins approve registrations like LO-MBKM and the
+			// approval still needs accepted documents
+			documentRoutes.POST("/:id/review", middleware.AuthorizationRole(userService, []string{"DOSEN PEMBIMBING", "LO-MBKM", "ADMIN"}), documentController.ReviewDocument)
 		}
 	}
 }
+
+// SignedDocumentRoutes registers the routes opened from signed links. They are
+// registered before the access key middleware since the link is opened from a
+// browser or a mail client, the signature is checked by the handler instead.
+func SignedDocumentRoutes(router *gin.Engine, documentController controller.DocumentController) {
+	router.GET("/registration-management/api/v1/document/:id/download/signed", documentController.DownloadSignedDocument)
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"registration-service/controller"
+	"registration-service/middleware"
+	"registration-service/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func EligibilityRuleRoutes(router *gin.Engine, eligibilityRuleController controller.EligibilityRuleController, userService service.UserManagementService) {
+	eligibilityRuleRoute := router.Group("/registration-management/api/v1/eligibility-rule")
+	{
+		eligibilityRuleRoute.GET("/", eligibilityRuleController.GetAllEligibilityRules)
+		eligibilityRuleRoute.GET("/:id", eligibilityRuleController.GetEligibilityRuleByID)
+		eligibilityRuleRoute.POST("/", middleware.AuthorizationRole(userService, []string{"ADMIN", "LO-MBKM"}), eligibilityRuleController.CreateEligibilityRule)
+		eligibilityRuleRoute.PUT("/:id", middleware.AuthorizationRole(userService, []string{"ADMIN", "LO-MBKM"}), eligibilityRuleController.UpdateEligibilityRule)
+		eligibilityRuleRoute.DELETE("/:id", middleware.AuthorizationRole(userService, []string{"ADMIN", "LO-MBKM"}), eligibilityRuleController.DeleteEligibilityRule)
+	}
+}
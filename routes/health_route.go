@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"registration-service/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthRoutes are registered before the access key middleware so probes don't need a key
+func HealthRoutes(router *gin.Engine, healthController controller.HealthController) {
+	healthRoute := router.Group("/registration-management/api/v1/health")
+	{
+		healthRoute.GET("/live", healthController.Live)
+		healthRoute.GET("/ready", healthController.Ready)
+	}
+}
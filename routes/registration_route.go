@@ -15,6 +15,7 @@ func RegistrationRoutes(router *gin.Engine, programTypeController controller.Reg
 		registrationServiceRoute.GET("/:id", programTypeController.GetRegistrationByID)
 		registrationServiceRoute.POST("", programTypeController.CreateRegistration)
 		registrationServiceRoute.PUT("/:id", programTypeController.UpdateRegistration)
+		registrationServiceRoute.PATCH("/:id", programTypeController.PatchRegistration)
 		registrationServiceRoute.DELETE("/:id", programTypeController.DeleteRegistration)
 		registrationServiceRoute.POST("/advisor", middleware.AuthorizationRole(userService, []string{"DOSEN PEMBIMBING"}), programTypeController.GetRegistrationsByAdvisor)
 		registrationServiceRoute.POST("/lo-mbkm", middleware.AuthorizationRole(userService, []string{"LO-MBKM"}), programTypeController.GetRegistrationsByLOMBKM)
@@ -26,5 +27,18 @@ func RegistrationRoutes(router *gin.Engine, programTypeController controller.Reg
 		registrationServiceRoute.POST("/student/matching", middleware.AuthorizationRole(userService, []string{"MAHASISWA"}), programTypeController.GetStudentRegistrationsWithMatching)
 		registrationServiceRoute.GET("/check-eligibility", middleware.AuthorizationRole(userService, []string{"MAHASISWA"}), programTypeController.CheckRegistrationEligibility)
 		registrationServiceRoute.GET("/total", middleware.AuthorizationRole(userService, []string{"DOSEN PEMBIMBING"}), programTypeController.GetTotalRegistrationByAdvisorEmail)
+		registrationServiceRoute.POST("/import", middleware.AuthorizationRole(userService, []string{"ADMIN", "LO-MBKM"}), programTypeController.ImportRegistrations)
+		registrationServiceRoute.POST("/advisor/reassign", middleware.AuthorizationRole(userService, []string{"ADMIN", "LO-MBKM"}), programTypeController.ReassignAdvisor)
+		registrationServiceRoute.POST("/draft", middleware.AuthorizationRole(userService, []string{"MAHASISWA"}), programTypeController.CreateDraftRegistration)
+		registrationServiceRoute.POST("/:id/submit", middleware.AuthorizationRole(userService, []string{"MAHASISWA"}), programTypeController.SubmitRegistration)
+		registrationServiceRoute.POST("/:id/withdraw", middleware.AuthorizationRole(userService, []string{"MAHASISWA"}), programTypeController.WithdrawRegistration)
+		registrationServiceRoute.POST("/withdrawal/approval", middleware.AuthorizationRole(userService, []string{"ADMIN", "LO-MBKM", "DOSEN PEMBIMBING"}), programTypeController.ApproveWithdrawal)
+		registrationServiceRoute.GET("/:id/history", programTypeController.GetRegistrationHistory)
+		registrationServiceRoute.GET("/:id/documents/archive", programTypeController.GetRegistrationDocumentsArchive)
+	}
+
+	activityServiceRoute := router.Group("/registration-management/api/v1/activity")
+	{
+		activityServiceRoute.GET("/:activityID/documents/archive", middleware.AuthorizationRole(userService, []string{"ADMIN", "LO-MBKM"}), programTypeController.GetActivityDocumentsArchive)
 	}
 }
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"registration-service/controller"
+	"registration-service/middleware"
+	"registration-service/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegistrationTrashRoutes(router *gin.Engine, registrationTrashController controller.RegistrationTrashController, userService service.UserManagementService) {
+	registrationTrashRoute := router.Group("/registration-management/api/v1/registration-trash")
+	{
+		registrationTrashRoute.GET("/", middleware.AuthorizationRole(userService, []string{"ADMIN"}), registrationTrashController.GetDeletedRegistrations)
+		registrationTrashRoute.GET("/:id", middleware.AuthorizationRole(userService, []string{"ADMIN"}), registrationTrashController.GetDeletedRegistrationByID)
+		registrationTrashRoute.POST("/:id/restore", middleware.AuthorizationRole(userService, []string{"ADMIN"}), registrationTrashController.RestoreRegistration)
+	}
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"registration-service/controller"
+	"registration-service/middleware"
+	"registration-service/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RetentionRoutes(router *gin.Engine, retentionController controller.RetentionController, userService service.UserManagementService) {
+	retentionRoute := router.Group("/registration-management/api/v1/retention")
+	{
+		retentionRoute.GET("/report", middleware.AuthorizationRole(userService, []string{"ADMIN"}), retentionController.GetRetentionReport)
+		retentionRoute.POST("/run", middleware.AuthorizationRole(userService, []string{"ADMIN"}), retentionController.RunRetention)
+	}
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"registration-service/dto"
+	"registration-service/entity"
+	"registration-service/repository"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type academicPeriodService struct {
+	academicPeriodRepository repository.AcademicPeriodRepository
+}
+
+type AcademicPeriodService interface {
+	FindAllAcademicPeriods(ctx context.Context, filter dto.FilterAcademicPeriodRequest, tx *gorm.DB) ([]dto.AcademicPeriodResponse, error)
+	FindAcademicPeriodByID(ctx context.Context, id string, tx *gorm.DB) (dto.AcademicPeriodResponse, error)
+	CreateAcademicPeriod(ctx context.Context, request dto.AcademicPeriodRequest, tx *gorm.DB) error
+	UpdateAcademicPeriod(ctx context.Context, id string, request dto.AcademicPeriodRequest, tx *gorm.DB) error
+	DeleteAcademicPeriod(ctx context.Context, id string, tx *gorm.DB) error
+	FindPeriodForDate(ctx context.Context, date time.Time, tx *gorm.DB) (*entity.AcademicPeriod, error)
+}
+
+func NewAcademicPeriodService(academicPeriodRepository repository.AcademicPeriodRepository) AcademicPeriodService {
+	return &academicPeriodService{
+		academicPeriodRepository: academicPeriodRepository,
+	}
+}
+
+func (s *academicPeriodService) FindAllAcademicPeriods(ctx context.Context, filter dto.FilterAcademicPeriodRequest, tx *gorm.DB) ([]dto.AcademicPeriodResponse, error) {
+	periods, err := s.academicPeriodRepository.Index(ctx, filter, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var response []dto.AcademicPeriodResponse
+	for _, period := range periods {
+		response = append(response, convertToAcademicPeriodResponse(period))
+	}
+
+	return response, nil
+}
+
+func (s *academicPeriodService) FindAcademicPeriodByID(ctx context.Context, id string, tx *gorm.DB) (dto.AcademicPeriodResponse, error) {
+	period, err := s.academicPeriodRepository.FindByID(ctx, id, tx)
+	if err != nil {
+		return dto.AcademicPeriodResponse{}, err
+	}
+
+	return convertToAcademicPeriodResponse(period), nil
+}
+
+func (s *academicPeriodService) CreateAcademicPeriod(ctx context.Context, request dto.AcademicPeriodRequest, tx *gorm.DB) error {
+	period, err := s.academicPeriodFromRequest(ctx, "", request, tx)
+	if err != nil {
+		return err
+	}
+
+	period.ID = uuid.New()
+	_, err = s.academicPeriodRepository.Create(ctx, period, tx)
+
+	return err
+}
+
+func (s *academicPeriodService) UpdateAcademicPeriod(ctx context.Context, id string, request dto.AcademicPeriodRequest, tx *gorm.DB) error {
+	_, err := s.academicPeriodRepository.FindByID(ctx, id, tx)
+	if err != nil {
+		return err
+	}
+
+	period, err := s.academicPeriodFromRequest(ctx, id, request, tx)
+	if err != nil {
+		return err
+	}
+
+	return s.academicPeriodRepository.Update(ctx, id, period, tx)
+}
+
+func (s *academicPeriodService) DeleteAcademicPeriod(ctx context.Context, id string, tx *gorm.DB) error {
+	return s.academicPeriodRepository.Destroy(ctx, id, tx)
+}
+
+// FindPeriodForDate returns the period covering the date, nil when there is none
+func (s *academicPeriodService) FindPeriodForDate(ctx context.Context, date time.Time, tx *gorm.DB) (*entity.AcademicPeriod, error) {
+	period, err := s.academicPeriodRepository.FindByDate(ctx, date, tx)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &period, nil
+}
+
+// academicPeriodFromRequest validates the dates, periods may not overlap so
+// every date belongs to at most one period
+func (s *academicPeriodService) academicPeriodFromRequest(ctx context.Context, id string, request dto.AcademicPeriodRequest, tx *gorm.DB) (entity.AcademicPeriod, error) {
+	if !request.StartDate.Before(request.EndDate) {
+		return entity.AcademicPeriod{}, errors.New("start_date must be before end_date")
+	}
+
+	overlapping, err := s.academicPeriodRepository.FindOverlapping(ctx, request.StartDate, request.EndDate, id, tx)
+	if err != nil {
+		return entity.AcademicPeriod{}, err
+	}
+
+	if len(overlapping) > 0 {
+		names := make([]string, 0, len(overlapping))
+		for _, period := range overlapping {
+			names = append(names, academicPeriodName(period))
+		}
+		return entity.AcademicPeriod{}, repository.NewConflictError(fmt.Sprintf("academic period overlaps %s", strings.Join(names, ", ")))
+	}
+
+	return entity.AcademicPeriod{
+		Year:                       strings.TrimSpace(request.Year),
+		Term:                       request.Term,
+		StartDate:                  request.StartDate,
+		EndDate:                    request.EndDate,
+		MaxRegistrationsPerStudent: request.MaxRegistrationsPerStudent,
+	}, nil
+}
+
+func academicPeriodName(period entity.AcademicPeriod) string {
+	return period.Year + " " + period.Term
+}
+
+func convertToAcademicPeriodResponse(period entity.AcademicPeriod) dto.AcademicPeriodResponse {
+	return dto.AcademicPeriodResponse{
+		ID:                         period.ID.String(),
+		Year:                       period.Year,
+		Term:                       period.Term,
+		StartDate:                  period.StartDate,
+		EndDate:                    period.EndDate,
+		MaxRegistrationsPerStudent: period.MaxRegistrationsPerStudent,
+	}
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"registration-service/config"
+	"registration-service/dto"
+	"registration-service/entity"
+	"strings"
+	"time"
+)
+
+const (
+	ACADEMIC_RECORD_CLIENT_NONE  = "none"
+	ACADEMIC_RECORD_CLIENT_LOCAL = "local"
+
+	ACADEMIC_RECORD_MODE_OFF       = "off"
+	ACADEMIC_RECORD_MODE_CHECK     = "check"
+	ACADEMIC_RECORD_MODE_OVERWRITE = "overwrite"
+)
+
+var ErrAcademicRecordNotFound = errors.New("academic record not found")
+
+// AcademicRecord is the official standing of a student, TotalSKS counts the
+// credits earned so far
+type AcademicRecord struct {
+	NRP      string `json:"nrp"`
+	Semester int    `json:"semester"`
+	TotalSKS int    `json:"total_sks"`
+}
+
+// AcademicRecordClient fetches the academic record of a student, it returns
+// ErrAcademicRecordNotFound when the student has no record
+type AcademicRecordClient interface {
+	GetAcademicRecord(ctx context.Context, nrp string, token string) (AcademicRecord, error)
+}
+
+func NewAcademicRecordClient(academicRecordConfig config.AcademicRecordConfig) AcademicRecordClient {
+	switch strings.ToLower(academicRecordConfig.Client) {
+	case ACADEMIC_RECORD_CLIENT_NONE, "":
+		return noAcademicRecordClient{}
+	case ACADEMIC_RECORD_CLIENT_LOCAL:
+		return NewLocalAcademicRecordClient(academicRecordConfig.StubFile)
+	}
+
+	log.Fatalf("unknown academic record client %q", academicRecordConfig.Client)
+	return nil
+}
+
+// academicRecordMode is off without a client, there is nothing to check against
+func academicRecordMode(academicRecordConfig config.AcademicRecordConfig) string {
+	client := strings.ToLower(academicRecordConfig.Client)
+	if client == ACADEMIC_RECORD_CLIENT_NONE || client == "" {
+		return ACADEMIC_RECORD_MODE_OFF
+	}
+
+	mode := strings.ToLower(academicRecordConfig.Mode)
+	switch mode {
+	case ACADEMIC_RECORD_MODE_OFF, ACADEMIC_RECORD_MODE_CHECK, ACADEMIC_RECORD_MODE_OVERWRITE:
+		return mode
+	}
+
+	log.Fatalf("unknown academic record mode %q", academicRecordConfig.Mode)
+	return ""
+}
+
+// noAcademicRecordClient is used until an academic system is configured, the
+// check is skipped so registrations aren't flagged as unavailable
+type noAcademicRecordClient struct{}
+
+func (noAcademicRecordClient) GetAcademicRecord(ctx context.Context, nrp string, token string) (AcademicRecord, error) {
+	return AcademicRecord{}, ErrAcademicRecordNotFound
+}
+
+// localAcademicRecordClient answers from a JSON file holding a list of
+// records, it is meant for development and tests until the academic system
+// can be reached. Without a file every student is unknown.
+type localAcademicRecordClient struct {
+	records map[string]AcademicRecord
+}
+
+func NewLocalAcademicRecordClient(stubFile string) AcademicRecordClient {
+	client := &localAcademicRecordClient{records: map[string]AcademicRecord{}}
+	if stubFile == "" {
+		log.Println("ACADEMIC_RECORD_STUB_FILE is not set, every academic record is unavailable")
+		return client
+	}
+
+	content, err := os.ReadFile(stubFile)
+	if err != nil {
+		log.Fatalf("failed to read academic record stub file: %v", err)
+	}
+
+	var records []AcademicRecord
+	err = json.Unmarshal(content, &records)
+	if err != nil {
+		log.Fatalf("invalid academic record stub file: %v", err)
+	}
+
+	for _, record := range records {
+		client.records[record.NRP] = record
+	}
+
+	return client
+}
+
+func (c *localAcademicRecordClient) GetAcademicRecord(ctx context.Context, nrp string, token string) (AcademicRecord, error) {
+	record, ok := c.records[nrp]
+	if !ok {
+		return AcademicRecord{}, ErrAcademicRecordNotFound
+	}
+
+	return record, nil
+}
+
+// academicRecordCheck is the outcome of comparing the typed in semester and
+// SKS with the academic record, Semester and TotalSKS are the values the
+// registration keeps
+type academicRecordCheck struct {
+	Status           string
+	Semester         int
+	TotalSKS         int
+	ReportedSemester int
+	ReportedTotalSKS int
+	RecordSemester   int
+	RecordTotalSKS   int
+	CheckedAt        *time.Time
+}
+
+// checkAcademicRecord looks up the student's record. An unreachable or
+// missing record never blocks a registration, it is flagged as unavailable
+// for the reviewers instead.
+func (s *registrationService) checkAcademicRecord(ctx context.Context, nrp string, semester int, totalSKS int, token string) academicRecordCheck {
+	check := academicRecordCheck{
+		Semester:         semester,
+		TotalSKS:         totalSKS,
+		ReportedSemester: semester,
+		ReportedTotalSKS: totalSKS,
+	}
+
+	if s.academicRecordMode == ACADEMIC_RECORD_MODE_OFF {
+		return check
+	}
+
+	now := time.Now()
+	check.CheckedAt = &now
+
+	record, err := s.academicRecordClient.GetAcademicRecord(ctx, nrp, token)
+	if err != nil {
+		if !errors.Is(err, ErrAcademicRecordNotFound) {
+			log.Printf("failed to get academic record of %s: %v", nrp, err)
+		}
+		check.Status = entity.ACADEMIC_RECORD_STATUS_UNAVAILABLE
+		return check
+	}
+
+	check.RecordSemester = record.Semester
+	check.RecordTotalSKS = record.TotalSKS
+
+	switch {
+	case record.Semester == semester && record.TotalSKS == totalSKS:
+		check.Status = entity.ACADEMIC_RECORD_STATUS_VERIFIED
+	case s.academicRecordMode == ACADEMIC_RECORD_MODE_OVERWRITE:
+		check.Status = entity.ACADEMIC_RECORD_STATUS_CORRECTED
+		check.Semester = record.Semester
+		check.TotalSKS = record.TotalSKS
+	default:
+		check.Status = entity.ACADEMIC_RECORD_STATUS_MISMATCH
+	}
+
+	return check
+}
+
+// academicRecordResponse is nil for registrations that were never checked
+func academicRecordResponse(registration entity.Registration) *dto.AcademicRecordResponse {
+	if registration.AcademicRecordStatus == "" {
+		return nil
+	}
+
+	return &dto.AcademicRecordResponse{
+		Status:           registration.AcademicRecordStatus,
+		ReportedSemester: registration.ReportedSemester,
+		ReportedTotalSKS: registration.ReportedTotalSKS,
+		RecordSemester:   registration.RecordSemester,
+		RecordTotalSKS:   registration.RecordTotalSKS,
+		CheckedAt:        registration.AcademicRecordCheckedAt,
+	}
+}
+
+func (c academicRecordCheck) applyTo(registration *entity.Registration) {
+	registration.Semester = c.Semester
+	registration.TotalSKS = c.TotalSKS
+	registration.ReportedSemester = c.ReportedSemester
+	registration.ReportedTotalSKS = c.ReportedTotalSKS
+	registration.RecordSemester = c.RecordSemester
+	registration.RecordTotalSKS = c.RecordTotalSKS
+	registration.AcademicRecordStatus = c.Status
+	registration.AcademicRecordCheckedAt = c.CheckedAt
+}
@@ -55,6 +55,8 @@ func (s *ActivityManagementService) GetActivitiesData(data map[string]interface{
 		activitiesData = append(activitiesData, map[string]interface{}{
 			"id":              activity["id"],
 			"name":            activity["name"],
+			"program_type_id": activity["program_type_id"],
+			"level_id":        activity["level_id"],
 			"start_period":    activity["start_period"],
 			"months_duration": activity["months_duration"],
 			"approval_status": activity["approval_status"],
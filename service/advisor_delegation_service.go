@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"registration-service/dto"
+	"registration-service/entity"
+	"registration-service/repository"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type advisorDelegationService struct {
+	advisorDelegationRepository repository.AdvisorDelegationRepository
+	userManagementService       *UserManagementService
+}
+
+type AdvisorDelegationService interface {
+	FindAllAdvisorDelegations(ctx context.Context, filter dto.FilterAdvisorDelegationRequest, tx *gorm.DB) ([]dto.AdvisorDelegationResponse, error)
+	FindAdvisorDelegationByID(ctx context.Context, id string, tx *gorm.DB) (dto.AdvisorDelegationResponse, error)
+	CreateAdvisorDelegation(ctx context.Context, request dto.AdvisorDelegationRequest, token string, tx *gorm.DB) (dto.AdvisorDelegationResponse, error)
+	RevokeAdvisorDelegation(ctx context.Context, id string, tx *gorm.DB) error
+}
+
+func NewAdvisorDelegationService(advisorDelegationRepository repository.AdvisorDelegationRepository, userManagementbaseURI string, asyncURIs []string) AdvisorDelegationService {
+	return &advisorDelegationService{
+		advisorDelegationRepository: advisorDelegationRepository,
+		userManagementService:       NewUserManagementService(userManagementbaseURI, asyncURIs),
+	}
+}
+
+func (s *advisorDelegationService) FindAllAdvisorDelegations(ctx context.Context, filter dto.FilterAdvisorDelegationRequest, tx *gorm.DB) ([]dto.AdvisorDelegationResponse, error) {
+	delegations, err := s.advisorDelegationRepository.Index(ctx, filter, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var response []dto.AdvisorDelegationResponse
+	for _, delegation := range delegations {
+		response = append(response, convertToAdvisorDelegationResponse(delegation, now))
+	}
+
+	return response, nil
+}
+
+func (s *advisorDelegationService) FindAdvisorDelegationByID(ctx context.Context, id string, tx *gorm.DB) (dto.AdvisorDelegationResponse, error) {
+	delegation, err := s.advisorDelegationRepository.FindByID(ctx, id, tx)
+	if err != nil {
+		return dto.AdvisorDelegationResponse{}, err
+	}
+
+	return convertToAdvisorDelegationResponse(delegation, time.Now()), nil
+}
+
+// CreateAdvisorDelegation lets the delegate approve for the advisor until the
+// delegation ends. Both have to be academic advisors in user management, the
+// stored emails and names are the ones user management knows.
+func (s *advisorDelegationService) CreateAdvisorDelegation(ctx context.Context, request dto.AdvisorDelegationRequest, token string, tx *gorm.DB) (dto.AdvisorDelegationResponse, error) {
+	if !request.StartsAt.Before(request.EndsAt) {
+		return dto.AdvisorDelegationResponse{}, errors.New("starts_at must be before ends_at")
+	}
+
+	if !request.EndsAt.After(time.Now()) {
+		return dto.AdvisorDelegationResponse{}, errors.New("ends_at must be in the future")
+	}
+
+	if strings.EqualFold(strings.TrimSpace(request.AdvisorEmail), strings.TrimSpace(request.DelegateEmail)) {
+		return dto.AdvisorDelegationResponse{}, errors.New("an advisor can't delegate to themselves")
+	}
+
+	advisor, err := s.findAdvisor(request.AdvisorEmail, token)
+	if err != nil {
+		return dto.AdvisorDelegationResponse{}, err
+	}
+
+	delegate, err := s.findAdvisor(request.DelegateEmail, token)
+	if err != nil {
+		return dto.AdvisorDelegationResponse{}, fmt.Errorf("delegate: %w", err)
+	}
+
+	delegation := entity.AdvisorDelegation{
+		ID:            uuid.New(),
+		AdvisorEmail:  advisor.Email,
+		AdvisorName:   advisor.Name,
+		DelegateEmail: delegate.Email,
+		DelegateName:  delegate.Name,
+		StartsAt:      request.StartsAt,
+		EndsAt:        request.EndsAt,
+		Reason:        request.Reason,
+	}
+
+	userData := s.userManagementService.GetUserData("GET", token)
+	if userData != nil {
+		delegation.CreatedByID, _ = userData["id"].(string)
+		delegation.CreatedByName, _ = userData["name"].(string)
+	}
+
+	delegation, err = s.advisorDelegationRepository.Create(ctx, delegation, tx)
+	if err != nil {
+		return dto.AdvisorDelegationResponse{}, err
+	}
+
+	return convertToAdvisorDelegationResponse(delegation, time.Now()), nil
+}
+
+// RevokeAdvisorDelegation ends the delegation right away, approvals already
+// given by the delegate stay valid
+func (s *advisorDelegationService) RevokeAdvisorDelegation(ctx context.Context, id string, tx *gorm.DB) error {
+	return s.advisorDelegationRepository.Destroy(ctx, id, tx)
+}
+
+func (s *advisorDelegationService) findAdvisor(email string, token string) (advisorAssignment, error) {
+	dosen := s.userManagementService.GetDosenDataByEmail(strings.TrimSpace(email), "GET", token)
+	if dosen == nil {
+		return advisorAssignment{}, errors.New("academic advisor not found")
+	}
+
+	return validateAdvisorData(advisorAssignment{Email: email}, dosen)
+}
+
+func convertToAdvisorDelegationResponse(delegation entity.AdvisorDelegation, now time.Time) dto.AdvisorDelegationResponse {
+	return dto.AdvisorDelegationResponse{
+		ID:            delegation.ID.String(),
+		AdvisorEmail:  delegation.AdvisorEmail,
+		AdvisorName:   delegation.AdvisorName,
+		DelegateEmail: delegation.DelegateEmail,
+		DelegateName:  delegation.DelegateName,
+		StartsAt:      delegation.StartsAt,
+		EndsAt:        delegation.EndsAt,
+		Reason:        delegation.Reason,
+		Active:        !delegation.StartsAt.After(now) && delegation.EndsAt.After(now),
+		CreatedByName: delegation.CreatedByName,
+	}
+}
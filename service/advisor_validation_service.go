@@ -0,0 +1,105 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"registration-service/config"
+	"strings"
+)
+
+const (
+	ADVISOR_MODE_OFF       = "off"
+	ADVISOR_MODE_VALIDATE  = "validate"
+	ADVISOR_MODE_AUTO_FILL = "auto_fill"
+
+	ADVISOR_ROLE = "DOSEN PEMBIMBING"
+)
+
+// advisorAssignment is the academic advisor data a registration stores
+type advisorAssignment struct {
+	ID    string
+	Name  string
+	Email string
+}
+
+func advisorMode(advisorConfig config.AdvisorConfig) string {
+	mode := strings.ToLower(advisorConfig.Mode)
+	switch mode {
+	case ADVISOR_MODE_OFF, ADVISOR_MODE_VALIDATE, ADVISOR_MODE_AUTO_FILL:
+		return mode
+	}
+
+	log.Fatalf("unknown advisor mode %q", advisorConfig.Mode)
+	return ""
+}
+
+// resolveAdvisor checks the advisor typed in for a registration against user
+// management and returns the advisor as user management knows it. In auto_fill
+// mode the advisor assigned to the student replaces the typed in one. A draft
+// may leave the advisor empty, required is false for it.
+func (s *registrationService) resolveAdvisor(userNRP string, advisor advisorAssignment, required bool, token string) (advisorAssignment, error) {
+	if s.advisorMode == ADVISOR_MODE_AUTO_FILL {
+		assigned, err := s.userManagementService.GetStudentAdvisor(userNRP, "GET", token)
+		if err != nil {
+			return advisorAssignment{}, err
+		}
+		if assigned != nil {
+			return validateAdvisorData(advisorAssignment{}, assigned)
+		}
+	}
+
+	if advisor.ID == "" && advisor.Name == "" && advisor.Email == "" && !required {
+		return advisor, nil
+	}
+
+	if advisor.Email == "" {
+		return advisorAssignment{}, errors.New("academic advisor email is required")
+	}
+
+	if s.advisorMode == ADVISOR_MODE_OFF {
+		if required && (advisor.ID == "" || advisor.Name == "") {
+			return advisorAssignment{}, errors.New("academic advisor id and name are required")
+		}
+		return advisor, nil
+	}
+
+	dosen := s.userManagementService.GetDosenDataByEmail(advisor.Email, "GET", token)
+	if dosen == nil {
+		return advisorAssignment{}, errors.New("academic advisor not found")
+	}
+
+	return validateAdvisorData(advisor, dosen)
+}
+
+// validateAdvisorData compares the typed in advisor with the user management
+// data of the advisor. Empty fields are taken from user management, filled in
+// ones have to agree with it.
+func validateAdvisorData(advisor advisorAssignment, dosen map[string]interface{}) (advisorAssignment, error) {
+	id := fmt.Sprint(dosen["id"])
+	name, _ := dosen["name"].(string)
+	email, _ := dosen["email"].(string)
+	role, _ := dosen["role"].(string)
+
+	if dosen["id"] == nil || email == "" {
+		return advisorAssignment{}, errors.New("academic advisor not found")
+	}
+
+	if role != ADVISOR_ROLE {
+		return advisorAssignment{}, fmt.Errorf("academic advisor %s does not have the %s role", email, ADVISOR_ROLE)
+	}
+
+	if advisor.Email != "" && !strings.EqualFold(strings.TrimSpace(advisor.Email), email) {
+		return advisorAssignment{}, errors.New("academic advisor email does not match user management")
+	}
+
+	if advisor.ID != "" && strings.TrimSpace(advisor.ID) != id {
+		return advisorAssignment{}, fmt.Errorf("academic advisor id does not match the advisor with email %s", email)
+	}
+
+	if advisor.Name != "" && !strings.EqualFold(strings.Join(strings.Fields(advisor.Name), " "), strings.Join(strings.Fields(name), " ")) {
+		return advisorAssignment{}, fmt.Errorf("academic advisor name does not match the advisor with email %s", email)
+	}
+
+	return advisorAssignment{ID: id, Name: name, Email: email}, nil
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"registration-service/entity"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var archiveNameReplacer = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// DocumentArchive is a ZIP of registration documents. Files are copied from
+// the storage straight into the response one at a time, so memory use does
+// not grow with the archive.
+type DocumentArchive struct {
+	Name          string
+	registrations []entity.Registration
+	storage       DocumentStorage
+}
+
+func (s *registrationService) RegistrationDocumentsArchive(ctx context.Context, id string, token string, tx *gorm.DB) (DocumentArchive, error) {
+	if !s.RegistrationsDataAccess(ctx, id, token, tx) {
+		return DocumentArchive{}, errors.New("data not found")
+	}
+
+	registration, err := s.registrationRepository.FindByID(ctx, id, tx)
+	if err != nil {
+		return DocumentArchive{}, err
+	}
+
+	return DocumentArchive{
+		Name:          archiveName(registration.UserNRP, registration.UserName) + "_documents.zip",
+		registrations: []entity.Registration{registration},
+		storage:       s.fileService.storage,
+	}, nil
+}
+
+func (s *registrationService) ActivityDocumentsArchive(ctx context.Context, activityID string, token string, tx *gorm.DB) (DocumentArchive, error) {
+	registrations, err := s.registrationRepository.FindByActivityID(ctx, activityID, tx)
+	if err != nil {
+		return DocumentArchive{}, err
+	}
+
+	if len(registrations) == 0 {
+		return DocumentArchive{}, errors.New("data not found")
+	}
+
+	return DocumentArchive{
+		Name:          archiveName(registrations[0].ActivityName, activityID) + "_documents.zip",
+		registrations: registrations,
+		storage:       s.fileService.storage,
+	}, nil
+}
+
+// Write streams the archive. Entries are named NRP_name/document_type.ext and
+// a manifest.csv listing every document, including the ones that could not be
+// fetched, is added last.
+func (a DocumentArchive) Write(ctx context.Context, w io.Writer) error {
+	archive := zip.NewWriter(w)
+
+	manifest := [][]string{{"nrp", "name", "activity", "registration_id", "document_id", "document_type", "version", "review_status", "file", "size", "sha256", "error"}}
+	used := map[string]int{}
+
+	for _, registration := range a.registrations {
+		folder := archiveName(registration.UserNRP, registration.UserName)
+
+		for _, document := range registration.Document {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			entryName := folder + "/" + archiveName(document.DocumentType) + archiveExtension(document)
+			used[entryName]++
+			if used[entryName] > 1 {
+				ext := filepath.Ext(entryName)
+				entryName = fmt.Sprintf("%s_%d%s", strings.TrimSuffix(entryName, ext), used[entryName], ext)
+			}
+
+			row := []string{registration.UserNRP, registration.UserName, registration.ActivityName, registration.ID.String(), document.ID.String(), document.DocumentType, strconv.Itoa(currentVersion(document)), document.ReviewStatus}
+
+			size, checksum, err := a.writeEntry(ctx, archive, entryName, document)
+			if err != nil {
+				// a missing object should not cost the whole archive
+				manifest = append(manifest, append(row, "", "", "", err.Error()))
+				continue
+			}
+
+			manifest = append(manifest, append(row, entryName, strconv.FormatInt(size, 10), checksum, ""))
+		}
+	}
+
+	entry, err := archive.CreateHeader(&zip.FileHeader{Name: "manifest.csv", Method: zip.Deflate, Modified: time.Now()})
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(entry)
+	err = writer.WriteAll(manifest)
+	if err != nil {
+		return err
+	}
+
+	return archive.Close()
+}
+
+func (a DocumentArchive) writeEntry(ctx context.Context, archive *zip.Writer, name string, document entity.Document) (int64, string, error) {
+	object, err := a.storage.Download(ctx, document.FileStorageID)
+	if err != nil {
+		return 0, "", err
+	}
+	defer object.Content.Close()
+
+	modified := time.Now()
+	if document.UpdatedAt != nil {
+		modified = *document.UpdatedAt
+	}
+
+	entry, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified})
+	if err != nil {
+		return 0, "", err
+	}
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(entry, hash), object.Content)
+	if err != nil {
+		return size, "", err
+	}
+
+	return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// archiveName joins the parts into a name safe for every unzip tool
+func archiveName(parts ...string) string {
+	var cleaned []string
+	for _, part := range parts {
+		part = strings.Trim(archiveNameReplacer.ReplaceAllString(strings.TrimSpace(part), "_"), "_.")
+		if part != "" {
+			cleaned = append(cleaned, part)
+		}
+	}
+
+	if len(cleaned) == 0 {
+		return "unknown"
+	}
+
+	return strings.Join(cleaned, "_")
+}
+
+func archiveExtension(document entity.Document) string {
+	if ext, ok := documentExtensions[document.ContentType]; ok {
+		return ext
+	}
+	return strings.ToLower(filepath.Ext(document.Name))
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"path/filepath"
+	"registration-service/dto"
+	"registration-service/entity"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DocumentDownload is a document ready to be streamed, the caller closes
+// Content. Size is -1 when the storage doesn't tell it.
+type DocumentDownload struct {
+	Name        string
+	ContentType string
+	ModTime     time.Time
+	Size        int64
+	Checksum    string
+	Content     io.ReadCloser
+}
+
+// Verified reads the whole content and fails instead of handing out the last
+// bytes when they don't match the checksum stored at upload, a client
+// downloading the whole document then sees a truncated transfer.
+func (d DocumentDownload) Verified() io.Reader {
+	if d.Checksum == "" {
+		return d.Content
+	}
+
+	return &checksumReader{reader: bufio.NewReader(d.Content), hash: sha256.New(), checksum: d.Checksum}
+}
+
+type checksumReader struct {
+	reader   *bufio.Reader
+	hash     hash.Hash
+	checksum string
+}
+
+func (r *checksumReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.hash.Write(p[:n])
+
+	// peek ahead so the check runs before the last chunk is returned
+	if err == nil {
+		_, peekErr := r.reader.Peek(1)
+		if peekErr == io.EOF {
+			err = io.EOF
+		}
+	}
+
+	if err == io.EOF && hex.EncodeToString(r.hash.Sum(nil)) != r.checksum {
+		return 0, errors.New("document checksum mismatch")
+	}
+	if err == io.EOF && n > 0 {
+		return n, nil
+	}
+
+	return n, err
+}
+
+var documentExtensions = map[string]string{
+	"application/pdf": ".pdf",
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"application/zip": ".zip",
+}
+
+// DownloadDocument serves a document to a user allowed to see the
+// registration, version 0 is the current version.
+func (s *documentService) DownloadDocument(ctx context.Context, id string, version int, token string, tx *gorm.DB) (DocumentDownload, error) {
+	document, err := s.documentRepository.FindByID(ctx, id, tx)
+	if err != nil {
+		return DocumentDownload{}, err
+	}
+
+	if !s.documentAccess(ctx, document, token, tx) {
+		return DocumentDownload{}, errors.New("data not found")
+	}
+
+	return s.fetchDocument(ctx, document, version, tx)
+}
+
+// DownloadSignedDocument serves a document through a signed link, the
+// signature replaces the access check.
+func (s *documentService) DownloadSignedDocument(ctx context.Context, id string, version int, expires int64, signature string, tx *gorm.DB) (DocumentDownload, error) {
+	err := s.downloadSigner.Verify(id, version, expires, signature)
+	if err != nil {
+		return DocumentDownload{}, err
+	}
+
+	document, err := s.documentRepository.FindByID(ctx, id, tx)
+	if err != nil {
+		return DocumentDownload{}, err
+	}
+
+	return s.fetchDocument(ctx, document, version, tx)
+}
+
+func (s *documentService) CreateDownloadLink(ctx context.Context, id string, version int, token string, tx *gorm.DB) (dto.DocumentDownloadLinkResponse, error) {
+	document, err := s.documentRepository.FindByID(ctx, id, tx)
+	if err != nil {
+		return dto.DocumentDownloadLinkResponse{}, err
+	}
+
+	if !s.documentAccess(ctx, document, token, tx) {
+		return dto.DocumentDownloadLinkResponse{}, errors.New("data not found")
+	}
+
+	if !s.downloadSigner.Enabled() {
+		return dto.DocumentDownloadLinkResponse{}, errSignedLinksDisabled
+	}
+
+	link, expiresAt := s.downloadSigner.SignedURL(id, version)
+
+	return dto.DocumentDownloadLinkResponse{
+		URL:       link,
+		ExpiresAt: expiresAt.Format(time.RFC3339),
+	}, nil
+}
+
+func (s *documentService) fetchDocument(ctx context.Context, document entity.Document, version int, tx *gorm.DB) (DocumentDownload, error) {
+	object := initialVersion(document)
+	if version > 0 && version != currentVersion(document) {
+		documentVersion, err := s.documentVersionRepository.FindByDocumentIDAndVersion(ctx, document.ID.String(), version, tx)
+		if err != nil {
+			return DocumentDownload{}, err
+		}
+		object = documentVersion
+	}
+
+	file, err := s.fileService.storage.Download(ctx, object.FileStorageID)
+	if err != nil {
+		return DocumentDownload{}, err
+	}
+
+	if s.downloadMaxSize > 0 && file.Size > s.downloadMaxSize {
+		file.Content.Close()
+		return DocumentDownload{}, errors.New("document is too large to download")
+	}
+
+	contentType := object.ContentType
+	if contentType == "" {
+		contentType = file.ContentType
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	var modTime time.Time
+	if object.CreatedAt != nil {
+		modTime = *object.CreatedAt
+	}
+
+	return DocumentDownload{
+		Name:        documentFileName(object.Name, document.DocumentType, contentType),
+		ContentType: contentType,
+		ModTime:     modTime,
+		Size:        file.Size,
+		Checksum:    object.Checksum,
+		Content:     file.Content,
+	}, nil
+}
+
+// documentFileName makes sure the downloaded file carries an extension
+// matching its content type.
+func documentFileName(name string, documentType string, contentType string) string {
+	if strings.TrimSpace(name) == "" {
+		name = documentType
+	}
+
+	if filepath.Ext(name) != "" {
+		return name
+	}
+
+	return fmt.Sprintf("%s%s", name, documentExtensions[contentType])
+}
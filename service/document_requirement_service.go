@@ -0,0 +1,309 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"registration-service/dto"
+	"registration-service/entity"
+	"registration-service/repository"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// defaultDocumentRequirements is used when the catalogue has no entry at all
+// for a registration, it matches the documents CreateRegistration collects.
+var defaultDocumentRequirements = []entity.DocumentRequirement{
+	{DocumentType: entity.DOCUMENT_TYPE_ACCEPTANCE_LETTER, Required: true},
+	{DocumentType: entity.DOCUMENT_TYPE_GEOLETTER, Required: true},
+}
+
+// errDocumentTypeNotListed tells that the checklist of the registration has
+// no entry for the document type
+var errDocumentTypeNotListed = errors.New("invalid document type")
+
+type documentRequirementService struct {
+	documentRequirementRepository repository.DocumentRequirementRepository
+}
+
+type DocumentRequirementService interface {
+	FindAllDocumentRequirements(ctx context.Context, filter dto.FilterDocumentRequirementRequest, tx *gorm.DB) ([]dto.DocumentRequirementResponse, error)
+	FindDocumentRequirementByID(ctx context.Context, id string, tx *gorm.DB) (dto.DocumentRequirementResponse, error)
+	CreateDocumentRequirement(ctx context.Context, request dto.DocumentRequirementRequest, tx *gorm.DB) error
+	UpdateDocumentRequirement(ctx context.Context, id string, request dto.DocumentRequirementRequest, tx *gorm.DB) error
+	DeleteDocumentRequirement(ctx context.Context, id string, tx *gorm.DB) error
+	FindRequirementsForRegistration(ctx context.Context, registration entity.Registration, tx *gorm.DB) ([]entity.DocumentRequirement, error)
+	ValidateDocumentType(ctx context.Context, registration entity.Registration, documentType string, tx *gorm.DB) (entity.DocumentRequirement, error)
+	CheckCompleteness(ctx context.Context, registration entity.Registration, tx *gorm.DB) (dto.DocumentCompletenessResponse, error)
+	CheckCompletenessForRegistrations(ctx context.Context, registrations []entity.Registration, tx *gorm.DB) (map[string]dto.DocumentCompletenessResponse, error)
+}
+
+func NewDocumentRequirementService(documentRequirementRepository repository.DocumentRequirementRepository) DocumentRequirementService {
+	return &documentRequirementService{
+		documentRequirementRepository: documentRequirementRepository,
+	}
+}
+
+func (s *documentRequirementService) FindAllDocumentRequirements(ctx context.Context, filter dto.FilterDocumentRequirementRequest, tx *gorm.DB) ([]dto.DocumentRequirementResponse, error) {
+	requirements, err := s.documentRequirementRepository.Index(ctx, filter, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var response []dto.DocumentRequirementResponse
+	for _, requirement := range requirements {
+		response = append(response, convertToDocumentRequirementResponse(requirement))
+	}
+
+	return response, nil
+}
+
+func (s *documentRequirementService) FindDocumentRequirementByID(ctx context.Context, id string, tx *gorm.DB) (dto.DocumentRequirementResponse, error) {
+	requirement, err := s.documentRequirementRepository.FindByID(ctx, id, tx)
+	if err != nil {
+		return dto.DocumentRequirementResponse{}, err
+	}
+
+	return convertToDocumentRequirementResponse(requirement), nil
+}
+
+func (s *documentRequirementService) CreateDocumentRequirement(ctx context.Context, request dto.DocumentRequirementRequest, tx *gorm.DB) error {
+	_, err := s.documentRequirementRepository.Create(ctx, entity.DocumentRequirement{
+		ID:               uuid.New(),
+		ProgramTypeID:    request.ProgramTypeID,
+		ActivityID:       request.ActivityID,
+		DocumentType:     strings.TrimSpace(request.DocumentType),
+		Description:      request.Description,
+		Required:         request.Required,
+		AllowedMimeTypes: joinMimeTypes(request.AllowedMimeTypes),
+		MaxSizeBytes:     request.MaxSizeBytes,
+	}, tx)
+
+	return err
+}
+
+func (s *documentRequirementService) UpdateDocumentRequirement(ctx context.Context, id string, request dto.DocumentRequirementRequest, tx *gorm.DB) error {
+	_, err := s.documentRequirementRepository.FindByID(ctx, id, tx)
+	if err != nil {
+		return err
+	}
+
+	return s.documentRequirementRepository.Update(ctx, id, entity.DocumentRequirement{
+		ProgramTypeID:    request.ProgramTypeID,
+		ActivityID:       request.ActivityID,
+		DocumentType:     strings.TrimSpace(request.DocumentType),
+		Description:      request.Description,
+		Required:         request.Required,
+		AllowedMimeTypes: joinMimeTypes(request.AllowedMimeTypes),
+		MaxSizeBytes:     request.MaxSizeBytes,
+	}, tx)
+}
+
+func (s *documentRequirementService) DeleteDocumentRequirement(ctx context.Context, id string, tx *gorm.DB) error {
+	return s.documentRequirementRepository.Destroy(ctx, id, tx)
+}
+
+// FindRequirementsForRegistration resolves the checklist of a registration.
+// The most specific scope wins: activity entries, then program type entries,
+// then the default catalogue and finally the built-in defaults.
+func (s *documentRequirementService) FindRequirementsForRegistration(ctx context.Context, registration entity.Registration, tx *gorm.DB) ([]entity.DocumentRequirement, error) {
+	requirements, err := s.documentRequirementRepository.FindByScope(ctx, registration.ActivityID, registration.ProgramTypeID, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return resolveRequirements(requirements, registration), nil
+}
+
+// resolveRequirements picks the entries of the registration out of the
+// catalogue entries loaded for it, see FindRequirementsForRegistration
+func resolveRequirements(requirements []entity.DocumentRequirement, registration entity.Registration) []entity.DocumentRequirement {
+	var byActivity, byProgramType, byDefault []entity.DocumentRequirement
+	for _, requirement := range requirements {
+		switch {
+		case requirement.ActivityID != "":
+			if requirement.ActivityID == registration.ActivityID {
+				byActivity = append(byActivity, requirement)
+			}
+		case requirement.ProgramTypeID != "":
+			if requirement.ProgramTypeID == registration.ProgramTypeID {
+				byProgramType = append(byProgramType, requirement)
+			}
+		default:
+			byDefault = append(byDefault, requirement)
+		}
+	}
+
+	switch {
+	case len(byActivity) > 0:
+		return byActivity
+	case len(byProgramType) > 0:
+		return byProgramType
+	case len(byDefault) > 0:
+		return byDefault
+	}
+
+	return defaultDocumentRequirements
+}
+
+// ValidateDocumentType returns the checklist entry matching the document type,
+// the entry also carries the upload rules for that type.
+func (s *documentRequirementService) ValidateDocumentType(ctx context.Context, registration entity.Registration, documentType string, tx *gorm.DB) (entity.DocumentRequirement, error) {
+	requirements, err := s.FindRequirementsForRegistration(ctx, registration, tx)
+	if err != nil {
+		return entity.DocumentRequirement{}, err
+	}
+
+	var allowed []string
+	for _, requirement := range requirements {
+		if strings.EqualFold(requirement.DocumentType, documentType) {
+			return requirement, nil
+		}
+		allowed = append(allowed, requirement.DocumentType)
+	}
+
+	return entity.DocumentRequirement{}, fmt.Errorf("%w, allowed types: %s", errDocumentTypeNotListed, strings.Join(allowed, ", "))
+}
+
+func (s *documentRequirementService) CheckCompleteness(ctx context.Context, registration entity.Registration, tx *gorm.DB) (dto.DocumentCompletenessResponse, error) {
+	requirements, err := s.FindRequirementsForRegistration(ctx, registration, tx)
+	if err != nil {
+		return dto.DocumentCompletenessResponse{}, err
+	}
+
+	return checkCompleteness(requirements, registration), nil
+}
+
+// CheckCompletenessForRegistrations is CheckCompleteness for a listing page,
+// the catalogue entries of the whole page are loaded once. The result is keyed
+// by registration ID.
+func (s *documentRequirementService) CheckCompletenessForRegistrations(ctx context.Context, registrations []entity.Registration, tx *gorm.DB) (map[string]dto.DocumentCompletenessResponse, error) {
+	response := map[string]dto.DocumentCompletenessResponse{}
+	if len(registrations) == 0 {
+		return response, nil
+	}
+
+	var activityIDs, programTypeIDs []string
+	seen := map[string]bool{}
+	for _, registration := range registrations {
+		if registration.ActivityID != "" && !seen["activity:"+registration.ActivityID] {
+			seen["activity:"+registration.ActivityID] = true
+			activityIDs = append(activityIDs, registration.ActivityID)
+		}
+		if registration.ProgramTypeID != "" && !seen["program_type:"+registration.ProgramTypeID] {
+			seen["program_type:"+registration.ProgramTypeID] = true
+			programTypeIDs = append(programTypeIDs, registration.ProgramTypeID)
+		}
+	}
+
+	requirements, err := s.documentRequirementRepository.FindByScopes(ctx, activityIDs, programTypeIDs, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, registration := range registrations {
+		response[registration.ID.String()] = checkCompleteness(resolveRequirements(requirements, registration), registration)
+	}
+
+	return response, nil
+}
+
+// checkCompleteness compares the uploaded documents of the registration with its checklist
+func checkCompleteness(requirements []entity.DocumentRequirement, registration entity.Registration) dto.DocumentCompletenessResponse {
+	// review status per document type, an accepted document wins over the others of the same type
+	uploaded := map[string]string{}
+	for _, document := range registration.Document {
+		documentType := strings.ToLower(document.DocumentType)
+		reviewStatus := document.ReviewStatus
+		if reviewStatus == "" {
+			reviewStatus = entity.DOCUMENT_REVIEW_PENDING
+		}
+		if uploaded[documentType] != entity.DOCUMENT_REVIEW_ACCEPTED {
+			uploaded[documentType] = reviewStatus
+		}
+	}
+
+	response := dto.DocumentCompletenessResponse{
+		Required:      []string{},
+		Missing:       []string{},
+		PendingReview: []string{},
+		NeedsRevision: []string{},
+	}
+	for _, requirement := range requirements {
+		if !requirement.Required {
+			continue
+		}
+
+		response.Required = append(response.Required, requirement.DocumentType)
+		reviewStatus, ok := uploaded[strings.ToLower(requirement.DocumentType)]
+		switch {
+		case !ok:
+			response.Missing = append(response.Missing, requirement.DocumentType)
+		case reviewStatus == entity.DOCUMENT_REVIEW_NEEDS_REVISION:
+			response.NeedsRevision = append(response.NeedsRevision, requirement.DocumentType)
+		case reviewStatus != entity.DOCUMENT_REVIEW_ACCEPTED:
+			response.PendingReview = append(response.PendingReview, requirement.DocumentType)
+		}
+	}
+	response.Complete = len(response.Missing) == 0
+	response.Accepted = response.Complete && len(response.PendingReview) == 0 && len(response.NeedsRevision) == 0
+
+	return response
+}
+
+func convertToDocumentRequirementResponse(requirement entity.DocumentRequirement) dto.DocumentRequirementResponse {
+	return dto.DocumentRequirementResponse{
+		ID:               requirement.ID.String(),
+		ProgramTypeID:    requirement.ProgramTypeID,
+		ActivityID:       requirement.ActivityID,
+		DocumentType:     requirement.DocumentType,
+		Description:      requirement.Description,
+		Required:         requirement.Required,
+		AllowedMimeTypes: splitMimeTypes(requirement.AllowedMimeTypes),
+		MaxSizeBytes:     requirement.MaxSizeBytes,
+	}
+}
+
+func joinMimeTypes(mimeTypes []string) string {
+	var cleaned []string
+	for _, mimeType := range mimeTypes {
+		mimeType = strings.ToLower(strings.TrimSpace(mimeType))
+		if mimeType != "" {
+			cleaned = append(cleaned, mimeType)
+		}
+	}
+	return strings.Join(cleaned, ",")
+}
+
+func splitMimeTypes(mimeTypes string) []string {
+	if mimeTypes == "" {
+		return []string{}
+	}
+	return strings.Split(mimeTypes, ",")
+}
+
+// errMissingDocuments builds the error returned when an action needs every required document.
+func errMissingDocuments(completeness dto.DocumentCompletenessResponse) error {
+	if completeness.Complete {
+		return nil
+	}
+	return errors.New("required documents are missing: " + strings.Join(completeness.Missing, ", "))
+}
+
+// errUnacceptedDocuments builds the error returned while required documents still wait for a review.
+func errUnacceptedDocuments(completeness dto.DocumentCompletenessResponse) error {
+	if completeness.Accepted {
+		return nil
+	}
+
+	var reasons []string
+	if len(completeness.NeedsRevision) > 0 {
+		reasons = append(reasons, "needs revision: "+strings.Join(completeness.NeedsRevision, ", "))
+	}
+	if len(completeness.PendingReview) > 0 {
+		reasons = append(reasons, "pending review: "+strings.Join(completeness.PendingReview, ", "))
+	}
+	return errors.New("required documents are not accepted yet (" + strings.Join(reasons, "; ") + ")")
+}
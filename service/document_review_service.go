@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"registration-service/dto"
+	"registration-service/entity"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ReviewDocument stores the decision of the academic advisor, LO-MBKM or an
+// admin on a single document. Asking for a revision notifies the student, a
+// new upload sets the document back to pending.
+func (s *documentService) ReviewDocument(ctx context.Context, id string, review dto.DocumentReviewRequest, token string, tx *gorm.DB) error {
+	if review.Status == entity.DOCUMENT_REVIEW_NEEDS_REVISION && strings.TrimSpace(review.Comment) == "" {
+		return errors.New("comment is required when requesting a revision")
+	}
+
+	document, err := s.documentRepository.FindByID(ctx, id, tx)
+	if err != nil {
+		return err
+	}
+
+	err = checkLockVersion(review.LockVersion, "document", id, document.LockVersion)
+	if err != nil {
+		return err
+	}
+
+	registration, err := s.registrationRepository.FindByID(ctx, document.RegistrationID, tx)
+	if err != nil {
+		return err
+	}
+
+	if registration.Status == entity.REGISTRATION_STATUS_WITHDRAWN {
+		return errors.New("registration has been withdrawn")
+	}
+
+	userData := s.userManagementService.GetUserData("GET", token)
+	if userData == nil {
+		return errors.New("Unauthorized")
+	}
+
+	userID, _ := userData["id"].(string)
+	userName, _ := userData["name"].(string)
+	userEmail, _ := userData["email"].(string)
+	userRole, _ := userData["role"].(string)
+
+	switch userRole {
+	case "DOSEN PEMBIMBING":
+		if userEmail == "" {
+			return errors.New("Unauthorized")
+		}
+		delegations, err := delegatedAdvisors(ctx, s.advisorDelegationRepository, userEmail, tx)
+		if err != nil {
+			return err
+		}
+		if _, _, allowed := actingFor(registration, userEmail, delegations); !allowed {
+			return errors.New("Unauthorized")
+		}
+	case "LO-MBKM", "ADMIN":
+	default:
+		return errors.New("Unauthorized")
+	}
+
+	now := time.Now()
+	document.ReviewStatus = review.Status
+	document.ReviewerID = userID
+	document.ReviewerName = userName
+	document.ReviewerEmail = userEmail
+	document.ReviewComment = review.Comment
+	document.ReviewedAt = &now
+
+	err = s.documentRepository.Update(ctx, id, document, tx)
+	if err != nil {
+		return err
+	}
+
+	if review.Status == entity.DOCUMENT_REVIEW_NEEDS_REVISION {
+		s.sendRevisionNotification(registration, document, userData, token)
+	}
+
+	return nil
+}
+
+func (s *documentService) sendRevisionNotification(registration entity.Registration, document entity.Document, userData map[string]interface{}, token string) {
+	mahasiswaData := s.userManagementService.GetUserByFilter(map[string]interface{}{
+		"user_nrp": registration.UserNRP,
+	}, "POST", token)
+
+	if len(mahasiswaData) == 0 {
+		log.Printf("No user data found for NRP: %s", registration.UserNRP)
+		return
+	}
+
+	message := fmt.Sprintf("Your %s for %s needs a revision: %s", document.DocumentType, registration.ActivityName, document.ReviewComment)
+
+	s.brokerService.SendNotification(map[string]interface{}{
+		"sender_name":    userData["name"],
+		"sender_email":   userData["email"],
+		"receiver_email": mahasiswaData[0]["email"],
+		"type":           "DOCUMENT REVISION",
+		"message":        message,
+	}, "POST", token)
+}
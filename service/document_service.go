@@ -5,10 +5,12 @@ import (
 	"errors"
 	"mime/multipart"
 	"reflect"
+	"registration-service/config"
 	"registration-service/dto"
 	"registration-service/entity"
 	"registration-service/helper"
 	"registration-service/repository"
+	"strings"
 
 	storageService "github.com/SIM-MBKM/filestorage/storage"
 	"github.com/google/uuid"
@@ -18,73 +20,139 @@ import (
 type DocumentUpdate struct {
 	RegistrationID string
 	FileStorageID  string
+	Checksum       string
+	ContentType    string
+	Size           int64
+	CurrentVersion int
+	ReviewStatus   string
 }
 
 type documentService struct {
-	documentRepository     repository.DocumentRepository
-	registrationRepository repository.RegistrationRepository
-	fileService            *FileService
+	documentRepository          repository.DocumentRepository
+	registrationRepository      repository.RegistrationRepository
+	documentVersionRepository   repository.DocumentVersionRepository
+	advisorDelegationRepository repository.AdvisorDelegationRepository
+	documentRequirementService  DocumentRequirementService
+	uploadValidator             UploadValidator
+	userManagementService       *UserManagementService
+	brokerService               *BrokerService
+	fileService                 *FileService
+	downloadSigner              *DownloadSigner
+	versionRetention            int
+	downloadMaxSize             int64
 }
 
 type DocumentService interface {
 	FindAllDocuments(ctx context.Context, pagReq dto.PaginationRequest, tx *gorm.DB) ([]dto.DocumentResponse, dto.PaginationResponse, error)
 	FindDocumentById(ctx context.Context, id string, tx *gorm.DB) (dto.DocumentResponse, error)
-	CreateDocument(ctx context.Context, document dto.DocumentRequest, file *multipart.FileHeader, tx *gorm.DB) error
-	UpdateDocument(ctx context.Context, id string, document dto.UpdateDocumentRequest, file *multipart.FileHeader, tx *gorm.DB) error
+	CreateDocument(ctx context.Context, document dto.DocumentRequest, file *multipart.FileHeader, token string, tx *gorm.DB) error
+	UpdateDocument(ctx context.Context, id string, document dto.UpdateDocumentRequest, file *multipart.FileHeader, token string, tx *gorm.DB) error
 	DeleteDocument(ctx context.Context, id string, tx *gorm.DB) error
+	FindDocumentVersions(ctx context.Context, id string, token string, tx *gorm.DB) ([]dto.DocumentVersionResponse, error)
+	FindDocumentVersion(ctx context.Context, id string, version int, token string, tx *gorm.DB) (dto.DocumentVersionResponse, error)
+	DownloadDocument(ctx context.Context, id string, version int, token string, tx *gorm.DB) (DocumentDownload, error)
+	DownloadSignedDocument(ctx context.Context, id string, version int, expires int64, signature string, tx *gorm.DB) (DocumentDownload, error)
+	CreateDownloadLink(ctx context.Context, id string, version int, token string, tx *gorm.DB) (dto.DocumentDownloadLinkResponse, error)
+	ReviewDocument(ctx context.Context, id string, review dto.DocumentReviewRequest, token string, tx *gorm.DB) error
 }
 
-func NewDocumentService(documentRepository repository.DocumentRepository, registrationRepository repository.RegistrationRepository, config *storageService.Config, tokenManager *storageService.CacheTokenManager) DocumentService {
+func NewDocumentService(documentRepository repository.DocumentRepository, registrationRepository repository.RegistrationRepository, documentRequirementRepository repository.DocumentRequirementRepository, documentVersionRepository repository.DocumentVersionRepository, advisorDelegationRepository repository.AdvisorDelegationRepository, uploadConfig config.UploadConfig, downloadConfig config.DownloadConfig, storageConfig config.StorageConfig, userManagementbaseURI string, brokerbaseURI string, asyncURIs []string, config *storageService.Config, tokenManager *storageService.CacheTokenManager) DocumentService {
 	return &documentService{
-		documentRepository:     documentRepository,
-		registrationRepository: registrationRepository,
-		fileService:            NewFileService(config, tokenManager),
+		documentRepository:          documentRepository,
+		registrationRepository:      registrationRepository,
+		documentVersionRepository:   documentVersionRepository,
+		advisorDelegationRepository: advisorDelegationRepository,
+		documentRequirementService:  NewDocumentRequirementService(documentRequirementRepository),
+		uploadValidator:             NewUploadValidator(uploadConfig),
+		userManagementService:       NewUserManagementService(userManagementbaseURI, asyncURIs),
+		brokerService:               NewBrokerService(brokerbaseURI, asyncURIs),
+		fileService:                 NewFileService(storageConfig, config, tokenManager),
+		downloadSigner:              NewDownloadSigner(downloadConfig.SigningKey, downloadConfig.LinkTTLSeconds, downloadConfig.PublicBaseURL),
+		versionRetention:            int(uploadConfig.VersionRetention),
+		downloadMaxSize:             downloadConfig.MaxSizeBytes,
 	}
 }
 
 func (s *documentService) FindAllDocuments(ctx context.Context, pagReq dto.PaginationRequest, tx *gorm.DB) ([]dto.DocumentResponse, dto.PaginationResponse, error) {
-	documents, total_data, err := s.documentRepository.Index(ctx, pagReq, tx)
-
-	metaData := helper.MetaDataPagination(total_data, pagReq)
-
+	documents, metaData, err := s.listDocuments(ctx, pagReq, tx)
 	if err != nil {
 		return nil, dto.PaginationResponse{}, err
 	}
 
 	var response []dto.DocumentResponse
 	for _, document := range documents {
-		response = append(response, dto.DocumentResponse{
-			ID:             document.ID.String(),
-			Name:           document.Name,
-			FileStorageID:  document.FileStorageID,
-			RegistrationID: document.RegistrationID,
-			DocumentType:   document.DocumentType,
-		})
+		response = append(response, toDocumentResponse(document))
 	}
 
 	return response, metaData, nil
 }
 
+// listDocuments reads a page of documents by page/limit or, when the request
+// carries a cursor, by keyset
+func (s *documentService) listDocuments(ctx context.Context, pagReq dto.PaginationRequest, tx *gorm.DB) ([]entity.Document, dto.PaginationResponse, error) {
+	if !pagReq.Keyset {
+		documents, total_data, err := s.documentRepository.Index(ctx, pagReq, tx)
+		if err != nil {
+			return nil, dto.PaginationResponse{}, err
+		}
+
+		return documents, helper.MetaDataPagination(total_data, pagReq), nil
+	}
+
+	documents, hasMore, total_data, err := s.documentRepository.IndexKeyset(ctx, pagReq, tx)
+	if err != nil {
+		return nil, dto.PaginationResponse{}, err
+	}
+
+	var first, last *dto.Cursor
+	if len(documents) > 0 {
+		first = documentCursor(documents[0])
+		last = documentCursor(documents[len(documents)-1])
+	}
+
+	return documents, helper.CursorMetaData(pagReq, first, last, hasMore, total_data), nil
+}
+
+func documentCursor(document entity.Document) *dto.Cursor {
+	cursor := &dto.Cursor{ID: document.ID.String()}
+	if document.CreatedAt != nil {
+		cursor.CreatedAt = *document.CreatedAt
+	}
+
+	return cursor
+}
+
 func (s *documentService) FindDocumentById(ctx context.Context, id string, tx *gorm.DB) (dto.DocumentResponse, error) {
 	document, err := s.documentRepository.FindByID(ctx, id, tx)
 	if err != nil {
 		return dto.DocumentResponse{}, err
 	}
 
-	response := dto.DocumentResponse{
-		ID:             document.ID.String(),
-		Name:           document.Name,
-		RegistrationID: document.RegistrationID,
-		FileStorageID:  document.FileStorageID,
-		DocumentType:   document.DocumentType,
+	return toDocumentResponse(document), nil
+}
+
+func (s *documentService) CreateDocument(ctx context.Context, document dto.DocumentRequest, file *multipart.FileHeader, token string, tx *gorm.DB) error {
+	registration, err := s.registrationRepository.FindByID(ctx, document.RegistrationID, tx)
+	if err != nil {
+		return err
 	}
 
-	return response, nil
-}
+	if registration.Status == entity.REGISTRATION_STATUS_WITHDRAWN {
+		return errors.New("registration has been withdrawn")
+	}
+
+	requirement, err := s.documentRequirementService.ValidateDocumentType(ctx, registration, document.DocumentType, tx)
+	if err != nil {
+		return err
+	}
+
+	uploaded, err := s.uploadValidator.Validate(ctx, requirement, file)
+	if err != nil {
+		return err
+	}
 
-func (s *documentService) CreateDocument(ctx context.Context, document dto.DocumentRequest, file *multipart.FileHeader, tx *gorm.DB) error {
 	// upload file
-	result, err := s.fileService.storage.GcsUpload(file, "sim_mbkm", "", "")
+	fileID, err := s.fileService.storage.Upload(ctx, file)
 	if err != nil {
 		return errors.New("failed to upload file")
 	}
@@ -93,43 +161,90 @@ func (s *documentService) CreateDocument(ctx context.Context, document dto.Docum
 
 	documentEntity.ID = uuid.New()
 	documentEntity.Name = document.Name
-	documentEntity.FileStorageID = result.FileID
+	documentEntity.FileStorageID = fileID
 	documentEntity.RegistrationID = document.RegistrationID
 	documentEntity.DocumentType = document.DocumentType
+	documentEntity.Checksum = uploaded.Checksum
+	documentEntity.ContentType = uploaded.ContentType
+	documentEntity.Size = uploaded.Size
+	documentEntity.CurrentVersion = 1
 
 	_, err = s.documentRepository.Create(ctx, documentEntity, tx)
 	if err != nil {
 		return err
 	}
 
+	userData := s.userManagementService.GetUserData("GET", token)
+	err = s.createVersion(ctx, documentEntity, userData, tx)
+	if err != nil {
+		return err
+	}
+
 	return nil
 
 }
 
-func (s *documentService) UpdateDocument(ctx context.Context, id string, document dto.UpdateDocumentRequest, file *multipart.FileHeader, tx *gorm.DB) error {
+// UpdateDocument uploads a new version of the document. The previous file is
+// kept as an older version until the retention policy prunes it.
+func (s *documentService) UpdateDocument(ctx context.Context, id string, document dto.UpdateDocumentRequest, file *multipart.FileHeader, token string, tx *gorm.DB) error {
 	res, err := s.documentRepository.FindByID(ctx, id, tx)
 	if err != nil {
 		return err
 	}
 
-	registration, err := s.registrationRepository.FindByID(ctx, string(document.RegistrationID), tx)
+	// checked before the upload so a stale request doesn't store a file
+	err = checkLockVersion(document.LockVersion, "document", id, res.LockVersion)
 	if err != nil {
 		return err
 	}
 
-	result, err := s.fileService.storage.GcsUpload(file, "sim_mbkm", "", "")
+	// the document stays with its registration, a request naming another one
+	// is refused rather than moving the document
+	if !strings.EqualFold(document.RegistrationID, res.RegistrationID) {
+		return errors.New("document does not belong to the registration")
+	}
+
+	registration, err := s.registrationRepository.FindByID(ctx, res.RegistrationID, tx)
+	if err != nil {
+		return err
+	}
+
+	requirement, err := s.documentRequirementService.ValidateDocumentType(ctx, registration, res.DocumentType, tx)
+	if err != nil {
+		return err
+	}
+
+	uploaded, err := s.uploadValidator.Validate(ctx, requirement, file)
+	if err != nil {
+		return err
+	}
+
+	fileID, err := s.fileService.storage.Upload(ctx, file)
 	if err != nil {
 		return errors.New("failed to upload file")
 	}
 
+	// documents uploaded before versioning have no version row yet
+	err = s.ensureInitialVersion(ctx, res, tx)
+	if err != nil {
+		return err
+	}
+
 	documentUpdate := DocumentUpdate{
-		RegistrationID: registration.ID.String(),
-		FileStorageID:  result.FileID,
+		RegistrationID: res.RegistrationID,
+		FileStorageID:  fileID,
+		Checksum:       uploaded.Checksum,
+		ContentType:    uploaded.ContentType,
+		Size:           uploaded.Size,
+		CurrentVersion: currentVersion(res) + 1,
+		// a new file has to be reviewed again
+		ReviewStatus: entity.DOCUMENT_REVIEW_PENDING,
 	}
 
 	// Create documentEntity with original ID
 	documentEntity := entity.Document{
-		ID: res.ID,
+		ID:          res.ID,
+		LockVersion: res.LockVersion,
 	}
 
 	// Get reflection values
@@ -166,7 +281,16 @@ func (s *documentService) UpdateDocument(ctx context.Context, id string, documen
 		return err
 	}
 
-	return nil
+	versioned := documentEntity
+	versioned.Name = res.Name
+
+	userData := s.userManagementService.GetUserData("GET", token)
+	err = s.createVersion(ctx, versioned, userData, tx)
+	if err != nil {
+		return err
+	}
+
+	return s.pruneVersions(ctx, id, tx)
 }
 
 func (s *documentService) DeleteDocument(ctx context.Context, id string, tx *gorm.DB) error {
@@ -175,11 +299,30 @@ func (s *documentService) DeleteDocument(ctx context.Context, id string, tx *gor
 		return err
 	}
 
-	_, err = s.fileService.storage.GcsDelete(res.FileStorageID, "sim_mbkm", "")
+	err = s.fileService.storage.Delete(ctx, res.FileStorageID)
 	if err != nil {
 		return err
 	}
 
+	// older versions go together with the document
+	versions, err := s.documentVersionRepository.FindByDocumentID(ctx, id, tx)
+	if err != nil {
+		return err
+	}
+	for _, version := range versions {
+		if version.FileStorageID != res.FileStorageID {
+			err = s.fileService.storage.Delete(ctx, version.FileStorageID)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = s.documentVersionRepository.DeleteByID(ctx, version.ID.String(), tx)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = s.documentRepository.DeleteByID(ctx, id, tx)
 	if err != nil {
 		return err
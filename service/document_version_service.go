@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"registration-service/dto"
+	"registration-service/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (s *documentService) FindDocumentVersions(ctx context.Context, id string, token string, tx *gorm.DB) ([]dto.DocumentVersionResponse, error) {
+	document, err := s.documentRepository.FindByID(ctx, id, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.documentAccess(ctx, document, token, tx) {
+		return nil, errors.New("data not found")
+	}
+
+	versions, err := s.documentVersionRepository.FindByDocumentID(ctx, id, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(versions) == 0 {
+		return []dto.DocumentVersionResponse{convertToDocumentVersionResponse(initialVersion(document))}, nil
+	}
+
+	var response []dto.DocumentVersionResponse
+	for _, version := range versions {
+		response = append(response, convertToDocumentVersionResponse(version))
+	}
+
+	return response, nil
+}
+
+func (s *documentService) FindDocumentVersion(ctx context.Context, id string, version int, token string, tx *gorm.DB) (dto.DocumentVersionResponse, error) {
+	document, err := s.documentRepository.FindByID(ctx, id, tx)
+	if err != nil {
+		return dto.DocumentVersionResponse{}, err
+	}
+
+	if !s.documentAccess(ctx, document, token, tx) {
+		return dto.DocumentVersionResponse{}, errors.New("data not found")
+	}
+
+	documentVersion, err := s.documentVersionRepository.FindByDocumentIDAndVersion(ctx, id, version, tx)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) && version == currentVersion(document) {
+			return convertToDocumentVersionResponse(initialVersion(document)), nil
+		}
+		return dto.DocumentVersionResponse{}, err
+	}
+
+	return convertToDocumentVersionResponse(documentVersion), nil
+}
+
+// documentAccess applies the registration access rules to its documents
+func (s *documentService) documentAccess(ctx context.Context, document entity.Document, token string, tx *gorm.DB) bool {
+	registration, err := s.registrationRepository.FindByID(ctx, document.RegistrationID, tx)
+	if err != nil {
+		return false
+	}
+
+	userData := s.userManagementService.GetUserData("GET", token)
+	if userData == nil {
+		return false
+	}
+
+	userID, _ := userData["id"].(string)
+	userRole, _ := userData["role"].(string)
+	userEmail, _ := userData["email"].(string)
+
+	switch userRole {
+	case "MAHASISWA":
+		return userID != "" && registration.UserID == userID
+	case "DOSEN PEMBIMBING":
+		if userEmail == "" {
+			return false
+		}
+		delegations, err := delegatedAdvisors(ctx, s.advisorDelegationRepository, userEmail, tx)
+		if err != nil {
+			return false
+		}
+		_, _, allowed := actingFor(registration, userEmail, delegations)
+		return allowed
+	case "ADMIN", "LO-MBKM":
+		return true
+	}
+
+	return false
+}
+
+func (s *documentService) createVersion(ctx context.Context, document entity.Document, userData map[string]interface{}, tx *gorm.DB) error {
+	version := entity.DocumentVersion{
+		ID:            uuid.New(),
+		DocumentID:    document.ID.String(),
+		Version:       currentVersion(document),
+		FileStorageID: document.FileStorageID,
+		Name:          document.Name,
+		Checksum:      document.Checksum,
+		ContentType:   document.ContentType,
+		Size:          document.Size,
+	}
+
+	if userData != nil {
+		version.UploadedByID, _ = userData["id"].(string)
+		version.UploadedByName, _ = userData["name"].(string)
+		version.UploadedByEmail, _ = userData["email"].(string)
+	}
+
+	_, err := s.documentVersionRepository.Create(ctx, version, tx)
+	return err
+}
+
+// ensureInitialVersion stores the current file of a document created before
+// versioning existed so that it is not lost on the first update.
+func (s *documentService) ensureInitialVersion(ctx context.Context, document entity.Document, tx *gorm.DB) error {
+	versions, err := s.documentVersionRepository.FindByDocumentID(ctx, document.ID.String(), tx)
+	if err != nil {
+		return err
+	}
+
+	if len(versions) > 0 {
+		return nil
+	}
+
+	_, err = s.documentVersionRepository.Create(ctx, initialVersion(document), tx)
+	return err
+}
+
+// pruneVersions removes the versions beyond the retention, the storage object
+// is deleted first so a failure leaves the row in place for the next attempt.
+func (s *documentService) pruneVersions(ctx context.Context, documentID string, tx *gorm.DB) error {
+	if s.versionRetention <= 0 {
+		return nil
+	}
+
+	versions, err := s.documentVersionRepository.FindByDocumentID(ctx, documentID, tx)
+	if err != nil {
+		return err
+	}
+
+	// versions are sorted newest first, the first one is the current file
+	keep := s.versionRetention + 1
+	if len(versions) <= keep {
+		return nil
+	}
+
+	for _, version := range versions[keep:] {
+		err = s.fileService.storage.Delete(ctx, version.FileStorageID)
+		if err != nil {
+			return err
+		}
+
+		err = s.documentVersionRepository.DeleteByID(ctx, version.ID.String(), tx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func initialVersion(document entity.Document) entity.DocumentVersion {
+	return entity.DocumentVersion{
+		ID:            uuid.New(),
+		DocumentID:    document.ID.String(),
+		Version:       currentVersion(document),
+		FileStorageID: document.FileStorageID,
+		Name:          document.Name,
+		Checksum:      document.Checksum,
+		ContentType:   document.ContentType,
+		Size:          document.Size,
+		BaseModel:     entity.BaseModel{CreatedAt: document.CreatedAt},
+	}
+}
+
+// currentVersion treats rows stored before versioning as version 1
+func currentVersion(document entity.Document) int {
+	if document.CurrentVersion == 0 {
+		return 1
+	}
+	return document.CurrentVersion
+}
+
+func convertToDocumentVersionResponse(version entity.DocumentVersion) dto.DocumentVersionResponse {
+	var createdAt string
+	if version.CreatedAt != nil {
+		createdAt = version.CreatedAt.Format(time.RFC3339)
+	}
+
+	return dto.DocumentVersionResponse{
+		ID:              version.ID.String(),
+		DocumentID:      version.DocumentID,
+		Version:         version.Version,
+		FileStorageID:   version.FileStorageID,
+		Name:            version.Name,
+		Checksum:        version.Checksum,
+		ContentType:     version.ContentType,
+		Size:            version.Size,
+		UploadedByID:    version.UploadedByID,
+		UploadedByName:  version.UploadedByName,
+		UploadedByEmail: version.UploadedByEmail,
+		CreatedAt:       createdAt,
+	}
+}
@@ -0,0 +1,288 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"registration-service/dto"
+	"registration-service/entity"
+	"registration-service/repository"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EligibilityInput is what a rule is evaluated against
+type EligibilityInput struct {
+	UserNRP       string
+	ActivityID    string
+	ProgramTypeID string
+	LevelID       string
+	// Semester and TotalSKS are 0 when they aren't known yet, e.g. when the
+	// student checks an activity before filling in the form
+	Semester int
+	TotalSKS int
+	// Registrations are the active registrations of the student
+	Registrations []entity.Registration
+}
+
+// EligibilityRuleEvaluator checks one configured rule and returns
+// ELIGIBILITY_RESULT_PASS or ELIGIBILITY_RESULT_FAIL with the reason, or
+// ELIGIBILITY_RESULT_WARN when it can't decide. A failure is turned into a
+// warning when the rule is configured with the WARN severity.
+type EligibilityRuleEvaluator func(input EligibilityInput, rule entity.EligibilityRule) (string, string)
+
+var eligibilityRuleEvaluators = map[string]EligibilityRuleEvaluator{
+	entity.ELIGIBILITY_RULE_MIN_SEMESTER:             evaluateMinSemester,
+	entity.ELIGIBILITY_RULE_MIN_TOTAL_SKS:            evaluateMinTotalSKS,
+	entity.ELIGIBILITY_RULE_MAX_CONCURRENT_PROGRAMS:  evaluateMaxConcurrentPrograms,
+	entity.ELIGIBILITY_RULE_ADVISOR_APPROVAL_HISTORY: evaluateAdvisorApprovalHistory,
+}
+
+// RegisterEligibilityRule makes a rule available to the configuration, it is
+// meant to be called from init
+func RegisterEligibilityRule(rule string, evaluator EligibilityRuleEvaluator) {
+	eligibilityRuleEvaluators[rule] = evaluator
+}
+
+// EligibilityRuleKeys lists the registered rules
+func EligibilityRuleKeys() []string {
+	keys := make([]string, 0, len(eligibilityRuleEvaluators))
+	for key := range eligibilityRuleEvaluators {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// EvaluateEligibilityRule runs the evaluator of a configured rule. A failure
+// of a rule with the WARN severity is reported as a warning, so is a rule that
+// is configured but not registered.
+func EvaluateEligibilityRule(input EligibilityInput, rule entity.EligibilityRule) (string, string) {
+	evaluator, ok := eligibilityRuleEvaluators[rule.Rule]
+	if !ok {
+		return entity.ELIGIBILITY_RESULT_WARN, "The rule is configured but not available in this version"
+	}
+
+	outcome, reason := evaluator(input, rule)
+	if outcome == entity.ELIGIBILITY_RESULT_FAIL && rule.Severity == entity.ELIGIBILITY_RESULT_WARN {
+		outcome = entity.ELIGIBILITY_RESULT_WARN
+	}
+
+	return outcome, reason
+}
+
+type eligibilityRuleService struct {
+	eligibilityRuleRepository repository.EligibilityRuleRepository
+}
+
+type EligibilityRuleService interface {
+	FindAllEligibilityRules(ctx context.Context, filter dto.FilterEligibilityRuleRequest, tx *gorm.DB) ([]dto.EligibilityRuleResponse, error)
+	FindEligibilityRuleByID(ctx context.Context, id string, tx *gorm.DB) (dto.EligibilityRuleResponse, error)
+	CreateEligibilityRule(ctx context.Context, request dto.EligibilityRuleRequest, tx *gorm.DB) error
+	UpdateEligibilityRule(ctx context.Context, id string, request dto.EligibilityRuleRequest, tx *gorm.DB) error
+	DeleteEligibilityRule(ctx context.Context, id string, tx *gorm.DB) error
+	FindRulesForActivity(ctx context.Context, programTypeID string, levelID string, tx *gorm.DB) ([]entity.EligibilityRule, error)
+}
+
+func NewEligibilityRuleService(eligibilityRuleRepository repository.EligibilityRuleRepository) EligibilityRuleService {
+	return &eligibilityRuleService{
+		eligibilityRuleRepository: eligibilityRuleRepository,
+	}
+}
+
+func (s *eligibilityRuleService) FindAllEligibilityRules(ctx context.Context, filter dto.FilterEligibilityRuleRequest, tx *gorm.DB) ([]dto.EligibilityRuleResponse, error) {
+	rules, err := s.eligibilityRuleRepository.Index(ctx, filter, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var response []dto.EligibilityRuleResponse
+	for _, rule := range rules {
+		response = append(response, convertToEligibilityRuleResponse(rule))
+	}
+
+	return response, nil
+}
+
+func (s *eligibilityRuleService) FindEligibilityRuleByID(ctx context.Context, id string, tx *gorm.DB) (dto.EligibilityRuleResponse, error) {
+	rule, err := s.eligibilityRuleRepository.FindByID(ctx, id, tx)
+	if err != nil {
+		return dto.EligibilityRuleResponse{}, err
+	}
+
+	return convertToEligibilityRuleResponse(rule), nil
+}
+
+func (s *eligibilityRuleService) CreateEligibilityRule(ctx context.Context, request dto.EligibilityRuleRequest, tx *gorm.DB) error {
+	rule, err := eligibilityRuleFromRequest(request)
+	if err != nil {
+		return err
+	}
+
+	rule.ID = uuid.New()
+	_, err = s.eligibilityRuleRepository.Create(ctx, rule, tx)
+
+	return err
+}
+
+func (s *eligibilityRuleService) UpdateEligibilityRule(ctx context.Context, id string, request dto.EligibilityRuleRequest, tx *gorm.DB) error {
+	_, err := s.eligibilityRuleRepository.FindByID(ctx, id, tx)
+	if err != nil {
+		return err
+	}
+
+	rule, err := eligibilityRuleFromRequest(request)
+	if err != nil {
+		return err
+	}
+
+	return s.eligibilityRuleRepository.Update(ctx, id, rule, tx)
+}
+
+func (s *eligibilityRuleService) DeleteEligibilityRule(ctx context.Context, id string, tx *gorm.DB) error {
+	return s.eligibilityRuleRepository.Destroy(ctx, id, tx)
+}
+
+// FindRulesForActivity resolves the enabled rules for an activity. Per rule
+// the most specific entry wins: program type and level, then program type,
+// then level and finally the default entry.
+func (s *eligibilityRuleService) FindRulesForActivity(ctx context.Context, programTypeID string, levelID string, tx *gorm.DB) ([]entity.EligibilityRule, error) {
+	rules, err := s.eligibilityRuleRepository.FindByScope(ctx, programTypeID, levelID, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	byRule := map[string]entity.EligibilityRule{}
+	for _, rule := range rules {
+		current, ok := byRule[rule.Rule]
+		// entries come oldest first, a newer entry of the same scope replaces an older one
+		if !ok || eligibilityRuleSpecificity(rule) >= eligibilityRuleSpecificity(current) {
+			byRule[rule.Rule] = rule
+		}
+	}
+
+	var resolved []entity.EligibilityRule
+	for _, rule := range byRule {
+		if rule.Enabled {
+			resolved = append(resolved, rule)
+		}
+	}
+
+	sort.Slice(resolved, func(i, j int) bool {
+		return resolved[i].Rule < resolved[j].Rule
+	})
+
+	return resolved, nil
+}
+
+func eligibilityRuleSpecificity(rule entity.EligibilityRule) int {
+	specificity := 0
+	if rule.ProgramTypeID != "" {
+		specificity += 2
+	}
+	if rule.LevelID != "" {
+		specificity++
+	}
+
+	return specificity
+}
+
+func eligibilityRuleFromRequest(request dto.EligibilityRuleRequest) (entity.EligibilityRule, error) {
+	rule := strings.TrimSpace(request.Rule)
+	if _, ok := eligibilityRuleEvaluators[rule]; !ok {
+		return entity.EligibilityRule{}, fmt.Errorf("unknown eligibility rule, available rules: %s", strings.Join(EligibilityRuleKeys(), ", "))
+	}
+
+	severity := request.Severity
+	if severity == "" {
+		severity = entity.ELIGIBILITY_RESULT_FAIL
+	}
+
+	enabled := true
+	if request.Enabled != nil {
+		enabled = *request.Enabled
+	}
+
+	return entity.EligibilityRule{
+		ProgramTypeID: request.ProgramTypeID,
+		LevelID:       request.LevelID,
+		Rule:          rule,
+		Value:         request.Value,
+		Severity:      severity,
+		Enabled:       enabled,
+		Description:   request.Description,
+	}, nil
+}
+
+func convertToEligibilityRuleResponse(rule entity.EligibilityRule) dto.EligibilityRuleResponse {
+	return dto.EligibilityRuleResponse{
+		ID:            rule.ID.String(),
+		ProgramTypeID: rule.ProgramTypeID,
+		LevelID:       rule.LevelID,
+		Rule:          rule.Rule,
+		Value:         rule.Value,
+		Severity:      rule.Severity,
+		Enabled:       rule.Enabled,
+		Description:   rule.Description,
+	}
+}
+
+func evaluateMinSemester(input EligibilityInput, rule entity.EligibilityRule) (string, string) {
+	if input.Semester == 0 {
+		return entity.ELIGIBILITY_RESULT_WARN, fmt.Sprintf("Semester %d or higher is required, it is checked when the registration is submitted", rule.Value)
+	}
+
+	if input.Semester < rule.Value {
+		return entity.ELIGIBILITY_RESULT_FAIL, fmt.Sprintf("Semester %d or higher is required, the student is in semester %d", rule.Value, input.Semester)
+	}
+
+	return entity.ELIGIBILITY_RESULT_PASS, fmt.Sprintf("Semester %d meets the minimum of %d", input.Semester, rule.Value)
+}
+
+func evaluateMinTotalSKS(input EligibilityInput, rule entity.EligibilityRule) (string, string) {
+	if input.TotalSKS == 0 {
+		return entity.ELIGIBILITY_RESULT_WARN, fmt.Sprintf("At least %d SKS are required, it is checked when the registration is submitted", rule.Value)
+	}
+
+	if input.TotalSKS < rule.Value {
+		return entity.ELIGIBILITY_RESULT_FAIL, fmt.Sprintf("At least %d SKS are required, the student has %d", rule.Value, input.TotalSKS)
+	}
+
+	return entity.ELIGIBILITY_RESULT_PASS, fmt.Sprintf("%d SKS meets the minimum of %d", input.TotalSKS, rule.Value)
+}
+
+// evaluateMaxConcurrentPrograms counts the submitted registrations still in
+// progress, drafts don't hold a place yet
+func evaluateMaxConcurrentPrograms(input EligibilityInput, rule entity.EligibilityRule) (string, string) {
+	running := 0
+	for _, registration := range input.Registrations {
+		if registration.ActivityID == input.ActivityID || registration.Status == entity.REGISTRATION_STATUS_DRAFT {
+			continue
+		}
+		running++
+	}
+
+	if running >= rule.Value {
+		return entity.ELIGIBILITY_RESULT_FAIL, fmt.Sprintf("At most %d MBKM programs may run at the same time, the student has %d", rule.Value, running)
+	}
+
+	return entity.ELIGIBILITY_RESULT_PASS, fmt.Sprintf("%d of at most %d MBKM programs running", running, rule.Value)
+}
+
+func evaluateAdvisorApprovalHistory(input EligibilityInput, rule entity.EligibilityRule) (string, string) {
+	approved := 0
+	for _, registration := range input.Registrations {
+		if registration.AcademicAdvisorValidation == "APPROVED" {
+			approved++
+		}
+	}
+
+	if approved < rule.Value {
+		return entity.ELIGIBILITY_RESULT_FAIL, fmt.Sprintf("%d registrations approved by an academic advisor are required, the student has %d", rule.Value, approved)
+	}
+
+	return entity.ELIGIBILITY_RESULT_PASS, fmt.Sprintf("%d registrations approved by an academic advisor", approved)
+}
@@ -1,15 +1,133 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"registration-service/config"
+	"strings"
+	"time"
+
 	storageService "github.com/SIM-MBKM/filestorage/storage"
 )
 
+// DocumentStorage is the backend documents are stored in, the returned file
+// id is what entity.Document keeps as FileStorageID.
+type DocumentStorage interface {
+	Upload(ctx context.Context, file *multipart.FileHeader) (string, error)
+	Download(ctx context.Context, fileID string) (FileObject, error)
+	Delete(ctx context.Context, fileID string) error
+}
+
 type FileService struct {
-	storage *storageService.FileStorageManager
+	storage DocumentStorage
+}
+
+// FileObject is an object fetched back from the storage, Size is -1 when the
+// storage doesn't send it
+type FileObject struct {
+	Content     io.ReadCloser
+	ContentType string
+	Size        int64
 }
 
-func NewFileService(config *storageService.Config, tokenManager *storageService.CacheTokenManager) *FileService {
-	return &FileService{
-		storage: storageService.NewFileStorageManager(config, tokenManager),
+const (
+	STORAGE_DRIVER_GCS   = "gcs"
+	STORAGE_DRIVER_S3    = "s3"
+	STORAGE_DRIVER_LOCAL = "local"
+)
+
+// NewFileService picks the storage backend from the config, the file storage
+// manager is only needed by the gcs driver and may be nil otherwise.
+func NewFileService(storageConfig config.StorageConfig, config *storageService.Config, tokenManager *storageService.CacheTokenManager) *FileService {
+	switch strings.ToLower(storageConfig.Driver) {
+	case STORAGE_DRIVER_S3:
+		return &FileService{storage: NewS3Storage(storageConfig)}
+	case STORAGE_DRIVER_LOCAL:
+		return &FileService{storage: NewLocalStorage(storageConfig.LocalDirectory)}
+	case STORAGE_DRIVER_GCS:
+		return &FileService{storage: NewGCSStorage(storageConfig, storageService.NewFileStorageManager(config, tokenManager))}
 	}
+
+	log.Fatalf("unknown storage driver %q", storageConfig.Driver)
+	return nil
+}
+
+// NewFileServiceWithStorage wraps an existing backend, mainly for tests
+func NewFileServiceWithStorage(storage DocumentStorage) *FileService {
+	return &FileService{storage: storage}
+}
+
+// gcsStorage stores documents through the SIM-MBKM file storage service
+type gcsStorage struct {
+	manager     *storageService.FileStorageManager
+	projectID   string
+	bucket      string
+	downloadURL string
+	client      *http.Client
+}
+
+func NewGCSStorage(storageConfig config.StorageConfig, manager *storageService.FileStorageManager) DocumentStorage {
+	return &gcsStorage{
+		manager:     manager,
+		projectID:   storageConfig.GCSProjectID,
+		bucket:      storageConfig.GCSBucket,
+		downloadURL: storageConfig.GCSDownloadURL,
+		client:      &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+func (s *gcsStorage) Upload(ctx context.Context, file *multipart.FileHeader) (string, error) {
+	result, err := s.manager.GcsUpload(file, s.projectID, s.bucket, "")
+	if err != nil {
+		return "", err
+	}
+
+	return result.FileID, nil
+}
+
+// Download fetches the object from the file storage service, the {file_id}
+// placeholder of the download URL is replaced with the storage id.
+func (s *gcsStorage) Download(ctx context.Context, fileID string) (FileObject, error) {
+	if s.downloadURL == "" {
+		return FileObject{}, errors.New("document download is not configured")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.ReplaceAll(s.downloadURL, "{file_id}", url.PathEscape(fileID)), nil)
+	if err != nil {
+		return FileObject{}, err
+	}
+
+	return doDownload(s.client, req)
+}
+
+func (s *gcsStorage) Delete(ctx context.Context, fileID string) error {
+	_, err := s.manager.GcsDelete(fileID, s.projectID, s.bucket)
+	return err
+}
+
+func doDownload(client *http.Client, req *http.Request) (FileObject, error) {
+	res, err := client.Do(req)
+	if err != nil {
+		return FileObject{}, errors.New("failed to download file")
+	}
+
+	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+		if res.StatusCode == http.StatusNotFound {
+			return FileObject{}, errors.New("file not found")
+		}
+		return FileObject{}, fmt.Errorf("failed to download file: storage responded %d", res.StatusCode)
+	}
+
+	return FileObject{
+		Content:     res.Body,
+		ContentType: res.Header.Get("Content-Type"),
+		Size:        res.ContentLength,
+	}, nil
 }
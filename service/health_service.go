@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"registration-service/dto"
+	"registration-service/migration"
+
+	"gorm.io/gorm"
+)
+
+type healthService struct {
+	db       *gorm.DB
+	migrator *migration.Migrator
+}
+
+type HealthService interface {
+	Ready(ctx context.Context) (dto.HealthResponse, bool)
+}
+
+func NewHealthService(db *gorm.DB, migrator *migration.Migrator) HealthService {
+	return &healthService{db: db, migrator: migrator}
+}
+
+// Ready reports whether the database answers and its schema is at the version
+// of the newest migration this build ships with
+func (s *healthService) Ready(ctx context.Context) (dto.HealthResponse, bool) {
+	response := dto.HealthResponse{
+		Database:              "down",
+		ExpectedSchemaVersion: s.migrator.Latest(),
+	}
+
+	sqlDB, err := s.db.DB()
+	if err == nil {
+		err = sqlDB.PingContext(ctx)
+	}
+	if err != nil {
+		response.Error = err.Error()
+		return response, false
+	}
+	response.Database = "up"
+
+	version, err := s.migrator.Version(ctx)
+	if err != nil {
+		response.Error = err.Error()
+		return response, false
+	}
+	response.SchemaVersion = version
+
+	if version != response.ExpectedSchemaVersion {
+		response.Error = fmt.Sprintf("schema is at version %d, expected %d", version, response.ExpectedSchemaVersion)
+		return response, false
+	}
+
+	return response, true
+}
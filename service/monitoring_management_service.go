@@ -134,3 +134,25 @@ func (s *MonitoringManagementService) CreateReportSchedule(data map[string]inter
 
 	return errors.New("failed to create report schedule")
 }
+
+func (s *MonitoringManagementService) CancelReportSchedulesByRegistrationID(registrationID string, token string) error {
+	// split token
+	tokenParts := strings.Split(token, " ")
+	if len(tokenParts) != 2 {
+		return errors.New("invalid token")
+	}
+
+	token = tokenParts[1]
+
+	// DELETE on the same report schedules of a registration the GET reads
+	endpoint := fmt.Sprintf("%s%s", GET_REPORT_SCHEDULES_BY_REGISTRATION_ID, registrationID)
+	_, err := s.baseService.Request("DELETE", endpoint, nil, token)
+	if err != nil {
+		if err.Error() != "404 Not Found" {
+			log.Println("Error in CancelReportSchedulesByRegistrationID:", err)
+			return err
+		}
+	}
+
+	return nil
+}
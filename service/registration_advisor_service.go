@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"registration-service/dto"
+	"registration-service/entity"
+	"registration-service/repository"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ReassignAdvisor moves registrations to another academic advisor, either the
+// listed ones or every registration of FromAdvisorEmail that isn't withdrawn.
+// The new advisor always has to be an academic advisor in user management.
+// Registrations already assigned to them are skipped.
+func (s *registrationService) ReassignAdvisor(ctx context.Context, request dto.AdvisorReassignmentRequest, token string, tx *gorm.DB) (dto.AdvisorReassignmentResponse, error) {
+	userData := s.userManagementService.GetUserData("GET", token)
+	if userData == nil {
+		return dto.AdvisorReassignmentResponse{}, errors.New("Unauthorized")
+	}
+
+	if len(request.RegistrationIDs) == 0 && request.FromAdvisorEmail == "" {
+		return dto.AdvisorReassignmentResponse{}, errors.New("registration_ids or from_advisor_email is required")
+	}
+
+	dosen := s.userManagementService.GetDosenDataByEmail(strings.TrimSpace(request.AdvisorEmail), "GET", token)
+	if dosen == nil {
+		return dto.AdvisorReassignmentResponse{}, errors.New("academic advisor not found")
+	}
+
+	advisor, err := validateAdvisorData(advisorAssignment{Email: request.AdvisorEmail}, dosen)
+	if err != nil {
+		return dto.AdvisorReassignmentResponse{}, err
+	}
+
+	response := dto.AdvisorReassignmentResponse{
+		AdvisorEmail:    advisor.Email,
+		AdvisorName:     advisor.Name,
+		RegistrationIDs: []string{},
+		Skipped:         []string{},
+	}
+
+	err = s.registrationRepository.WithTransaction(ctx, func(tx *gorm.DB) error {
+		registrations, err := s.registrationsToReassign(ctx, request, tx)
+		if err != nil {
+			return err
+		}
+
+		for _, registration := range registrations {
+			if registration.Status == entity.REGISTRATION_STATUS_WITHDRAWN || strings.EqualFold(registration.AcademicAdvisorEmail, advisor.Email) {
+				response.Skipped = append(response.Skipped, registration.ID.String())
+				continue
+			}
+
+			note := reassignmentNote(registration.AcademicAdvisorEmail, advisor.Email, request.Reason)
+			assignAdvisor(&registration, advisor, request.ResetApproval)
+
+			err = s.registrationRepository.Update(ctx, registration.ID.String(), registration, tx)
+			if err != nil {
+				return err
+			}
+
+			err = s.recordHistory(ctx, registration, entity.REGISTRATION_HISTORY_ADVISOR_REASSIGNED, registration.Status, note, userData, tx)
+			if err != nil {
+				return err
+			}
+
+			response.RegistrationIDs = append(response.RegistrationIDs, registration.ID.String())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return dto.AdvisorReassignmentResponse{}, err
+	}
+
+	response.Reassigned = len(response.RegistrationIDs)
+	return response, nil
+}
+
+func (s *registrationService) registrationsToReassign(ctx context.Context, request dto.AdvisorReassignmentRequest, tx *gorm.DB) ([]entity.Registration, error) {
+	if len(request.RegistrationIDs) == 0 {
+		return s.registrationRepository.FindByAdvisorEmail(ctx, request.FromAdvisorEmail, tx)
+	}
+
+	registrations := make([]entity.Registration, 0, len(request.RegistrationIDs))
+	for _, id := range request.RegistrationIDs {
+		registration, err := s.registrationRepository.FindByID(ctx, id, tx)
+		if err != nil {
+			return nil, fmt.Errorf("registration %s: %w", id, err)
+		}
+
+		if request.FromAdvisorEmail != "" && !strings.EqualFold(registration.AcademicAdvisorEmail, request.FromAdvisorEmail) {
+			return nil, fmt.Errorf("registration %s is not assigned to %s", id, request.FromAdvisorEmail)
+		}
+
+		registrations = append(registrations, registration)
+	}
+
+	return registrations, nil
+}
+
+// assignAdvisor hands the registration to another advisor. A decision the
+// previous advisor already took stays unless resetApproval is set, then the
+// registration waits for the new advisor again.
+func assignAdvisor(registration *entity.Registration, advisor advisorAssignment, resetApproval bool) {
+	registration.AcademicAdvisorID = advisor.ID
+	registration.AcademicAdvisor = advisor.Name
+	registration.AcademicAdvisorEmail = advisor.Email
+
+	if registration.Status == entity.REGISTRATION_STATUS_DRAFT {
+		return
+	}
+
+	if resetApproval || (registration.AcademicAdvisorValidation != "APPROVED" && registration.AcademicAdvisorValidation != "REJECTED") {
+		registration.AcademicAdvisorValidation = "PENDING"
+		registration.ApprovalStatus = false
+	}
+}
+
+func reassignmentNote(from string, to string, reason string) string {
+	note := fmt.Sprintf("academic advisor changed from %s to %s", from, to)
+	if reason != "" {
+		note += ": " + reason
+	}
+
+	return note
+}
+
+// delegatedAdvisors returns the delegations the user currently holds, keyed
+// by the email of the advisor they stand in for
+func (s *registrationService) delegatedAdvisors(ctx context.Context, userEmail string, tx *gorm.DB) (map[string]entity.AdvisorDelegation, error) {
+	return delegatedAdvisors(ctx, s.advisorDelegationRepository, userEmail, tx)
+}
+
+func delegatedAdvisors(ctx context.Context, advisorDelegationRepository repository.AdvisorDelegationRepository, userEmail string, tx *gorm.DB) (map[string]entity.AdvisorDelegation, error) {
+	delegations, err := advisorDelegationRepository.FindActiveByDelegateEmail(ctx, userEmail, time.Now(), tx)
+	if err != nil {
+		return nil, err
+	}
+
+	advisors := make(map[string]entity.AdvisorDelegation, len(delegations))
+	for _, delegation := range delegations {
+		advisors[strings.ToLower(delegation.AdvisorEmail)] = delegation
+	}
+
+	return advisors, nil
+}
+
+// actingFor tells whether the user may act as the advisor of the registration,
+// delegated is true when they do so on behalf of another advisor
+func actingFor(registration entity.Registration, userEmail string, delegations map[string]entity.AdvisorDelegation) (delegation entity.AdvisorDelegation, delegated bool, allowed bool) {
+	if registration.AcademicAdvisorEmail == userEmail {
+		return entity.AdvisorDelegation{}, false, true
+	}
+
+	delegation, delegated = delegations[strings.ToLower(registration.AcademicAdvisorEmail)]
+	return delegation, delegated, delegated
+}
+
+func delegationNote(decision string, delegation entity.AdvisorDelegation) string {
+	return fmt.Sprintf("%s on behalf of %s", decision, delegation.AdvisorEmail)
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"registration-service/dto"
+	"registration-service/entity"
+	"registration-service/repository"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CreateDraftRegistration stores a registration with partial data. Documents
+// are attached afterwards through the document endpoints and the draft only
+// enters the approval pipeline once SubmitRegistration accepts it.
+func (s *registrationService) CreateDraftRegistration(ctx context.Context, registration dto.CreateDraftRegistrationRequest, token string, tx *gorm.DB) (dto.GetRegistrationResponse, error) {
+	userData := s.userManagementService.GetUserData("GET", token)
+	if userData == nil {
+		return dto.GetRegistrationResponse{}, errors.New("user data not found")
+	}
+
+	userID, _ := userData["id"].(string)
+	userNRP, _ := userData["nrp"].(string)
+	userName, _ := userData["name"].(string)
+	if userID == "" || userNRP == "" {
+		return dto.GetRegistrationResponse{}, errors.New("User not found")
+	}
+
+	activitiesData := s.activityManagementService.GetActivitiesData(map[string]interface{}{
+		"activity_id":     registration.ActivityID,
+		"program_type_id": "",
+		"level_id":        "",
+		"group_id":        "",
+		"name":            "",
+	}, "POST", token)
+	if len(activitiesData) == 0 {
+		return dto.GetRegistrationResponse{}, errors.New("Activity not found")
+	}
+
+	activityName, ok := activitiesData[0]["name"].(string)
+	if !ok {
+		return dto.GetRegistrationResponse{}, errors.New("Activity not found")
+	}
+	programTypeID, _ := activitiesData[0]["program_type_id"].(string)
+
+	advisor, err := s.resolveAdvisor(userNRP, advisorAssignment{ID: registration.AcademicAdvisorID, Name: registration.AcademicAdvisor, Email: registration.AcademicAdvisorEmail}, false, token)
+	if err != nil {
+		return dto.GetRegistrationResponse{}, err
+	}
+
+	registrationEntity := entity.Registration{
+		ID:                        uuid.New(),
+		ActivityID:                registration.ActivityID,
+		ActivityName:              activityName,
+		ProgramTypeID:             programTypeID,
+		UserID:                    userID,
+		UserNRP:                   userNRP,
+		UserName:                  userName,
+		AdvisingConfirmation:      registration.AdvisingConfirmation,
+		AcademicAdvisorID:         advisor.ID,
+		AcademicAdvisor:           advisor.Name,
+		AcademicAdvisorEmail:      advisor.Email,
+		MentorName:                registration.MentorName,
+		MentorEmail:               registration.MentorEmail,
+		LOValidation:              "PENDING",
+		AcademicAdvisorValidation: "PENDING",
+		Semester:                  registration.Semester,
+		TotalSKS:                  registration.TotalSKS,
+		Status:                    entity.REGISTRATION_STATUS_DRAFT,
+	}
+	s.checkAcademicRecord(ctx, userNRP, registration.Semester, registration.TotalSKS, token).applyTo(&registrationEntity)
+
+	// same checks as a full registration: activity open, no duplicate, no
+	// overlapping period. They run under the student lock like CreateRegistration.
+	err = s.registrationRepository.WithTransaction(ctx, func(tx *gorm.DB) error {
+		err := s.registrationRepository.LockNRP(ctx, userNRP, tx)
+		if err != nil {
+			return err
+		}
+
+		eligibility, err := s.checkEligibility(ctx, eligibilityApplicant{NRP: userNRP, Semester: registrationEntity.Semester, TotalSKS: registrationEntity.TotalSKS}, registration.ActivityID, token, tx)
+		if err != nil {
+			if errors.Is(err, repository.ErrConflict) {
+				return err
+			}
+			return errors.New(eligibility.Message)
+		}
+		registrationEntity.AcademicPeriodID = academicPeriodID(eligibility)
+
+		_, err = s.registrationRepository.Create(ctx, registrationEntity, tx)
+		if err != nil {
+			return err
+		}
+
+		return s.recordHistory(ctx, registrationEntity, entity.REGISTRATION_HISTORY_DRAFT_CREATED, "", "", userData, tx)
+	})
+	if err != nil {
+		return dto.GetRegistrationResponse{}, err
+	}
+
+	return dto.GetRegistrationResponse{
+		ID:                        registrationEntity.ID.String(),
+		ActivityID:                registrationEntity.ActivityID,
+		ActivityName:              registrationEntity.ActivityName,
+		AcademicPeriodID:          registrationEntity.AcademicPeriodID,
+		UserID:                    registrationEntity.UserID,
+		UserNRP:                   registrationEntity.UserNRP,
+		UserName:                  registrationEntity.UserName,
+		AdvisingConfirmation:      registrationEntity.AdvisingConfirmation,
+		AcademicAdvisor:           registrationEntity.AcademicAdvisor,
+		AcademicAdvisorEmail:      registrationEntity.AcademicAdvisorEmail,
+		MentorName:                registrationEntity.MentorName,
+		MentorEmail:               registrationEntity.MentorEmail,
+		LOValidation:              registrationEntity.LOValidation,
+		AcademicAdvisorValidation: registrationEntity.AcademicAdvisorValidation,
+		Semester:                  registrationEntity.Semester,
+		TotalSKS:                  registrationEntity.TotalSKS,
+		Status:                    registrationEntity.Status,
+		LockVersion:               registrationEntity.LockVersion,
+		DocumentCompleteness:      s.documentCompleteness(ctx, registrationEntity, tx),
+		AcademicRecord:            academicRecordResponse(registrationEntity),
+	}, nil
+}
+
+// SubmitRegistration validates that a draft is complete and moves it into the
+// approval pipeline.
+func (s *registrationService) SubmitRegistration(ctx context.Context, id string, token string, tx *gorm.DB) error {
+	userNRP := s.ValidateStudent(ctx, token, tx)
+	if userNRP == "" {
+		return errors.New("Unauthorized")
+	}
+
+	// the check and the update run under the student's lock, like a new
+	// registration, so two submissions can't both pass the overlap check
+	var registration entity.Registration
+	userData := s.userManagementService.GetUserData("GET", token)
+	submit := func(tx *gorm.DB) error {
+		err := s.registrationRepository.LockNRP(ctx, userNRP, tx)
+		if err != nil {
+			return err
+		}
+
+		registration, err = s.registrationRepository.FindByID(ctx, id, tx)
+		if err != nil {
+			return err
+		}
+
+		if registration.UserNRP != userNRP {
+			return errors.New("data not found")
+		}
+
+		if registration.Status != entity.REGISTRATION_STATUS_DRAFT {
+			return errors.New("registration already submitted")
+		}
+
+		// the advisor is checked again, in auto_fill mode a draft without one gets
+		// the student's assigned advisor now
+		advisor, err := s.resolveAdvisor(userNRP, advisorAssignment{ID: registration.AcademicAdvisorID, Name: registration.AcademicAdvisor, Email: registration.AcademicAdvisorEmail}, false, token)
+		if err != nil {
+			return err
+		}
+		registration.AcademicAdvisorID = advisor.ID
+		registration.AcademicAdvisor = advisor.Name
+		registration.AcademicAdvisorEmail = advisor.Email
+
+		missing := validateDraftCompleteness(registration)
+
+		completeness, err := s.documentRequirementService.CheckCompleteness(ctx, registration, tx)
+		if err != nil {
+			return err
+		}
+		for _, documentType := range completeness.Missing {
+			missing = append(missing, fmt.Sprintf("%s is required", documentType))
+		}
+
+		if len(missing) > 0 {
+			return fmt.Errorf("registration is incomplete: %s", strings.Join(missing, ", "))
+		}
+
+		// semester and SKS are filled in now, check them against the record again.
+		// A corrected draft that wasn't edited keeps what the student typed in.
+		semester, totalSKS := registration.Semester, registration.TotalSKS
+		if registration.AcademicRecordStatus == entity.ACADEMIC_RECORD_STATUS_CORRECTED && semester == registration.RecordSemester && totalSKS == registration.RecordTotalSKS {
+			semester, totalSKS = registration.ReportedSemester, registration.ReportedTotalSKS
+		}
+		s.checkAcademicRecord(ctx, userNRP, semester, totalSKS, token).applyTo(&registration)
+
+		// the activity may have been closed while the student was filling in the
+		// draft, and the rules can only check semester and SKS now they are filled in
+		eligibility, err := s.checkEligibility(ctx, eligibilityApplicant{NRP: userNRP, Semester: registration.Semester, TotalSKS: registration.TotalSKS, RegistrationID: id}, registration.ActivityID, token, tx)
+		if err != nil {
+			return err
+		}
+
+		// the period may have been created after the draft
+		if registration.AcademicPeriodID == "" {
+			registration.AcademicPeriodID = academicPeriodID(eligibility)
+		}
+		registration.Status = entity.REGISTRATION_STATUS_ACTIVE
+		registration.LOValidation = "PENDING"
+		registration.AcademicAdvisorValidation = "PENDING"
+
+		err = s.registrationRepository.Update(ctx, id, registration, tx)
+		if err != nil {
+			return err
+		}
+
+		err = s.recordHistory(ctx, registration, entity.REGISTRATION_HISTORY_SUBMITTED, entity.REGISTRATION_STATUS_DRAFT, "", userData, tx)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	var err error
+	if tx != nil {
+		err = submit(tx)
+	} else {
+		err = s.registrationRepository.WithTransaction(ctx, submit)
+	}
+	if err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("%s has registered for %s", registration.UserName, registration.ActivityName)
+	message += s.documentLinksMessage(registration.Document)
+	// send notification to academic advisor
+	s.brokerService.SendNotification(map[string]interface{}{
+		"sender_name":    registration.UserName,
+		"sender_email":   userData["email"],
+		"receiver_email": registration.AcademicAdvisorEmail,
+		"type":           "REGISTER",
+		"message":        message,
+	}, "POST", token)
+
+	return nil
+}
+
+// validateDraftCompleteness lists the form fields still missing before a draft
+// can be submitted, documents are checked against the requirement catalogue.
+func validateDraftCompleteness(registration entity.Registration) []string {
+	var missing []string
+
+	if registration.AcademicAdvisorID == "" || registration.AcademicAdvisor == "" || registration.AcademicAdvisorEmail == "" {
+		missing = append(missing, "academic advisor is required")
+	}
+	if registration.MentorName == "" || registration.MentorEmail == "" {
+		missing = append(missing, "mentor is required")
+	}
+	if registration.Semester == 0 {
+		missing = append(missing, "semester is required")
+	}
+	if registration.TotalSKS == 0 {
+		missing = append(missing, "total sks is required")
+	}
+
+	return missing
+}
@@ -0,0 +1,371 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"registration-service/dto"
+	"registration-service/entity"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// fields of a registration the edit rules are written for, the three advisor
+// fields are edited together as academic_advisor
+const (
+	REGISTRATION_FIELD_ADVISING_CONFIRMATION = "advising_confirmation"
+	REGISTRATION_FIELD_ACADEMIC_ADVISOR      = "academic_advisor"
+	REGISTRATION_FIELD_MENTOR_NAME           = "mentor_name"
+	REGISTRATION_FIELD_MENTOR_EMAIL          = "mentor_email"
+	REGISTRATION_FIELD_SEMESTER              = "semester"
+	REGISTRATION_FIELD_TOTAL_SKS             = "total_sks"
+)
+
+// lifecycle states the edit rules distinguish, an ACTIVE registration is
+// SUBMITTED until one of the reviewers approves it
+const (
+	REGISTRATION_EDIT_STATE_DRAFT                = entity.REGISTRATION_STATUS_DRAFT
+	REGISTRATION_EDIT_STATE_SUBMITTED            = "SUBMITTED"
+	REGISTRATION_EDIT_STATE_APPROVED             = "APPROVED"
+	REGISTRATION_EDIT_STATE_WITHDRAWAL_REQUESTED = entity.REGISTRATION_STATUS_WITHDRAWAL_REQUESTED
+	REGISTRATION_EDIT_STATE_WITHDRAWN            = entity.REGISTRATION_STATUS_WITHDRAWN
+)
+
+var allRegistrationFields = []string{
+	REGISTRATION_FIELD_ADVISING_CONFIRMATION,
+	REGISTRATION_FIELD_ACADEMIC_ADVISOR,
+	REGISTRATION_FIELD_MENTOR_NAME,
+	REGISTRATION_FIELD_MENTOR_EMAIL,
+	REGISTRATION_FIELD_SEMESTER,
+	REGISTRATION_FIELD_TOTAL_SKS,
+}
+
+var mentorRegistrationFields = []string{
+	REGISTRATION_FIELD_MENTOR_NAME,
+	REGISTRATION_FIELD_MENTOR_EMAIL,
+}
+
+// materialRegistrationFields are the fields the approvals are based on
+var materialRegistrationFields = map[string]bool{
+	REGISTRATION_FIELD_ADVISING_CONFIRMATION: true,
+	REGISTRATION_FIELD_ACADEMIC_ADVISOR:      true,
+	REGISTRATION_FIELD_SEMESTER:              true,
+	REGISTRATION_FIELD_TOTAL_SKS:             true,
+}
+
+// registrationEditRules lists per role and state the fields that may be
+// changed, a state that isn't listed allows no change. Withdrawn
+// registrations are never edited.
+var registrationEditRules = map[string]map[string][]string{
+	"MAHASISWA": {
+		REGISTRATION_EDIT_STATE_DRAFT:     allRegistrationFields,
+		REGISTRATION_EDIT_STATE_SUBMITTED: allRegistrationFields,
+		REGISTRATION_EDIT_STATE_APPROVED:  mentorRegistrationFields,
+	},
+	"DOSEN PEMBIMBING": {
+		REGISTRATION_EDIT_STATE_SUBMITTED: mentorRegistrationFields,
+	},
+	"LO-MBKM": {
+		REGISTRATION_EDIT_STATE_DRAFT:                allRegistrationFields,
+		REGISTRATION_EDIT_STATE_SUBMITTED:            allRegistrationFields,
+		REGISTRATION_EDIT_STATE_APPROVED:             allRegistrationFields,
+		REGISTRATION_EDIT_STATE_WITHDRAWAL_REQUESTED: mentorRegistrationFields,
+	},
+	"ADMIN": {
+		REGISTRATION_EDIT_STATE_DRAFT:                allRegistrationFields,
+		REGISTRATION_EDIT_STATE_SUBMITTED:            allRegistrationFields,
+		REGISTRATION_EDIT_STATE_APPROVED:             allRegistrationFields,
+		REGISTRATION_EDIT_STATE_WITHDRAWAL_REQUESTED: mentorRegistrationFields,
+	},
+}
+
+func registrationEditState(registration entity.Registration) string {
+	switch registration.Status {
+	case entity.REGISTRATION_STATUS_DRAFT, entity.REGISTRATION_STATUS_WITHDRAWAL_REQUESTED, entity.REGISTRATION_STATUS_WITHDRAWN:
+		return registration.Status
+	}
+
+	if registration.AcademicAdvisorValidation == "APPROVED" || registration.LOValidation == "APPROVED" {
+		return REGISTRATION_EDIT_STATE_APPROVED
+	}
+
+	return REGISTRATION_EDIT_STATE_SUBMITTED
+}
+
+// canEditField applies registrationEditRules. When approvals are re-opened on
+// change the student may also change the material fields of an approved
+// registration, it goes back to the reviewers.
+func (s *registrationService) canEditField(role string, state string, field string) bool {
+	for _, allowed := range registrationEditRules[role][state] {
+		if allowed == field {
+			return true
+		}
+	}
+
+	return s.reopenApprovals && role == "MAHASISWA" && state == REGISTRATION_EDIT_STATE_APPROVED && materialRegistrationFields[field]
+}
+
+// patchedRegistrationFields returns the rule fields the request touches
+func patchedRegistrationFields(request dto.PatchRegistrationRequest) []string {
+	var fields []string
+	if request.AdvisingConfirmation.Set {
+		fields = append(fields, REGISTRATION_FIELD_ADVISING_CONFIRMATION)
+	}
+	if request.AcademicAdvisorID.Set || request.AcademicAdvisor.Set || request.AcademicAdvisorEmail.Set {
+		fields = append(fields, REGISTRATION_FIELD_ACADEMIC_ADVISOR)
+	}
+	if request.MentorName.Set {
+		fields = append(fields, REGISTRATION_FIELD_MENTOR_NAME)
+	}
+	if request.MentorEmail.Set {
+		fields = append(fields, REGISTRATION_FIELD_MENTOR_EMAIL)
+	}
+	if request.Semester.Set {
+		fields = append(fields, REGISTRATION_FIELD_SEMESTER)
+	}
+	if request.TotalSKS.Set {
+		fields = append(fields, REGISTRATION_FIELD_TOTAL_SKS)
+	}
+
+	return fields
+}
+
+// patchFromUpdateRequest keeps the PUT endpoint working as before, a zero
+// value there means the field is left alone
+func patchFromUpdateRequest(request dto.UpdateRegistrationDataRequest) dto.PatchRegistrationRequest {
+	patch := dto.PatchRegistrationRequest{LockVersion: request.LockVersion}
+	if request.AdvisingConfirmation {
+		patch.AdvisingConfirmation = dto.Optional[bool]{Set: true, Value: true}
+	}
+	if request.AcademicAdvisorID != "" {
+		patch.AcademicAdvisorID = dto.Optional[string]{Set: true, Value: request.AcademicAdvisorID}
+	}
+	if request.AcademicAdvisor != "" {
+		patch.AcademicAdvisor = dto.Optional[string]{Set: true, Value: request.AcademicAdvisor}
+	}
+	if request.AcademicAdvisorEmail != "" {
+		patch.AcademicAdvisorEmail = dto.Optional[string]{Set: true, Value: request.AcademicAdvisorEmail}
+	}
+	if request.MentorName != "" {
+		patch.MentorName = dto.Optional[string]{Set: true, Value: request.MentorName}
+	}
+	if request.MentorEmail != "" {
+		patch.MentorEmail = dto.Optional[string]{Set: true, Value: request.MentorEmail}
+	}
+	if request.Semester != 0 {
+		patch.Semester = dto.Optional[int]{Set: true, Value: request.Semester}
+	}
+	if request.TotalSKS != 0 {
+		patch.TotalSKS = dto.Optional[int]{Set: true, Value: request.TotalSKS}
+	}
+
+	return patch
+}
+
+// PatchRegistration changes the fields the request carries after checking
+// them against the edit rules. Changes to a submitted registration are kept
+// in its history, and changing a material field of an approved one re-opens
+// the approvals when configured.
+func (s *registrationService) PatchRegistration(ctx context.Context, id string, request dto.PatchRegistrationRequest, token string, tx *gorm.DB) error {
+	access := s.RegistrationsDataAccess(ctx, id, token, tx)
+	if !access {
+		return errors.New("data not found")
+	}
+
+	existingRegistration, err := s.registrationRepository.FindByID(ctx, id, tx)
+	if err != nil {
+		return err
+	}
+
+	err = checkLockVersion(request.LockVersion, "registration", id, existingRegistration.LockVersion)
+	if err != nil {
+		return err
+	}
+
+	userData := s.userManagementService.GetUserData("GET", token)
+	if userData == nil {
+		return errors.New("Unauthorized")
+	}
+	role, _ := userData["role"].(string)
+
+	state := registrationEditState(existingRegistration)
+	for _, field := range patchedRegistrationFields(request) {
+		if !s.canEditField(role, state, field) {
+			return fmt.Errorf("%s can't be changed while the registration is %s", field, strings.ToLower(strings.ReplaceAll(state, "_", " ")))
+		}
+	}
+
+	submitted := existingRegistration.Status != entity.REGISTRATION_STATUS_DRAFT
+	registration := existingRegistration
+
+	if request.AdvisingConfirmation.Set {
+		registration.AdvisingConfirmation = !request.AdvisingConfirmation.Null && request.AdvisingConfirmation.Value
+	}
+
+	var reassignedNote string
+	if request.AcademicAdvisorID.Set || request.AcademicAdvisor.Set || request.AcademicAdvisorEmail.Set {
+		advisor := advisorAssignment{ID: registration.AcademicAdvisorID, Name: registration.AcademicAdvisor, Email: registration.AcademicAdvisorEmail}
+		if request.AcademicAdvisorEmail.Set && !strings.EqualFold(request.AcademicAdvisorEmail.Value, advisor.Email) {
+			// another advisor, the stored id and name belong to the old one
+			advisor = advisorAssignment{Email: request.AcademicAdvisorEmail.Value}
+		}
+		if request.AcademicAdvisorID.Set {
+			advisor.ID = request.AcademicAdvisorID.Value
+		}
+		if request.AcademicAdvisor.Set {
+			advisor.Name = request.AcademicAdvisor.Value
+		}
+
+		advisor, err = s.resolveAdvisor(registration.UserNRP, advisor, submitted, token)
+		if err != nil {
+			return err
+		}
+
+		if !strings.EqualFold(advisor.Email, registration.AcademicAdvisorEmail) {
+			// the decision of the previous advisor doesn't carry over
+			reassignedNote = reassignmentNote(registration.AcademicAdvisorEmail, advisor.Email, "")
+			assignAdvisor(&registration, advisor, true)
+		} else {
+			registration.AcademicAdvisorID = advisor.ID
+			registration.AcademicAdvisor = advisor.Name
+			registration.AcademicAdvisorEmail = advisor.Email
+		}
+	}
+
+	if request.MentorName.Set {
+		registration.MentorName = strings.TrimSpace(request.MentorName.Value)
+		if submitted && registration.MentorName == "" {
+			return errors.New("mentor_name can't be empty once the registration is submitted")
+		}
+	}
+
+	if request.MentorEmail.Set {
+		registration.MentorEmail = strings.TrimSpace(request.MentorEmail.Value)
+		if submitted && registration.MentorEmail == "" {
+			return errors.New("mentor_email can't be empty once the registration is submitted")
+		}
+	}
+
+	if request.Semester.Set {
+		registration.Semester, err = patchedCount(REGISTRATION_FIELD_SEMESTER, request.Semester, submitted)
+		if err != nil {
+			return err
+		}
+	}
+
+	if request.TotalSKS.Set {
+		registration.TotalSKS, err = patchedCount(REGISTRATION_FIELD_TOTAL_SKS, request.TotalSKS, submitted)
+		if err != nil {
+			return err
+		}
+	}
+
+	changed := changedRegistrationFields(existingRegistration, registration)
+	if len(changed) == 0 {
+		return nil
+	}
+
+	var material []string
+	for _, field := range changed {
+		if materialRegistrationFields[field] {
+			material = append(material, field)
+		}
+	}
+
+	reopened := false
+	if state == REGISTRATION_EDIT_STATE_APPROVED && len(material) > 0 && s.reopenApprovals {
+		reopened = reopenApprovals(&registration)
+	}
+
+	if submitted && (registration.Semester != existingRegistration.Semester || registration.TotalSKS != existingRegistration.TotalSKS) {
+		s.checkAcademicRecord(ctx, registration.UserNRP, registration.Semester, registration.TotalSKS, token).applyTo(&registration)
+	}
+
+	err = s.registrationRepository.Update(ctx, id, registration, tx)
+	if err != nil {
+		return err
+	}
+
+	if !submitted {
+		return nil
+	}
+
+	err = s.recordHistory(ctx, registration, entity.REGISTRATION_HISTORY_UPDATED, existingRegistration.Status, "changed "+strings.Join(changed, ", "), userData, tx)
+	if err != nil {
+		return err
+	}
+
+	if reassignedNote != "" {
+		err = s.recordHistory(ctx, registration, entity.REGISTRATION_HISTORY_ADVISOR_REASSIGNED, existingRegistration.Status, reassignedNote, userData, tx)
+		if err != nil {
+			return err
+		}
+	}
+
+	if reopened {
+		err = s.recordHistory(ctx, registration, entity.REGISTRATION_HISTORY_APPROVAL_REOPENED, existingRegistration.Status, "approvals re-opened after changing "+strings.Join(material, ", "), userData, tx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// patchedCount validates a semester or SKS value, null clears it on a draft
+func patchedCount(field string, value dto.Optional[int], submitted bool) (int, error) {
+	if value.Null {
+		if submitted {
+			return 0, fmt.Errorf("%s can't be empty once the registration is submitted", field)
+		}
+		return 0, nil
+	}
+
+	if value.Value <= 0 {
+		return 0, fmt.Errorf("%s must be positive", field)
+	}
+
+	return value.Value, nil
+}
+
+func changedRegistrationFields(before entity.Registration, after entity.Registration) []string {
+	var fields []string
+	if before.AdvisingConfirmation != after.AdvisingConfirmation {
+		fields = append(fields, REGISTRATION_FIELD_ADVISING_CONFIRMATION)
+	}
+	if before.AcademicAdvisorID != after.AcademicAdvisorID || before.AcademicAdvisor != after.AcademicAdvisor || before.AcademicAdvisorEmail != after.AcademicAdvisorEmail {
+		fields = append(fields, REGISTRATION_FIELD_ACADEMIC_ADVISOR)
+	}
+	if before.MentorName != after.MentorName {
+		fields = append(fields, REGISTRATION_FIELD_MENTOR_NAME)
+	}
+	if before.MentorEmail != after.MentorEmail {
+		fields = append(fields, REGISTRATION_FIELD_MENTOR_EMAIL)
+	}
+	if before.Semester != after.Semester {
+		fields = append(fields, REGISTRATION_FIELD_SEMESTER)
+	}
+	if before.TotalSKS != after.TotalSKS {
+		fields = append(fields, REGISTRATION_FIELD_TOTAL_SKS)
+	}
+
+	return fields
+}
+
+// reopenApprovals sends the approvals given so far back to PENDING, it
+// reports whether there was one
+func reopenApprovals(registration *entity.Registration) bool {
+	reopened := false
+	if registration.AcademicAdvisorValidation == "APPROVED" {
+		registration.AcademicAdvisorValidation = "PENDING"
+		reopened = true
+	}
+	if registration.LOValidation == "APPROVED" {
+		registration.LOValidation = "PENDING"
+		reopened = true
+	}
+	registration.ApprovalStatus = false
+
+	return reopened
+}
@@ -0,0 +1,336 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"registration-service/dto"
+	"registration-service/entity"
+	"registration-service/repository"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// the built-in rules always run and can't be configured
+const (
+	ELIGIBILITY_RULE_ACTIVITY_OPEN     = "activity_open"
+	ELIGIBILITY_RULE_NOT_REGISTERED    = "not_registered"
+	ELIGIBILITY_RULE_NO_PERIOD_OVERLAP = "no_period_overlap"
+	ELIGIBILITY_RULE_ACADEMIC_PERIOD   = "academic_period_limit"
+)
+
+// eligibilityApplicant is the student a registration is checked for, Semester
+// and TotalSKS are 0 when they aren't known yet. RegistrationID is set when an
+// existing draft is checked, so the draft doesn't count against itself.
+type eligibilityApplicant struct {
+	NRP            string
+	Semester       int
+	TotalSKS       int
+	RegistrationID string
+}
+
+// eligibilityResult collects the outcome of every rule, the first failure
+// gives the message and the error of the whole check
+type eligibilityResult struct {
+	response dto.RegistrationEligibilityResponse
+	err      error
+}
+
+func (r *eligibilityResult) add(rule string, result string, reason string, err error) {
+	r.response.Rules = append(r.response.Rules, dto.EligibilityRuleResultResponse{
+		Rule:   rule,
+		Result: result,
+		Reason: reason,
+	})
+
+	if result == entity.ELIGIBILITY_RESULT_FAIL && r.err == nil {
+		r.response.Message = reason
+		r.err = err
+	}
+}
+
+// checkEligibility runs the built-in rules and the rules configured for the
+// activity's program type and level, every rule is evaluated so the response
+// explains all of them. It is shared by the student's own check, registration
+// and the LO-MBKM bulk import.
+func (s *registrationService) checkEligibility(ctx context.Context, applicant eligibilityApplicant, activityID string, token string, tx *gorm.DB) (dto.RegistrationEligibilityResponse, error) {
+	activitiesData := s.activityManagementService.GetActivitiesData(map[string]interface{}{
+		"activity_id":     activityID,
+		"program_type_id": "",
+		"level_id":        "",
+		"group_id":        "",
+		"name":            "",
+	}, "POST", token)
+
+	if len(activitiesData) == 0 {
+		return dto.RegistrationEligibilityResponse{
+			Eligible: false,
+			Message:  "Activity not found",
+		}, errors.New("activity not found")
+	}
+	activity := activitiesData[0]
+
+	period, err := parseActivityPeriod(activity)
+	if err != nil {
+		return dto.RegistrationEligibilityResponse{
+			Eligible: false,
+			Message:  "Invalid period data in new activity",
+		}, fmt.Errorf("%s in new activity", err.Error())
+	}
+
+	active, err := s.registrationRepository.FindActiveByNRP(ctx, applicant.NRP, tx)
+	if err != nil {
+		return dto.RegistrationEligibilityResponse{
+			Eligible: false,
+			Message:  "Error checking existing registrations",
+		}, errors.New("error checking existing registrations")
+	}
+
+	var registrations []entity.Registration
+	for _, registration := range active {
+		if registration.ID.String() != applicant.RegistrationID {
+			registrations = append(registrations, registration)
+		}
+	}
+
+	result := eligibilityResult{}
+
+	approvalStatus, _ := activity["approval_status"].(string)
+	if approvalStatus != "APPROVED" {
+		result.add(ELIGIBILITY_RULE_ACTIVITY_OPEN, entity.ELIGIBILITY_RESULT_FAIL, "This activity is not open for registration", errors.New("this activity is not open for registration"))
+	} else {
+		result.add(ELIGIBILITY_RULE_ACTIVITY_OPEN, entity.ELIGIBILITY_RESULT_PASS, "The activity is open for registration", nil)
+	}
+
+	registered := false
+	for _, registration := range registrations {
+		if registration.ActivityID == activityID {
+			registered = true
+			break
+		}
+	}
+	// withdrawn and rejected registrations still block the same activity
+	if !registered {
+		registration, err := s.registrationRepository.FindByActivityIDAndNRP(ctx, activityID, applicant.NRP, tx)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.RegistrationEligibilityResponse{
+				Eligible: false,
+				Message:  "Error checking existing registration",
+			}, errors.New("error getting registration by activity_id and user_nrp")
+		}
+		registered = registration.ActivityID == activityID && registration.ID.String() != applicant.RegistrationID
+	}
+
+	if registered {
+		result.add(ELIGIBILITY_RULE_NOT_REGISTERED, entity.ELIGIBILITY_RESULT_FAIL, "User already registered for this activity", repository.NewConflictError("user already registered"))
+	} else {
+		result.add(ELIGIBILITY_RULE_NOT_REGISTERED, entity.ELIGIBILITY_RESULT_PASS, "No registration for this activity yet", nil)
+	}
+
+	conflicts, err := s.findPeriodConflicts(registrations, activityID, period, token)
+	if err != nil {
+		return dto.RegistrationEligibilityResponse{
+			Eligible: false,
+			Message:  "Error checking existing registrations",
+		}, err
+	}
+
+	if len(conflicts) > 0 {
+		result.response.Conflicts = conflicts
+		result.add(ELIGIBILITY_RULE_NO_PERIOD_OVERLAP, entity.ELIGIBILITY_RESULT_FAIL, conflictsMessage(conflicts), repository.NewConflictError("user already registered for an overlapping activity period"))
+	} else {
+		result.add(ELIGIBILITY_RULE_NO_PERIOD_OVERLAP, entity.ELIGIBILITY_RESULT_PASS, "No overlap with the student's other activities", nil)
+	}
+
+	academicPeriod, err := s.academicPeriodService.FindPeriodForDate(ctx, period.Start, tx)
+	if err != nil {
+		return dto.RegistrationEligibilityResponse{
+			Eligible: false,
+			Message:  "Error checking academic period",
+		}, errors.New("error checking academic period")
+	}
+
+	if academicPeriod == nil {
+		result.add(ELIGIBILITY_RULE_ACADEMIC_PERIOD, entity.ELIGIBILITY_RESULT_WARN, "No academic period covers the start of the activity", nil)
+	} else {
+		academicPeriodResponse := convertToAcademicPeriodResponse(*academicPeriod)
+		result.response.AcademicPeriod = &academicPeriodResponse
+		outcome, reason := academicPeriodLimit(*academicPeriod, registrations, activityID)
+		result.add(ELIGIBILITY_RULE_ACADEMIC_PERIOD, outcome, reason, errors.New(reason))
+	}
+
+	programTypeID, _ := activity["program_type_id"].(string)
+	levelID, _ := activity["level_id"].(string)
+	rules, err := s.eligibilityRuleService.FindRulesForActivity(ctx, programTypeID, levelID, tx)
+	if err != nil {
+		return dto.RegistrationEligibilityResponse{
+			Eligible: false,
+			Message:  "Error loading eligibility rules",
+		}, err
+	}
+
+	input := EligibilityInput{
+		UserNRP:       applicant.NRP,
+		ActivityID:    activityID,
+		ProgramTypeID: programTypeID,
+		LevelID:       levelID,
+		Semester:      applicant.Semester,
+		TotalSKS:      applicant.TotalSKS,
+		Registrations: registrations,
+	}
+	for _, rule := range rules {
+		outcome, reason := EvaluateEligibilityRule(input, rule)
+		result.add(rule.Rule, outcome, reason, errors.New(reason))
+	}
+
+	if result.err != nil {
+		result.response.Eligible = false
+		return result.response, result.err
+	}
+
+	result.response.Eligible = true
+	result.response.Message = "User is eligible to register for this activity"
+	return result.response, nil
+}
+
+// academicPeriodID is the period a checked registration is linked to, empty
+// when no period covers the activity start
+func academicPeriodID(eligibility dto.RegistrationEligibilityResponse) string {
+	if eligibility.AcademicPeriod == nil {
+		return ""
+	}
+
+	return eligibility.AcademicPeriod.ID
+}
+
+// academicPeriodLimit counts the submitted registrations the student holds in
+// the period, like the concurrent program rule drafts don't take a place yet
+func academicPeriodLimit(academicPeriod entity.AcademicPeriod, registrations []entity.Registration, activityID string) (string, string) {
+	name := academicPeriodName(academicPeriod)
+	if academicPeriod.MaxRegistrationsPerStudent == 0 {
+		return entity.ELIGIBILITY_RESULT_PASS, fmt.Sprintf("The activity starts in %s, which has no registration limit", name)
+	}
+
+	held := 0
+	for _, registration := range registrations {
+		if registration.AcademicPeriodID != academicPeriod.ID.String() || registration.ActivityID == activityID || registration.Status == entity.REGISTRATION_STATUS_DRAFT {
+			continue
+		}
+		held++
+	}
+
+	if held >= academicPeriod.MaxRegistrationsPerStudent {
+		return entity.ELIGIBILITY_RESULT_FAIL, fmt.Sprintf("At most %d registrations are allowed in %s, the student has %d", academicPeriod.MaxRegistrationsPerStudent, name, held)
+	}
+
+	return entity.ELIGIBILITY_RESULT_PASS, fmt.Sprintf("%d of at most %d registrations in %s", held, academicPeriod.MaxRegistrationsPerStudent, name)
+}
+
+// activityPeriod is the interval an activity occupies the student, from its
+// start period until months_duration months later
+type activityPeriod struct {
+	Start time.Time
+	End   time.Time
+}
+
+// overlaps treats both ends as included, an activity starting on the day
+// another one ends still conflicts with it
+func (p activityPeriod) overlaps(other activityPeriod) bool {
+	return !p.Start.After(other.End) && !other.Start.After(p.End)
+}
+
+// parseActivityPeriod reads start_period and months_duration from the activity
+// management data, start_period may come as RFC3339 text or as a time
+func parseActivityPeriod(activity map[string]interface{}) (activityPeriod, error) {
+	var start time.Time
+	switch v := activity["start_period"].(type) {
+	case string:
+		parsedTime, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return activityPeriod{}, errors.New("invalid start_period format")
+		}
+		start = parsedTime
+	case time.Time:
+		start = v
+	case nil:
+		return activityPeriod{}, errors.New("start_period not found")
+	default:
+		return activityPeriod{}, errors.New("invalid start_period type")
+	}
+
+	var monthsDuration int
+	switch v := activity["months_duration"].(type) {
+	case int:
+		monthsDuration = v
+	case float64:
+		monthsDuration = int(v)
+	case nil:
+		return activityPeriod{}, errors.New("months_duration not found")
+	default:
+		return activityPeriod{}, errors.New("invalid months_duration type")
+	}
+
+	return activityPeriod{Start: start, End: start.AddDate(0, monthsDuration, 0)}, nil
+}
+
+// findPeriodConflicts compares the period of the activity being registered for
+// with the period of every active registration of the student. Registrations
+// for the same activity are left to the duplicate check.
+func (s *registrationService) findPeriodConflicts(registrations []entity.Registration, activityID string, period activityPeriod, token string) ([]dto.RegistrationConflictResponse, error) {
+	var err error
+	periods := map[string]activityPeriod{}
+	var conflicts []dto.RegistrationConflictResponse
+	for _, registration := range registrations {
+		if registration.ActivityID == activityID {
+			continue
+		}
+
+		existing, ok := periods[registration.ActivityID]
+		if !ok {
+			activitiesData := s.activityManagementService.GetActivitiesData(map[string]interface{}{
+				"activity_id":     registration.ActivityID,
+				"program_type_id": "",
+				"level_id":        "",
+				"group_id":        "",
+				"name":            "",
+			}, "POST", token)
+			if len(activitiesData) == 0 {
+				return nil, fmt.Errorf("activity data of %s not found", registration.ActivityName)
+			}
+
+			existing, err = parseActivityPeriod(activitiesData[0])
+			if err != nil {
+				return nil, fmt.Errorf("%s of %s", err.Error(), registration.ActivityName)
+			}
+			periods[registration.ActivityID] = existing
+		}
+
+		if period.overlaps(existing) {
+			conflicts = append(conflicts, registrationConflict(registration, existing))
+		}
+	}
+
+	return conflicts, nil
+}
+
+func registrationConflict(registration entity.Registration, period activityPeriod) dto.RegistrationConflictResponse {
+	return dto.RegistrationConflictResponse{
+		RegistrationID: registration.ID.String(),
+		ActivityID:     registration.ActivityID,
+		ActivityName:   registration.ActivityName,
+		Status:         registration.Status,
+		StartPeriod:    period.Start,
+		EndPeriod:      period.End,
+	}
+}
+
+func conflictsMessage(conflicts []dto.RegistrationConflictResponse) string {
+	names := make([]string, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		names = append(names, conflict.ActivityName)
+	}
+
+	return "User already registered for an overlapping activity period: " + strings.Join(names, ", ")
+}
@@ -0,0 +1,355 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"registration-service/dto"
+	"registration-service/entity"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
+)
+
+const (
+	EXPORT_FORMAT_CSV  = "csv"
+	EXPORT_FORMAT_XLSX = "xlsx"
+
+	EXPORT_LANG_EN = "en"
+	EXPORT_LANG_ID = "id"
+
+	// the scopes match the JSON listing the export was requested from
+	EXPORT_SCOPE_ALL     = "ALL"
+	EXPORT_SCOPE_ADVISOR = "ADVISOR"
+	EXPORT_SCOPE_LO_MBKM = "LO-MBKM"
+)
+
+const (
+	exportDateTimeFormat  = "2006-01-02 15:04:05"
+	exportFileTimeFormat  = "20060102_150405"
+	exportXLSXContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+	// rows loaded per query while the export is written
+	exportBatchSize = 500
+)
+
+type registrationExportColumn struct {
+	Key     string
+	Headers map[string]string
+	Value   func(registration entity.Registration) interface{}
+}
+
+var registrationExportColumns = []registrationExportColumn{
+	{Key: "id", Headers: map[string]string{EXPORT_LANG_EN: "Registration ID", EXPORT_LANG_ID: "ID Pendaftaran"}, Value: func(r entity.Registration) interface{} { return r.ID.String() }},
+	{Key: "activity_id", Headers: map[string]string{EXPORT_LANG_EN: "Activity ID", EXPORT_LANG_ID: "ID Kegiatan"}, Value: func(r entity.Registration) interface{} { return r.ActivityID }},
+	{Key: "activity_name", Headers: map[string]string{EXPORT_LANG_EN: "Activity", EXPORT_LANG_ID: "Kegiatan"}, Value: func(r entity.Registration) interface{} { return r.ActivityName }},
+	{Key: "user_nrp", Headers: map[string]string{EXPORT_LANG_EN: "NRP", EXPORT_LANG_ID: "NRP"}, Value: func(r entity.Registration) interface{} { return r.UserNRP }},
+	{Key: "user_name", Headers: map[string]string{EXPORT_LANG_EN: "Student Name", EXPORT_LANG_ID: "Nama Mahasiswa"}, Value: func(r entity.Registration) interface{} { return r.UserName }},
+	{Key: "academic_advisor", Headers: map[string]string{EXPORT_LANG_EN: "Academic Advisor", EXPORT_LANG_ID: "Dosen Pembimbing"}, Value: func(r entity.Registration) interface{} { return r.AcademicAdvisor }},
+	{Key: "academic_advisor_email", Headers: map[string]string{EXPORT_LANG_EN: "Academic Advisor Email", EXPORT_LANG_ID: "Email Dosen Pembimbing"}, Value: func(r entity.Registration) interface{} { return r.AcademicAdvisorEmail }},
+	{Key: "mentor_name", Headers: map[string]string{EXPORT_LANG_EN: "Mentor", EXPORT_LANG_ID: "Mentor"}, Value: func(r entity.Registration) interface{} { return r.MentorName }},
+	{Key: "mentor_email", Headers: map[string]string{EXPORT_LANG_EN: "Mentor Email", EXPORT_LANG_ID: "Email Mentor"}, Value: func(r entity.Registration) interface{} { return r.MentorEmail }},
+	{Key: "semester", Headers: map[string]string{EXPORT_LANG_EN: "Semester", EXPORT_LANG_ID: "Semester"}, Value: func(r entity.Registration) interface{} { return r.Semester }},
+	{Key: "total_sks", Headers: map[string]string{EXPORT_LANG_EN: "Total SKS", EXPORT_LANG_ID: "Total SKS"}, Value: func(r entity.Registration) interface{} { return r.TotalSKS }},
+	{Key: "academic_advisor_validation", Headers: map[string]string{EXPORT_LANG_EN: "Advisor Validation", EXPORT_LANG_ID: "Validasi Dosen Pembimbing"}, Value: func(r entity.Registration) interface{} { return r.AcademicAdvisorValidation }},
+	{Key: "lo_validation", Headers: map[string]string{EXPORT_LANG_EN: "LO-MBKM Validation", EXPORT_LANG_ID: "Validasi LO-MBKM"}, Value: func(r entity.Registration) interface{} { return r.LOValidation }},
+	{Key: "approval_status", Headers: map[string]string{EXPORT_LANG_EN: "Approved", EXPORT_LANG_ID: "Disetujui"}, Value: func(r entity.Registration) interface{} { return r.ApprovalStatus }},
+	{Key: "status", Headers: map[string]string{EXPORT_LANG_EN: "Status", EXPORT_LANG_ID: "Status"}, Value: func(r entity.Registration) interface{} { return r.Status }},
+	{Key: "withdrawal_reason", Headers: map[string]string{EXPORT_LANG_EN: "Withdrawal Reason", EXPORT_LANG_ID: "Alasan Pengunduran Diri"}, Value: func(r entity.Registration) interface{} { return r.WithdrawalReason }},
+	{Key: "created_at", Headers: map[string]string{EXPORT_LANG_EN: "Registered At", EXPORT_LANG_ID: "Tanggal Pendaftaran"}, Value: func(r entity.Registration) interface{} { return exportTime(r.CreatedAt) }},
+}
+
+// RegistrationExport is a CSV or XLSX of every registration matching a filter,
+// the rows are loaded in batches while it is written
+type RegistrationExport struct {
+	Name        string
+	ContentType string
+	format      string
+	lang        string
+	columns     []registrationExportColumn
+	ids         []string
+	load        func(ctx context.Context, ids []string) ([]entity.Registration, error)
+}
+
+// ExportRegistrations selects the registrations matching the filter, up to the
+// configured row limit, scoped the same way as the JSON listing of the role.
+// Only the ids are read here, they fix the rows and their order for Write.
+func (s *registrationService) ExportRegistrations(ctx context.Context, scope string, filter dto.FilterRegistrationRequest, request dto.RegistrationExportRequest, token string, tx *gorm.DB) (RegistrationExport, error) {
+	switch scope {
+	case EXPORT_SCOPE_ADVISOR:
+		userEmail := s.ValidateAdvisor(ctx, token, tx)
+		if userEmail == "" {
+			return RegistrationExport{}, errors.New("Unauthorized")
+		}
+
+		filter.AcademicAdvisorEmail = userEmail
+	case EXPORT_SCOPE_ALL, EXPORT_SCOPE_LO_MBKM:
+	default:
+		return RegistrationExport{}, errors.New("invalid export scope")
+	}
+
+	columns, err := registrationExportColumnsByKey(request.Columns)
+	if err != nil {
+		return RegistrationExport{}, err
+	}
+
+	if s.exportMaxRows <= 0 {
+		return RegistrationExport{}, errors.New("registration export is disabled")
+	}
+
+	ids, total, err := s.registrationRepository.IndexIDs(ctx, tx, int(s.exportMaxRows), filter)
+	if err != nil {
+		return RegistrationExport{}, err
+	}
+
+	if total > s.exportMaxRows {
+		return RegistrationExport{}, fmt.Errorf("%d registrations match the filter, the export is limited to %d rows", total, s.exportMaxRows)
+	}
+
+	format := request.Format
+	if format == "" {
+		format = EXPORT_FORMAT_CSV
+	}
+
+	lang := request.Lang
+	if lang == "" {
+		lang = EXPORT_LANG_EN
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if format == EXPORT_FORMAT_XLSX {
+		contentType = exportXLSXContentType
+	}
+
+	return RegistrationExport{
+		Name:        "registrations_" + time.Now().Format(exportFileTimeFormat) + "." + format,
+		ContentType: contentType,
+		format:      format,
+		lang:        lang,
+		columns:     columns,
+		ids:         ids,
+		load: func(ctx context.Context, ids []string) ([]entity.Registration, error) {
+			return s.registrationRepository.FindByIDs(ctx, ids, tx)
+		},
+	}, nil
+}
+
+// registrationExportColumnsByKey keeps the requested order, keys may be sent
+// as repeated parameters or comma separated. No keys selects every column.
+func registrationExportColumnsByKey(keys []string) ([]registrationExportColumn, error) {
+	var columns []registrationExportColumn
+	for _, param := range keys {
+		for _, key := range strings.Split(param, ",") {
+			key = strings.TrimSpace(key)
+			if key == "" {
+				continue
+			}
+
+			column, ok := findRegistrationExportColumn(key)
+			if !ok {
+				return nil, fmt.Errorf("unknown export column %s", key)
+			}
+			columns = append(columns, column)
+		}
+	}
+
+	if len(columns) == 0 {
+		return registrationExportColumns, nil
+	}
+
+	return columns, nil
+}
+
+func findRegistrationExportColumn(key string) (registrationExportColumn, bool) {
+	for _, column := range registrationExportColumns {
+		if column.Key == key {
+			return column, true
+		}
+	}
+
+	return registrationExportColumn{}, false
+}
+
+func (e RegistrationExport) headers() []string {
+	headers := make([]string, 0, len(e.columns))
+	for _, column := range e.columns {
+		header, ok := column.Headers[e.lang]
+		if !ok {
+			header = column.Headers[EXPORT_LANG_EN]
+		}
+		headers = append(headers, header)
+	}
+
+	return headers
+}
+
+// Write streams the export in its format to w
+func (e RegistrationExport) Write(ctx context.Context, w io.Writer) error {
+	if e.format == EXPORT_FORMAT_XLSX {
+		return e.writeXLSX(ctx, w)
+	}
+
+	return e.writeCSV(ctx, w)
+}
+
+// each calls fn for every exported registration, batch by batch
+func (e RegistrationExport) each(ctx context.Context, fn func(registration entity.Registration) error) error {
+	for start := 0; start < len(e.ids); start += exportBatchSize {
+		end := min(start+exportBatchSize, len(e.ids))
+
+		registrations, err := e.load(ctx, e.ids[start:end])
+		if err != nil {
+			return err
+		}
+
+		for _, registration := range registrations {
+			err = fn(registration)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (e RegistrationExport) writeCSV(ctx context.Context, w io.Writer) error {
+	// the byte order mark makes spreadsheet applications read the file as UTF-8
+	_, err := io.WriteString(w, "\ufeff")
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	err = writer.Write(e.headers())
+	if err != nil {
+		return err
+	}
+
+	err = e.each(ctx, func(registration entity.Registration) error {
+		record := make([]string, 0, len(e.columns))
+		for _, column := range e.columns {
+			record = append(record, exportCSVValue(column.Value(registration)))
+		}
+
+		return writer.Write(record)
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func (e RegistrationExport) writeXLSX(ctx context.Context, w io.Writer) error {
+	file := excelize.NewFile()
+	defer file.Close()
+
+	sheet := "Registrations"
+	if e.lang == EXPORT_LANG_ID {
+		sheet = "Pendaftaran"
+	}
+
+	err := file.SetSheetName(file.GetSheetName(0), sheet)
+	if err != nil {
+		return err
+	}
+
+	stream, err := file.NewStreamWriter(sheet)
+	if err != nil {
+		return err
+	}
+
+	headerStyle, err := file.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return err
+	}
+
+	// text cells keep free text from being read as a formula or a number
+	textStyle, err := file.NewStyle(&excelize.Style{NumFmt: 49})
+	if err != nil {
+		return err
+	}
+
+	err = stream.SetPanes(&excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"})
+	if err != nil {
+		return err
+	}
+
+	headers := e.headers()
+	header := make([]interface{}, 0, len(headers))
+	for _, value := range headers {
+		header = append(header, excelize.Cell{StyleID: headerStyle, Value: value})
+	}
+
+	err = stream.SetRow("A1", header)
+	if err != nil {
+		return err
+	}
+
+	rowNumber := 2
+	err = e.each(ctx, func(registration entity.Registration) error {
+		row := make([]interface{}, 0, len(e.columns))
+		for _, column := range e.columns {
+			row = append(row, exportXLSXValue(column.Value(registration), textStyle))
+		}
+
+		cell, err := excelize.CoordinatesToCellName(1, rowNumber)
+		if err != nil {
+			return err
+		}
+		rowNumber++
+
+		return stream.SetRow(cell, row)
+	})
+	if err != nil {
+		return err
+	}
+
+	err = stream.Flush()
+	if err != nil {
+		return err
+	}
+
+	return file.Write(w)
+}
+
+// exportCSVValue prefixes text a spreadsheet would run as a formula with a quote
+func exportCSVValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+			return "'" + v
+		}
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// exportXLSXValue writes text as an explicit string cell, other values keep their type
+func exportXLSXValue(value interface{}, textStyle int) interface{} {
+	if v, ok := value.(string); ok {
+		return excelize.Cell{StyleID: textStyle, Value: v}
+	}
+
+	return value
+}
+
+func exportTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.Format(exportDateTimeFormat)
+}
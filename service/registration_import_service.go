@@ -0,0 +1,487 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"path/filepath"
+	"registration-service/dto"
+	"registration-service/entity"
+	"registration-service/repository"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
+)
+
+const (
+	importColumnNRP          = "nrp"
+	importColumnActivityID   = "activity_id"
+	importColumnAdvisorEmail = "advisor_email"
+	importColumnMentorName   = "mentor_name"
+	importColumnMentorEmail  = "mentor_email"
+	importColumnSemester     = "semester"
+	importColumnTotalSKS     = "total_sks"
+)
+
+// importColumnAliases maps the normalized spreadsheet headers to the columns
+// the import understands
+var importColumnAliases = map[string]string{
+	"nrp":                    importColumnNRP,
+	"user_nrp":               importColumnNRP,
+	"activity_id":            importColumnActivityID,
+	"advisor_email":          importColumnAdvisorEmail,
+	"academic_advisor_email": importColumnAdvisorEmail,
+	"mentor":                 importColumnMentorName,
+	"mentor_name":            importColumnMentorName,
+	"mentor_email":           importColumnMentorEmail,
+	"semester":               importColumnSemester,
+	"sks":                    importColumnTotalSKS,
+	"total_sks":              importColumnTotalSKS,
+}
+
+var importRequiredColumns = []string{
+	importColumnNRP,
+	importColumnActivityID,
+	importColumnAdvisorEmail,
+	importColumnSemester,
+	importColumnTotalSKS,
+}
+
+type registrationImportRow struct {
+	row          int
+	values       map[string]string
+	registration entity.Registration
+}
+
+// ImportRegistrations creates registrations on behalf of students from a CSV
+// or XLSX cohort list. Every row goes through the same eligibility checks as a
+// student registering themself, valid rows are created in one transaction and
+// invalid rows are reported with their reasons. A dry run only reports.
+func (s *registrationService) ImportRegistrations(ctx context.Context, file *multipart.FileHeader, request dto.RegistrationImportRequest, token string, tx *gorm.DB) (dto.RegistrationImportResponse, error) {
+	userData := s.userManagementService.GetUserData("GET", token)
+	if userData == nil {
+		return dto.RegistrationImportResponse{}, errors.New("user data not found")
+	}
+
+	records, err := s.readImportFile(file)
+	if err != nil {
+		return dto.RegistrationImportResponse{}, err
+	}
+
+	rows, err := importRows(records)
+	if err != nil {
+		return dto.RegistrationImportResponse{}, err
+	}
+
+	if len(rows) == 0 {
+		return dto.RegistrationImportResponse{}, errors.New("import file has no rows")
+	}
+
+	if s.importMaxRows > 0 && int64(len(rows)) > s.importMaxRows {
+		return dto.RegistrationImportResponse{}, fmt.Errorf("import file has %d rows, the import is limited to %d rows", len(rows), s.importMaxRows)
+	}
+
+	response := dto.RegistrationImportResponse{
+		DryRun:    request.DryRun,
+		TotalRows: len(rows),
+	}
+
+	students := map[string]map[string]interface{}{}
+	advisors := map[string]map[string]interface{}{}
+	activities := map[string]map[string]interface{}{}
+	seenNRP := map[string]int{}
+
+	var valid []registrationImportRow
+	for _, row := range rows {
+		nrp := row.values[importColumnNRP]
+		activityID := row.values[importColumnActivityID]
+		result := dto.RegistrationImportRowResponse{
+			Row:        row.row,
+			UserNRP:    nrp,
+			ActivityID: activityID,
+		}
+
+		reasons := s.validateImportRow(ctx, &row, students, advisors, activities, token, tx)
+
+		if first, ok := seenNRP[nrp]; ok && nrp != "" {
+			reasons = append(reasons, fmt.Sprintf("student is already listed on row %d", first))
+		} else if nrp != "" {
+			seenNRP[nrp] = row.row
+		}
+
+		if len(reasons) > 0 {
+			result.Reasons = reasons
+			response.InvalidRows++
+		} else {
+			result.Valid = true
+			response.ValidRows++
+			valid = append(valid, row)
+		}
+
+		response.Rows = append(response.Rows, result)
+	}
+
+	if request.DryRun || len(valid) == 0 {
+		return response, nil
+	}
+
+	err = s.registrationRepository.WithTransaction(ctx, func(tx *gorm.DB) error {
+		for _, row := range valid {
+			// the rows were checked before the transaction, check again under
+			// the student lock in case a registration was created meanwhile
+			err := s.registrationRepository.LockNRP(ctx, row.registration.UserNRP, tx)
+			if err != nil {
+				return fmt.Errorf("row %d: %w", row.row, err)
+			}
+
+			eligibility, err := s.checkEligibility(ctx, eligibilityApplicant{NRP: row.registration.UserNRP, Semester: row.registration.Semester, TotalSKS: row.registration.TotalSKS}, row.registration.ActivityID, token, tx)
+			if err != nil {
+				if errors.Is(err, repository.ErrConflict) {
+					return fmt.Errorf("row %d: %w", row.row, err)
+				}
+				return fmt.Errorf("row %d: %s", row.row, eligibility.Message)
+			}
+			row.registration.AcademicPeriodID = academicPeriodID(eligibility)
+
+			_, err = s.registrationRepository.Create(ctx, row.registration, tx)
+			if err != nil {
+				return fmt.Errorf("row %d: %w", row.row, err)
+			}
+
+			err = s.recordHistory(ctx, row.registration, entity.REGISTRATION_HISTORY_IMPORTED, "", "", userData, tx)
+			if err != nil {
+				return fmt.Errorf("row %d: %w", row.row, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return dto.RegistrationImportResponse{}, err
+	}
+
+	response.Created = len(valid)
+
+	created := map[int]string{}
+	for _, row := range valid {
+		created[row.row] = row.registration.ID.String()
+		s.sendImportNotification(row.registration, userData, token)
+	}
+
+	for i := range response.Rows {
+		response.Rows[i].RegistrationID = created[response.Rows[i].Row]
+	}
+
+	return response, nil
+}
+
+// validateImportRow checks a row and fills in the registration it would create
+func (s *registrationService) validateImportRow(ctx context.Context, row *registrationImportRow, students map[string]map[string]interface{}, advisors map[string]map[string]interface{}, activities map[string]map[string]interface{}, token string, tx *gorm.DB) []string {
+	var reasons []string
+	for _, column := range importRequiredColumns {
+		if row.values[column] == "" {
+			reasons = append(reasons, column+" is required")
+		}
+	}
+
+	semester, err := importPositiveInt(row.values[importColumnSemester])
+	if row.values[importColumnSemester] != "" && err != nil {
+		reasons = append(reasons, "semester "+err.Error())
+	}
+
+	totalSKS, err := importPositiveInt(row.values[importColumnTotalSKS])
+	if row.values[importColumnTotalSKS] != "" && err != nil {
+		reasons = append(reasons, "total_sks "+err.Error())
+	}
+
+	nrp := row.values[importColumnNRP]
+	activityID := row.values[importColumnActivityID]
+
+	// the typed in values of an import are checked against the record like a
+	// student's own registration
+	academicRecord := s.checkAcademicRecord(ctx, nrp, semester, totalSKS, token)
+	semester, totalSKS = academicRecord.Semester, academicRecord.TotalSKS
+	advisorEmail := row.values[importColumnAdvisorEmail]
+
+	var student map[string]interface{}
+	if nrp != "" {
+		student = s.importStudent(nrp, students, token)
+		if student == nil {
+			reasons = append(reasons, "student not found")
+		}
+	}
+
+	// the advisor is stored as user management has it, not as typed in the file
+	var assigned advisorAssignment
+	if advisorEmail != "" {
+		advisor := s.importAdvisor(advisorEmail, advisors, token)
+		if advisor == nil {
+			reasons = append(reasons, "academic advisor not found")
+		} else if s.advisorMode != ADVISOR_MODE_OFF {
+			resolved, err := validateAdvisorData(advisorAssignment{Email: advisorEmail}, advisor)
+			if err != nil {
+				reasons = append(reasons, err.Error())
+			}
+			assigned = resolved
+		} else {
+			assigned.ID, _ = advisor["id"].(string)
+			assigned.Name, _ = advisor["name"].(string)
+			assigned.Email, _ = advisor["email"].(string)
+			if assigned.Email == "" {
+				assigned.Email = advisorEmail
+			}
+		}
+	}
+
+	var activity map[string]interface{}
+	var eligibility dto.RegistrationEligibilityResponse
+	if nrp != "" && activityID != "" {
+		eligibility, _ = s.checkEligibility(ctx, eligibilityApplicant{NRP: nrp, Semester: semester, TotalSKS: totalSKS}, activityID, token, tx)
+		if !eligibility.Eligible {
+			reasons = append(reasons, eligibility.Message)
+		} else {
+			activity = s.importActivity(activityID, activities, token)
+			if activity == nil {
+				reasons = append(reasons, "Activity not found")
+			}
+		}
+	}
+
+	if len(reasons) > 0 {
+		return reasons
+	}
+
+	activityName, _ := activity["name"].(string)
+	programTypeID, _ := activity["program_type_id"].(string)
+	userID, _ := student["id"].(string)
+	userName, _ := student["name"].(string)
+
+	row.registration = entity.Registration{
+		ID:                        uuid.New(),
+		ActivityID:                activityID,
+		ActivityName:              activityName,
+		ProgramTypeID:             programTypeID,
+		AcademicPeriodID:          academicPeriodID(eligibility),
+		UserID:                    userID,
+		UserNRP:                   nrp,
+		UserName:                  userName,
+		AdvisingConfirmation:      true,
+		AcademicAdvisorID:         assigned.ID,
+		AcademicAdvisor:           assigned.Name,
+		AcademicAdvisorEmail:      assigned.Email,
+		MentorName:                row.values[importColumnMentorName],
+		MentorEmail:               row.values[importColumnMentorEmail],
+		LOValidation:              "PENDING",
+		AcademicAdvisorValidation: "PENDING",
+		Semester:                  semester,
+		TotalSKS:                  totalSKS,
+		Status:                    entity.REGISTRATION_STATUS_ACTIVE,
+	}
+	academicRecord.applyTo(&row.registration)
+
+	return nil
+}
+
+func (s *registrationService) importStudent(nrp string, cache map[string]map[string]interface{}, token string) map[string]interface{} {
+	if student, ok := cache[nrp]; ok {
+		return student
+	}
+
+	var student map[string]interface{}
+	users := s.userManagementService.GetUserByFilter(map[string]interface{}{
+		"user_nrp": nrp,
+	}, "POST", token)
+	for _, user := range users {
+		if userNRP, _ := user["nrp"].(string); userNRP == nrp {
+			student = user
+			break
+		}
+	}
+
+	cache[nrp] = student
+	return student
+}
+
+func (s *registrationService) importAdvisor(email string, cache map[string]map[string]interface{}, token string) map[string]interface{} {
+	if advisor, ok := cache[email]; ok {
+		return advisor
+	}
+
+	advisor := s.userManagementService.GetDosenDataByEmail(email, "GET", token)
+	cache[email] = advisor
+	return advisor
+}
+
+func (s *registrationService) importActivity(activityID string, cache map[string]map[string]interface{}, token string) map[string]interface{} {
+	if activity, ok := cache[activityID]; ok {
+		return activity
+	}
+
+	var activity map[string]interface{}
+	activitiesData := s.activityManagementService.GetActivitiesData(map[string]interface{}{
+		"activity_id":     activityID,
+		"program_type_id": "",
+		"level_id":        "",
+		"group_id":        "",
+		"name":            "",
+	}, "POST", token)
+	if len(activitiesData) > 0 {
+		activity = activitiesData[0]
+	}
+
+	cache[activityID] = activity
+	return activity
+}
+
+func (s *registrationService) sendImportNotification(registration entity.Registration, userData map[string]interface{}, token string) {
+	message := fmt.Sprintf("%s has been registered for %s by LO-MBKM", registration.UserName, registration.ActivityName)
+	err := s.brokerService.SendNotification(map[string]interface{}{
+		"sender_name":    userData["name"],
+		"sender_email":   userData["email"],
+		"receiver_email": registration.AcademicAdvisorEmail,
+		"type":           "REGISTER",
+		"message":        message,
+	}, "POST", token)
+	if err != nil {
+		log.Printf("Failed to send import notification for registration %s: %v", registration.ID, err)
+	}
+}
+
+// readImportFile returns the cells of a CSV file or of the first sheet of an
+// XLSX file
+func (s *registrationService) readImportFile(file *multipart.FileHeader) ([][]string, error) {
+	if file == nil {
+		return nil, errors.New("import file is required")
+	}
+
+	if s.importMaxSize > 0 && file.Size > s.importMaxSize {
+		return nil, fmt.Errorf("import file exceeds the maximum size of %d bytes", s.importMaxSize)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	switch strings.ToLower(filepath.Ext(file.Filename)) {
+	case ".csv":
+		reader := csv.NewReader(src)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		records, err := reader.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV file: %w", err)
+		}
+		if len(records) > 0 && len(records[0]) > 0 {
+			records[0][0] = strings.TrimPrefix(records[0][0], "\ufeff")
+		}
+		return records, nil
+	case ".xlsx":
+		content, err := io.ReadAll(src)
+		if err != nil {
+			return nil, err
+		}
+
+		workbook, err := excelize.OpenReader(bytes.NewReader(content))
+		if err != nil {
+			return nil, fmt.Errorf("invalid XLSX file: %w", err)
+		}
+		defer workbook.Close()
+
+		sheets := workbook.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, errors.New("import file has no sheets")
+		}
+
+		return workbook.GetRows(sheets[0])
+	default:
+		return nil, errors.New("import file must be a .csv or .xlsx file")
+	}
+}
+
+// importRows maps the records under the header row to import columns, empty
+// lines are skipped and row numbers follow the spreadsheet
+func importRows(records [][]string) ([]registrationImportRow, error) {
+	header := -1
+	for i, record := range records {
+		if !importRecordEmpty(record) {
+			header = i
+			break
+		}
+	}
+
+	if header == -1 {
+		return nil, errors.New("import file is empty")
+	}
+
+	columns := map[int]string{}
+	found := map[string]bool{}
+	for i, name := range records[header] {
+		key := strings.ToLower(strings.TrimSpace(name))
+		key = strings.NewReplacer(" ", "_", "-", "_").Replace(key)
+		if column, ok := importColumnAliases[key]; ok {
+			columns[i] = column
+			found[column] = true
+		}
+	}
+
+	var missing []string
+	for _, column := range importRequiredColumns {
+		if !found[column] {
+			missing = append(missing, column)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("import file is missing the columns %s", strings.Join(missing, ", "))
+	}
+
+	var rows []registrationImportRow
+	for i := header + 1; i < len(records); i++ {
+		if importRecordEmpty(records[i]) {
+			continue
+		}
+
+		values := map[string]string{}
+		for index, column := range columns {
+			if index < len(records[i]) {
+				values[column] = strings.TrimSpace(records[i][index])
+			}
+		}
+
+		rows = append(rows, registrationImportRow{row: i + 1, values: values})
+	}
+
+	return rows, nil
+}
+
+func importRecordEmpty(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+func importPositiveInt(value string) (int, error) {
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, errors.New("must be a number")
+	}
+
+	if number <= 0 {
+		return 0, errors.New("must be greater than zero")
+	}
+
+	return number, nil
+}
@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"log"
 	"mime/multipart"
+	"registration-service/config"
 	"registration-service/dto"
 	"registration-service/entity"
 	"registration-service/helper"
 	"registration-service/repository"
+	"strings"
 	"time"
 
 	storageService "github.com/SIM-MBKM/filestorage/storage"
@@ -17,15 +19,49 @@ import (
 	"gorm.io/gorm"
 )
 
+// ErrConflict matches the errors returned when a registration clashes with an
+// existing one, controllers answer them with 409
+var ErrConflict = repository.ErrConflict
+
+// ErrStaleVersion matches the errors returned when a registration or document
+// changed after the client read it, it matches ErrConflict as well
+var ErrStaleVersion = repository.ErrStaleVersion
+
+type StaleVersionError = repository.StaleVersionError
+
+// checkLockVersion compares the version the client read, sent in If-Match,
+// with the stored one. Without If-Match only the check on update applies.
+func checkLockVersion(expected *int64, entity string, id string, current int64) error {
+	if expected != nil && *expected != current {
+		return repository.NewStaleVersionError(entity, id)
+	}
+
+	return nil
+}
+
 type registrationService struct {
-	registrationRepository      repository.RegistrationRepository
-	documentRepository          repository.DocumentRepository
-	userManagementService       *UserManagementService
-	activityManagementService   *ActivityManagementService
-	fileService                 *FileService
-	matchingManagementService   *MatchingManagementService
-	monitoringManagementService *MonitoringManagementService
-	brokerService               *BrokerService
+	registrationRepository        repository.RegistrationRepository
+	documentRepository            repository.DocumentRepository
+	registrationHistoryRepository repository.RegistrationHistoryRepository
+	documentRequirementService    DocumentRequirementService
+	eligibilityRuleService        EligibilityRuleService
+	academicPeriodService         AcademicPeriodService
+	advisorDelegationRepository   repository.AdvisorDelegationRepository
+	uploadValidator               UploadValidator
+	downloadSigner                *DownloadSigner
+	userManagementService         *UserManagementService
+	activityManagementService     *ActivityManagementService
+	fileService                   *FileService
+	matchingManagementService     *MatchingManagementService
+	monitoringManagementService   *MonitoringManagementService
+	brokerService                 *BrokerService
+	academicRecordClient          AcademicRecordClient
+	academicRecordMode            string
+	advisorMode                   string
+	reopenApprovals               bool
+	exportMaxRows                 int64
+	importMaxRows                 int64
+	importMaxSize                 int64
 }
 
 type RegistrationService interface {
@@ -33,6 +69,7 @@ type RegistrationService interface {
 	FindRegistrationByID(ctx context.Context, id string, token string, tx *gorm.DB) (dto.GetRegistrationResponse, error)
 	CreateRegistration(ctx context.Context, registration dto.CreateRegistrationRequest, file *multipart.FileHeader, geoletter *multipart.FileHeader, tx *gorm.DB, token string) error
 	UpdateRegistration(ctx context.Context, id string, registration dto.UpdateRegistrationDataRequest, token string, tx *gorm.DB) error
+	PatchRegistration(ctx context.Context, id string, request dto.PatchRegistrationRequest, token string, tx *gorm.DB) error
 	DeleteRegistration(ctx context.Context, id string, token string, tx *gorm.DB) error
 	RegistrationsDataAccess(ctx context.Context, id string, token string, tx *gorm.DB) bool
 	FindRegistrationByAdvisor(ctx context.Context, pagReq dto.PaginationRequest, filter dto.FilterRegistrationRequest, token string, tx *gorm.DB) ([]dto.GetRegistrationResponse, dto.PaginationResponse, error)
@@ -47,25 +84,50 @@ type RegistrationService interface {
 	GetStudentRegistrationsWithSyllabuses(ctx context.Context, pagReq dto.PaginationRequest, filter dto.FilterRegistrationRequest, token string, tx *gorm.DB) (dto.StudentSyllabusesResponse, dto.PaginationResponse, error)
 	FindRegistrationsWithMatching(ctx context.Context, pagReq dto.PaginationRequest, filter dto.FilterRegistrationRequest, token string, tx *gorm.DB) (dto.StudentRegistrationsWithMatchingResponse, dto.PaginationResponse, error)
 	CheckRegistrationEligibility(ctx context.Context, activityID string, token string, tx *gorm.DB) (dto.RegistrationEligibilityResponse, error)
-	FindTotalRegistrationByAdvisorEmail(ctx context.Context, token string, tx *gorm.DB) (entity.RegistrationCount, error)
+	FindTotalRegistrationByAdvisorEmail(ctx context.Context, token string, academicPeriodID string, tx *gorm.DB) (entity.RegistrationCount, error)
+	CreateDraftRegistration(ctx context.Context, registration dto.CreateDraftRegistrationRequest, token string, tx *gorm.DB) (dto.GetRegistrationResponse, error)
+	SubmitRegistration(ctx context.Context, id string, token string, tx *gorm.DB) error
+	RequestWithdrawal(ctx context.Context, id string, request dto.WithdrawalRequest, token string, tx *gorm.DB) error
+	AdvisorWithdrawalApproval(ctx context.Context, token string, approval dto.ApprovalRequest, tx *gorm.DB) error
+	LOWithdrawalApproval(ctx context.Context, token string, approval dto.ApprovalRequest, tx *gorm.DB) error
+	FindRegistrationHistory(ctx context.Context, id string, token string, tx *gorm.DB) ([]dto.RegistrationHistoryResponse, error)
+	RegistrationDocumentsArchive(ctx context.Context, id string, token string, tx *gorm.DB) (DocumentArchive, error)
+	ActivityDocumentsArchive(ctx context.Context, activityID string, token string, tx *gorm.DB) (DocumentArchive, error)
+	ExportRegistrations(ctx context.Context, scope string, filter dto.FilterRegistrationRequest, request dto.RegistrationExportRequest, token string, tx *gorm.DB) (RegistrationExport, error)
+	ImportRegistrations(ctx context.Context, file *multipart.FileHeader, request dto.RegistrationImportRequest, token string, tx *gorm.DB) (dto.RegistrationImportResponse, error)
+	ReassignAdvisor(ctx context.Context, request dto.AdvisorReassignmentRequest, token string, tx *gorm.DB) (dto.AdvisorReassignmentResponse, error)
 	handlePostApprovalTasks(ctx context.Context, registration entity.Registration, token string, status string)
 	sendApprovalNotification(ctx context.Context, registration entity.Registration, token string, status string)
 }
 
-func NewRegistrationService(registrationRepository repository.RegistrationRepository, documentRepository repository.DocumentRepository, secretKey string, userManagementbaseURI string, activityManagementbaseURI string, matchingManagementbaseURI string, monitoringManagementbaseURI string, brokerbaseURI string, asyncURIs []string, config *storageService.Config, tokenManager *storageService.CacheTokenManager) RegistrationService {
+func NewRegistrationService(registrationRepository repository.RegistrationRepository, documentRepository repository.DocumentRepository, registrationHistoryRepository repository.RegistrationHistoryRepository, documentRequirementRepository repository.DocumentRequirementRepository, eligibilityRuleRepository repository.EligibilityRuleRepository, academicPeriodRepository repository.AcademicPeriodRepository, advisorDelegationRepository repository.AdvisorDelegationRepository, uploadConfig config.UploadConfig, downloadConfig config.DownloadConfig, storageConfig config.StorageConfig, exportConfig config.ExportConfig, importConfig config.ImportConfig, academicRecordConfig config.AcademicRecordConfig, advisorConfig config.AdvisorConfig, registrationEditConfig config.RegistrationEditConfig, secretKey string, userManagementbaseURI string, activityManagementbaseURI string, matchingManagementbaseURI string, monitoringManagementbaseURI string, brokerbaseURI string, asyncURIs []string, config *storageService.Config, tokenManager *storageService.CacheTokenManager) RegistrationService {
 	return &registrationService{
-		registrationRepository:      registrationRepository,
-		documentRepository:          documentRepository,
-		userManagementService:       NewUserManagementService(userManagementbaseURI, asyncURIs),
-		activityManagementService:   NewActivityManagementService(activityManagementbaseURI, asyncURIs),
-		matchingManagementService:   NewMatchingManagementService(matchingManagementbaseURI, asyncURIs),
-		monitoringManagementService: NewMonitoringManagementService(monitoringManagementbaseURI, asyncURIs),
-		fileService:                 NewFileService(config, tokenManager),
-		brokerService:               NewBrokerService(brokerbaseURI, asyncURIs),
+		registrationRepository:        registrationRepository,
+		documentRepository:            documentRepository,
+		registrationHistoryRepository: registrationHistoryRepository,
+		documentRequirementService:    NewDocumentRequirementService(documentRequirementRepository),
+		eligibilityRuleService:        NewEligibilityRuleService(eligibilityRuleRepository),
+		academicPeriodService:         NewAcademicPeriodService(academicPeriodRepository),
+		advisorDelegationRepository:   advisorDelegationRepository,
+		uploadValidator:               NewUploadValidator(uploadConfig),
+		downloadSigner:                NewDownloadSigner(downloadConfig.SigningKey, downloadConfig.LinkTTLSeconds, downloadConfig.PublicBaseURL),
+		userManagementService:         NewUserManagementService(userManagementbaseURI, asyncURIs),
+		activityManagementService:     NewActivityManagementService(activityManagementbaseURI, asyncURIs),
+		matchingManagementService:     NewMatchingManagementService(matchingManagementbaseURI, asyncURIs),
+		monitoringManagementService:   NewMonitoringManagementService(monitoringManagementbaseURI, asyncURIs),
+		fileService:                   NewFileService(storageConfig, config, tokenManager),
+		brokerService:                 NewBrokerService(brokerbaseURI, asyncURIs),
+		academicRecordClient:          NewAcademicRecordClient(academicRecordConfig),
+		academicRecordMode:            academicRecordMode(academicRecordConfig),
+		advisorMode:                   advisorMode(advisorConfig),
+		reopenApprovals:               registrationEditConfig.ReopenApprovals,
+		exportMaxRows:                 exportConfig.MaxRows,
+		importMaxRows:                 importConfig.MaxRows,
+		importMaxSize:                 importConfig.MaxSizeBytes,
 	}
 }
 
-func (s *registrationService) FindTotalRegistrationByAdvisorEmail(ctx context.Context, token string, tx *gorm.DB) (entity.RegistrationCount, error) {
+func (s *registrationService) FindTotalRegistrationByAdvisorEmail(ctx context.Context, token string, academicPeriodID string, tx *gorm.DB) (entity.RegistrationCount, error) {
 
 	userData := s.userManagementService.GetUserData("GET", token)
 
@@ -75,7 +137,7 @@ func (s *registrationService) FindTotalRegistrationByAdvisorEmail(ctx context.Co
 		return entity.RegistrationCount{}, errors.New("email is not a string")
 	}
 
-	registratoinCounter, err := s.registrationRepository.FindTotalRegistrationByAdvisorEmail(ctx, emailString, tx)
+	registratoinCounter, err := s.registrationRepository.FindTotalRegistrationByAdvisorEmail(ctx, emailString, academicPeriodID, tx)
 
 	if err != nil {
 		return entity.RegistrationCount{}, err
@@ -236,6 +298,19 @@ func (s *registrationService) LORegistrationApproval(ctx context.Context, token
 			return err
 		}
 
+		if registration.Status == entity.REGISTRATION_STATUS_WITHDRAWN {
+			return errors.New("registration has been withdrawn")
+		}
+
+		if registration.Status == entity.REGISTRATION_STATUS_DRAFT {
+			return errors.New("registration has not been submitted")
+		}
+
+		err = checkLockVersion(approval.LockVersion, "registration", id, registration.LockVersion)
+		if err != nil {
+			return err
+		}
+
 		// Validation logic (same as before)
 		if registration.LOValidation == "APPROVED" && approval.Status == "APPROVED" {
 			return errors.New("Registration already approved")
@@ -244,6 +319,13 @@ func (s *registrationService) LORegistrationApproval(ctx context.Context, token
 			return errors.New("Registration already rejected")
 		}
 
+		if approval.Status == "APPROVED" {
+			err = s.requireCompleteDocuments(ctx, registration, tx)
+			if err != nil {
+				return err
+			}
+		}
+
 		// Update database first (fast operation)
 		if approval.Status == "APPROVED" {
 			registration.LOValidation = "APPROVED"
@@ -342,6 +424,11 @@ func (s *registrationService) AdvisorRegistrationApproval(ctx context.Context, t
 		return errors.New("Unauthorized")
 	}
 
+	delegations, err := s.delegatedAdvisors(ctx, userEmail, tx)
+	if err != nil {
+		return err
+	}
+
 	for _, id := range approval.ID {
 		registration, err := s.registrationRepository.FindByID(ctx, id, tx)
 
@@ -350,11 +437,25 @@ func (s *registrationService) AdvisorRegistrationApproval(ctx context.Context, t
 			return err
 		}
 
-		if registration.AcademicAdvisorEmail != userEmail {
+		delegation, delegated, allowed := actingFor(registration, userEmail, delegations)
+		if !allowed {
 			log.Println("UNAUTHORIZED")
 			return errors.New("Unauthorized")
 		}
 
+		err = checkLockVersion(approval.LockVersion, "registration", id, registration.LockVersion)
+		if err != nil {
+			return err
+		}
+
+		if registration.Status == entity.REGISTRATION_STATUS_WITHDRAWN {
+			return errors.New("registration has been withdrawn")
+		}
+
+		if registration.Status == entity.REGISTRATION_STATUS_DRAFT {
+			return errors.New("registration has not been submitted")
+		}
+
 		if registration.AcademicAdvisorValidation == "APPROVED" && approval.Status == "APPROVED" {
 			return errors.New("Registration already approved")
 		}
@@ -363,6 +464,13 @@ func (s *registrationService) AdvisorRegistrationApproval(ctx context.Context, t
 			return errors.New("Registration already rejected")
 		}
 
+		if approval.Status == "APPROVED" {
+			err = s.requireCompleteDocuments(ctx, registration, tx)
+			if err != nil {
+				return err
+			}
+		}
+
 		if approval.Status == "APPROVED" {
 			registration.AcademicAdvisorValidation = "APPROVED"
 			if registration.LOValidation == "APPROVED" {
@@ -395,6 +503,15 @@ func (s *registrationService) AdvisorRegistrationApproval(ctx context.Context, t
 		if err != nil {
 			return err
 		}
+
+		if delegated {
+			userData := s.userManagementService.GetUserData("GET", token)
+			err = s.recordHistory(ctx, registration, entity.REGISTRATION_HISTORY_DELEGATED_APPROVAL, registration.Status, delegationNote(statusText(approval.Status), delegation), userData, tx)
+			if err != nil {
+				return err
+			}
+		}
+
 		mahasiswaData := s.userManagementService.GetUserByFilter(map[string]interface{}{
 			"user_nrp": registration.UserNRP,
 		}, "POST", token)
@@ -435,16 +552,16 @@ func (s *registrationService) FindRegistrationByStudent(ctx context.Context, pag
 	}
 
 	filter.UserNRP = userNRP
+	filter.IncludeDraft = true
 
 	// filter user data
-	registrations, total, err := s.registrationRepository.Index(ctx, tx, pagReq, filter)
+	registrations, metaData, err := s.listRegistrations(ctx, pagReq, filter, tx)
 	if err != nil {
 		return []dto.GetRegistrationResponse{}, dto.PaginationResponse{}, err
 	}
 
-	metaData := helper.MetaDataPagination(total, pagReq)
-
 	var response []dto.GetRegistrationResponse
+	completeness := s.pageCompleteness(ctx, registrations, tx)
 	for _, registration := range registrations {
 		equivalents, err := s.matchingManagementService.GetEquivalentsByRegistrationID(registration.ID.String(), "GET", token)
 		if err != nil {
@@ -454,6 +571,7 @@ func (s *registrationService) FindRegistrationByStudent(ctx context.Context, pag
 			ID:                        registration.ID.String(),
 			ActivityID:                registration.ActivityID,
 			ActivityName:              registration.ActivityName,
+			AcademicPeriodID:          registration.AcademicPeriodID,
 			UserID:                    registration.UserID,
 			UserNRP:                   registration.UserNRP,
 			UserName:                  registration.UserName,
@@ -467,7 +585,12 @@ func (s *registrationService) FindRegistrationByStudent(ctx context.Context, pag
 			Semester:                  registration.Semester,
 			TotalSKS:                  registration.TotalSKS,
 			ApprovalStatus:            registration.ApprovalStatus,
+			Status:                    registration.Status,
+			WithdrawalReason:          registration.WithdrawalReason,
+			LockVersion:               registration.LockVersion,
 			Documents:                 convertToDocumentResponse(registration.Document),
+			DocumentCompleteness:      completeness[registration.ID.String()],
+			AcademicRecord:            academicRecordResponse(registration),
 			Equivalents:               equivalents,
 		})
 	}
@@ -481,17 +604,30 @@ func (s *registrationService) FindRegistrationByAdvisor(ctx context.Context, pag
 		return []dto.GetRegistrationResponse{}, dto.PaginationResponse{}, errors.New("Unauthorized")
 	}
 
-	filter.AcademicAdvisorEmail = userEmail
+	delegations, err := s.delegatedAdvisors(ctx, userEmail, tx)
+	if err != nil {
+		return []dto.GetRegistrationResponse{}, dto.PaginationResponse{}, err
+	}
+
+	// a delegate also sees the registrations of the advisors they stand in for
+	if len(delegations) > 0 {
+		filter.AcademicAdvisorEmail = ""
+		filter.AcademicAdvisorEmails = []string{userEmail}
+		for _, delegation := range delegations {
+			filter.AcademicAdvisorEmails = append(filter.AcademicAdvisorEmails, delegation.AdvisorEmail)
+		}
+	} else {
+		filter.AcademicAdvisorEmail = userEmail
+	}
 
 	// filter user data
-	registrations, total, err := s.registrationRepository.Index(ctx, tx, pagReq, filter)
+	registrations, metaData, err := s.listRegistrations(ctx, pagReq, filter, tx)
 	if err != nil {
 		return []dto.GetRegistrationResponse{}, dto.PaginationResponse{}, err
 	}
 
-	metaData := helper.MetaDataPagination(total, pagReq)
-
 	var response []dto.GetRegistrationResponse
+	completeness := s.pageCompleteness(ctx, registrations, tx)
 	for _, registration := range registrations {
 		// get equivalent data
 		equivalents, err := s.matchingManagementService.GetEquivalentsByRegistrationID(registration.ID.String(), "GET", token)
@@ -509,6 +645,7 @@ func (s *registrationService) FindRegistrationByAdvisor(ctx context.Context, pag
 			ID:                        registration.ID.String(),
 			ActivityID:                registration.ActivityID,
 			ActivityName:              registration.ActivityName,
+			AcademicPeriodID:          registration.AcademicPeriodID,
 			UserID:                    registration.UserID,
 			UserNRP:                   registration.UserNRP,
 			AdvisingConfirmation:      registration.AdvisingConfirmation,
@@ -521,9 +658,14 @@ func (s *registrationService) FindRegistrationByAdvisor(ctx context.Context, pag
 			Semester:                  registration.Semester,
 			TotalSKS:                  registration.TotalSKS,
 			ApprovalStatus:            registration.ApprovalStatus,
+			Status:                    registration.Status,
+			WithdrawalReason:          registration.WithdrawalReason,
+			LockVersion:               registration.LockVersion,
 			Equivalents:               equivalents,
 			Matching:                  matching,
 			Documents:                 convertToDocumentResponse(registration.Document),
+			DocumentCompleteness:      completeness[registration.ID.String()],
+			AcademicRecord:            academicRecordResponse(registration),
 		})
 	}
 
@@ -532,14 +674,13 @@ func (s *registrationService) FindRegistrationByAdvisor(ctx context.Context, pag
 
 func (s *registrationService) FindRegistrationByLOMBKM(ctx context.Context, pagReq dto.PaginationRequest, filter dto.FilterRegistrationRequest, token string, tx *gorm.DB) ([]dto.GetRegistrationResponse, dto.PaginationResponse, error) {
 	// filter user data
-	registrations, total, err := s.registrationRepository.Index(ctx, tx, pagReq, filter)
+	registrations, metaData, err := s.listRegistrations(ctx, pagReq, filter, tx)
 	if err != nil {
 		return []dto.GetRegistrationResponse{}, dto.PaginationResponse{}, err
 	}
 
-	metaData := helper.MetaDataPagination(total, pagReq)
-
 	var response []dto.GetRegistrationResponse
+	completeness := s.pageCompleteness(ctx, registrations, tx)
 	for _, registration := range registrations {
 		// get equivalent data
 		equivalents, err := s.matchingManagementService.GetEquivalentsByRegistrationID(registration.ID.String(), "GET", token)
@@ -557,6 +698,7 @@ func (s *registrationService) FindRegistrationByLOMBKM(ctx context.Context, pagR
 			ID:                        registration.ID.String(),
 			ActivityID:                registration.ActivityID,
 			ActivityName:              registration.ActivityName,
+			AcademicPeriodID:          registration.AcademicPeriodID,
 			UserID:                    registration.UserID,
 			UserNRP:                   registration.UserNRP,
 			AdvisingConfirmation:      registration.AdvisingConfirmation,
@@ -569,9 +711,14 @@ func (s *registrationService) FindRegistrationByLOMBKM(ctx context.Context, pagR
 			Semester:                  registration.Semester,
 			TotalSKS:                  registration.TotalSKS,
 			ApprovalStatus:            registration.ApprovalStatus,
+			Status:                    registration.Status,
+			WithdrawalReason:          registration.WithdrawalReason,
+			LockVersion:               registration.LockVersion,
 			Equivalents:               equivalents,
 			Matching:                  matching,
 			Documents:                 convertToDocumentResponse(registration.Document),
+			DocumentCompleteness:      completeness[registration.ID.String()],
+			AcademicRecord:            academicRecordResponse(registration),
 		})
 	}
 
@@ -706,9 +853,11 @@ func (s *registrationService) RegistrationsDataAccess(ctx context.Context, id st
 			state = true
 		}
 	} else if userRole == "DOSEN PEMBIMBING" {
-		if registration.AcademicAdvisorEmail == userEmail {
-			state = true
+		delegations, err := s.delegatedAdvisors(ctx, userEmail, tx)
+		if err != nil {
+			return false
 		}
+		_, _, state = actingFor(registration, userEmail, delegations)
 	} else if userRole == "ADMIN" || userRole == "LO-MBKM" {
 		state = true
 	}
@@ -717,19 +866,19 @@ func (s *registrationService) RegistrationsDataAccess(ctx context.Context, id st
 }
 
 func (s *registrationService) FindAllRegistrations(ctx context.Context, pagReq dto.PaginationRequest, filter dto.FilterRegistrationRequest, tx *gorm.DB, token string) ([]dto.GetRegistrationResponse, dto.PaginationResponse, error) {
-	registrations, total, err := s.registrationRepository.Index(ctx, tx, pagReq, filter)
+	registrations, metaData, err := s.listRegistrations(ctx, pagReq, filter, tx)
 	if err != nil {
 		return nil, dto.PaginationResponse{}, err
 	}
 
-	metaData := helper.MetaDataPagination(total, pagReq)
-
 	var response []dto.GetRegistrationResponse
+	completeness := s.pageCompleteness(ctx, registrations, tx)
 	for _, registration := range registrations {
 		response = append(response, dto.GetRegistrationResponse{
 			ID:                        registration.ID.String(),
 			ActivityID:                registration.ActivityID,
 			ActivityName:              registration.ActivityName,
+			AcademicPeriodID:          registration.AcademicPeriodID,
 			UserID:                    registration.UserID,
 			UserNRP:                   registration.UserNRP,
 			AdvisingConfirmation:      registration.AdvisingConfirmation,
@@ -742,7 +891,12 @@ func (s *registrationService) FindAllRegistrations(ctx context.Context, pagReq d
 			Semester:                  registration.Semester,
 			TotalSKS:                  registration.TotalSKS,
 			ApprovalStatus:            registration.ApprovalStatus,
+			Status:                    registration.Status,
+			WithdrawalReason:          registration.WithdrawalReason,
+			LockVersion:               registration.LockVersion,
 			Documents:                 convertToDocumentResponse(registration.Document),
+			DocumentCompleteness:      completeness[registration.ID.String()],
+			AcademicRecord:            academicRecordResponse(registration),
 		})
 	}
 
@@ -770,6 +924,7 @@ func (s *registrationService) FindRegistrationByID(ctx context.Context, id strin
 		ID:                        registration.ID.String(),
 		ActivityID:                registration.ActivityID,
 		ActivityName:              registration.ActivityName,
+		AcademicPeriodID:          registration.AcademicPeriodID,
 		UserID:                    registration.UserID,
 		UserNRP:                   registration.UserNRP,
 		UserName:                  registration.UserName,
@@ -783,7 +938,12 @@ func (s *registrationService) FindRegistrationByID(ctx context.Context, id strin
 		Semester:                  registration.Semester,
 		TotalSKS:                  registration.TotalSKS,
 		ApprovalStatus:            registration.ApprovalStatus,
+		Status:                    registration.Status,
+		WithdrawalReason:          registration.WithdrawalReason,
+		LockVersion:               registration.LockVersion,
 		Documents:                 convertToDocumentResponse(registration.Document),
+		DocumentCompleteness:      s.documentCompleteness(ctx, registration, tx),
+		AcademicRecord:            academicRecordResponse(registration),
 		Equivalents:               equivalents,
 	}
 
@@ -794,7 +954,6 @@ func (s *registrationService) CreateRegistration(ctx context.Context, registrati
 	var registrationEntity entity.Registration
 	var activitiesData []map[string]interface{}
 	// var usersData []map[string]interface{}
-	var activitiesDataOld []map[string]interface{}
 	// get user data
 	userData := s.userManagementService.GetUserData("GET", token)
 	if userData == nil {
@@ -841,109 +1000,7 @@ func (s *registrationService) CreateRegistration(ctx context.Context, registrati
 		return errors.New("Activity not found") // Default value if key doesn't exist or is nil
 	}
 
-	// get registration by activity_id and user_nrp
-	registrationByActivityIDAndNRP, err := s.registrationRepository.FindByActivityIDAndNRP(ctx, registration.ActivityID, userData["nrp"].(string), tx)
-	if err != nil {
-		if err.Error() != "record not found" {
-			return errors.New("error getting registration by activity_id and user_nrp")
-		}
-	}
-
-	if registrationByActivityIDAndNRP.ActivityID == registration.ActivityID {
-		return errors.New("user already registered")
-	}
-
-	// get registration by user nrp and check the academic year (2024/2025)
-	// because user can only register in one semester of academic year (2024/2025)
-	// use
-	registrationsByNRP, err := s.registrationRepository.FindByNRP(ctx, userData["nrp"].(string), tx)
-
-	if registrationsByNRP.ActivityID != "00000000-0000-0000-0000-000000000000" || (err != nil && err.Error() == "record not found") {
-		if registrationsByNRP.ActivityID == registration.ActivityID {
-			return errors.New("user already registered")
-		} else if registrationsByNRP.ActivityID != registration.ActivityID && registrationsByNRP.ActivityID != "" {
-			log.Println("REGISTRATION EXISTS BUT DIFFERENT ACTIVITY ID", registrationsByNRP.ActivityID, registration.ActivityID)
-			// get activity data by registrationsByNRP.ActivityID
-			if registrationsByNRP.ActivityID != "" {
-				activitiesDataOld = s.activityManagementService.GetActivitiesData(map[string]interface{}{
-					"activity_id":     registrationsByNRP.ActivityID,
-					"program_type_id": "",
-					"level_id":        "",
-					"group_id":        "",
-					"name":            "",
-				}, "POST", token)
-				log.Println("ACTIVITIES DATA OLD", activitiesDataOld)
-			}
-			// Check if activitiesDataOld has valid data
-			if len(activitiesDataOld) == 0 {
-				return errors.New("old activity data not found")
-			}
-
-			// Safely parse the old activity start date and duration
-			var activityOldStartDate time.Time
-			var activityOldMonthsDuration int
-
-			// Handle start_date - could be string or another format
-			if startDateVal, ok := activitiesDataOld[0]["start_period"]; ok && startDateVal != nil {
-				// Try to parse as RFC3339 if it's a string
-				if startDateStr, ok := startDateVal.(string); ok {
-					parsedTime, err := time.Parse(time.RFC3339, startDateStr)
-					if err != nil {
-						return errors.New("invalid start_period format in old activity")
-					}
-					activityOldStartDate = parsedTime
-				} else if startDate, ok := startDateVal.(time.Time); ok {
-					// It's already a time.Time
-					activityOldStartDate = startDate
-				} else {
-					return errors.New("invalid start_period type in old activity")
-				}
-			} else {
-				return errors.New("start_period not found in old activity")
-			}
-
-			// Handle months_duration - could be float64 or int
-			if durationVal, ok := activitiesDataOld[0]["months_duration"]; ok && durationVal != nil {
-				switch v := durationVal.(type) {
-				case int:
-					activityOldMonthsDuration = v
-				case float64:
-					activityOldMonthsDuration = int(v)
-				default:
-					return errors.New("invalid months_duration type in old activity")
-				}
-			} else {
-				return errors.New("months_duration not found in old activity")
-			}
-
-			activityOldEndDate := activityOldStartDate.AddDate(0, activityOldMonthsDuration, 0)
-
-			// Similarly handle start_date for the new activity
-			var activityNewStartDate time.Time
-			if startDateVal, ok := activitiesData[0]["start_period"]; ok && startDateVal != nil {
-				// Try to parse as RFC3339 if it's a string
-				if startDateStr, ok := startDateVal.(string); ok {
-					parsedTime, err := time.Parse(time.RFC3339, startDateStr)
-					if err != nil {
-						return errors.New("invalid start_period format in new activity")
-					}
-					activityNewStartDate = parsedTime
-				} else if startDate, ok := startDateVal.(time.Time); ok {
-					// It's already a time.Time
-					activityNewStartDate = startDate
-				} else {
-					return errors.New("invalid start_period type in new activity")
-				}
-			} else {
-				return errors.New("start_period not found in new activity")
-			}
-
-			// if activity new start date is after activity old end date then user can register
-			if !activityNewStartDate.After(activityOldEndDate) {
-				return errors.New("user already registered for an overlapping activity period")
-			}
-		}
-	}
+	programTypeID, _ := activitiesData[0]["program_type_id"].(string)
 
 	var userID string
 	if id, ok := userData["id"]; ok && id != nil {
@@ -975,78 +1032,147 @@ func (s *registrationService) CreateRegistration(ctx context.Context, registrati
 		return errors.New("User not found") // Default value if key doesn't exist or is nil
 	}
 
-	// upload file
-	result, err := s.fileService.storage.GcsUpload(file, "sim_mbkm", "", "")
-	if err != nil {
-		return errors.New("failed to upload file")
+	// validate both letters before anything is sent to the storage. A
+	// checklist without the letters still takes them, without upload rules.
+	scope := entity.Registration{ActivityID: registration.ActivityID, ProgramTypeID: programTypeID}
+	letterRequirement, err := s.documentRequirementService.ValidateDocumentType(ctx, scope, entity.DOCUMENT_TYPE_ACCEPTANCE_LETTER, tx)
+	if errors.Is(err, errDocumentTypeNotListed) {
+		letterRequirement = entity.DocumentRequirement{DocumentType: entity.DOCUMENT_TYPE_ACCEPTANCE_LETTER}
+	} else if err != nil {
+		return err
 	}
-
-	geoletterResult, err := s.fileService.storage.GcsUpload(geoletter, "sim_mbkm", "", "")
+	letterUpload, err := s.uploadValidator.Validate(ctx, letterRequirement, file)
 	if err != nil {
-		return errors.New("failed to upload file")
+		return err
 	}
 
-	loValidation := "PENDING"
-	academicAdvisorValidation := "PENDING"
-
-	registrationEntity = entity.Registration{
-		ID:                        uuid.New(),
-		ActivityID:                registration.ActivityID,
-		ActivityName:              activityName,
-		UserID:                    userID,
-		UserNRP:                   userNRP,
-		UserName:                  userName,
-		AdvisingConfirmation:      registration.AdvisingConfirmation,
-		AcademicAdvisorID:         registration.AcademicAdvisorID,
-		AcademicAdvisor:           registration.AcademicAdvisor,
-		AcademicAdvisorEmail:      registration.AcademicAdvisorEmail,
-		MentorName:                registration.MentorName,
-		MentorEmail:               registration.MentorEmail,
-		LOValidation:              loValidation,
-		AcademicAdvisorValidation: academicAdvisorValidation,
-		Semester:                  registration.Semester,
-		TotalSKS:                  registration.TotalSKS,
+	geoletterRequirement, err := s.documentRequirementService.ValidateDocumentType(ctx, scope, entity.DOCUMENT_TYPE_GEOLETTER, tx)
+	if errors.Is(err, errDocumentTypeNotListed) {
+		geoletterRequirement = entity.DocumentRequirement{DocumentType: entity.DOCUMENT_TYPE_GEOLETTER}
+	} else if err != nil {
+		return err
 	}
-
-	_, err = s.registrationRepository.Create(ctx, registrationEntity, tx)
+	geoletterUpload, err := s.uploadValidator.Validate(ctx, geoletterRequirement, geoletter)
 	if err != nil {
 		return err
 	}
 
-	// Create document entity
-	documentEntity := entity.Document{
-		ID:             uuid.New(),
-		RegistrationID: registrationEntity.ID.String(),
-		Name:           file.Filename,
-		FileStorageID:  result.FileID,
-		DocumentType:   "Acceptence Letter",
+	advisor, err := s.resolveAdvisor(userNRP, advisorAssignment{ID: registration.AcademicAdvisorID, Name: registration.AcademicAdvisor, Email: registration.AcademicAdvisorEmail}, true, token)
+	if err != nil {
+		return err
 	}
 
-	_, err = s.documentRepository.Create(ctx, documentEntity, tx)
+	// in overwrite mode the eligibility rules see the record values
+	academicRecord := s.checkAcademicRecord(ctx, userNRP, registration.Semester, registration.TotalSKS, token)
+
+	// the files go up before the lock is taken so a slow storage doesn't hold
+	// it, they are removed again when the registration is not created
+	fileID, err := s.fileService.storage.Upload(ctx, file)
 	if err != nil {
-		return err
+		return errors.New("failed to upload file")
 	}
 
-	geoletterEntity := entity.Document{
-		ID:             uuid.New(),
-		RegistrationID: registrationEntity.ID.String(),
-		Name:           geoletter.Filename,
-		FileStorageID:  geoletterResult.FileID,
-		DocumentType:   "Geoletter",
+	geoletterFileID, err := s.fileService.storage.Upload(ctx, geoletter)
+	if err != nil {
+		s.removeUploadedFiles(ctx, fileID)
+		return errors.New("failed to upload file")
 	}
 
-	_, err = s.documentRepository.Create(ctx, geoletterEntity, tx)
+	// the checks and the insert run under a lock on the student, two quick
+	// submits would otherwise both pass the checks. The unique index on
+	// activity and NRP stays the last line of defence.
+	var documentEntity, geoletterEntity entity.Document
+	err = s.registrationRepository.WithTransaction(ctx, func(tx *gorm.DB) error {
+		err := s.registrationRepository.LockNRP(ctx, userNRP, tx)
+		if err != nil {
+			return err
+		}
+
+		// the same rules the eligibility check reports, with the submitted semester and SKS
+		eligibility, err := s.checkEligibility(ctx, eligibilityApplicant{NRP: userNRP, Semester: academicRecord.Semester, TotalSKS: academicRecord.TotalSKS}, registration.ActivityID, token, tx)
+		if err != nil {
+			return err
+		}
+
+		loValidation := "PENDING"
+		academicAdvisorValidation := "PENDING"
+
+		registrationEntity = entity.Registration{
+			ID:                        uuid.New(),
+			ActivityID:                registration.ActivityID,
+			ActivityName:              activityName,
+			ProgramTypeID:             programTypeID,
+			AcademicPeriodID:          academicPeriodID(eligibility),
+			UserID:                    userID,
+			UserNRP:                   userNRP,
+			UserName:                  userName,
+			AdvisingConfirmation:      registration.AdvisingConfirmation,
+			AcademicAdvisorID:         advisor.ID,
+			AcademicAdvisor:           advisor.Name,
+			AcademicAdvisorEmail:      advisor.Email,
+			MentorName:                registration.MentorName,
+			MentorEmail:               registration.MentorEmail,
+			LOValidation:              loValidation,
+			AcademicAdvisorValidation: academicAdvisorValidation,
+			Semester:                  registration.Semester,
+			TotalSKS:                  registration.TotalSKS,
+			Status:                    entity.REGISTRATION_STATUS_ACTIVE,
+		}
+		academicRecord.applyTo(&registrationEntity)
+
+		_, err = s.registrationRepository.Create(ctx, registrationEntity, tx)
+		if err != nil {
+			return err
+		}
+
+		// Create document entity
+		documentEntity = entity.Document{
+			ID:             uuid.New(),
+			RegistrationID: registrationEntity.ID.String(),
+			Name:           file.Filename,
+			FileStorageID:  fileID,
+			DocumentType:   entity.DOCUMENT_TYPE_ACCEPTANCE_LETTER,
+			Checksum:       letterUpload.Checksum,
+			ContentType:    letterUpload.ContentType,
+			Size:           letterUpload.Size,
+		}
+
+		_, err = s.documentRepository.Create(ctx, documentEntity, tx)
+		if err != nil {
+			return err
+		}
+
+		geoletterEntity = entity.Document{
+			ID:             uuid.New(),
+			RegistrationID: registrationEntity.ID.String(),
+			Name:           geoletter.Filename,
+			FileStorageID:  geoletterFileID,
+			DocumentType:   entity.DOCUMENT_TYPE_GEOLETTER,
+			Checksum:       geoletterUpload.Checksum,
+			ContentType:    geoletterUpload.ContentType,
+			Size:           geoletterUpload.Size,
+		}
+
+		_, err = s.documentRepository.Create(ctx, geoletterEntity, tx)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
 	if err != nil {
+		s.removeUploadedFiles(ctx, fileID, geoletterFileID)
 		return err
 	}
 
 	userEmail := userData["email"]
 	message := fmt.Sprintf("%s has registered for %s", userName, activityName)
-	// send notification to academic advisor
+	message += s.documentLinksMessage([]entity.Document{documentEntity, geoletterEntity})
+	// send notification to the academic advisor resolved from user management
 	err = s.brokerService.SendNotification(map[string]interface{}{
 		"sender_name":    userName,
 		"sender_email":   userEmail,
-		"receiver_email": registration.AcademicAdvisorEmail,
+		"receiver_email": advisor.Email,
 		"type":           "REGISTER",
 		"message":        message,
 	}, "POST", token)
@@ -1058,92 +1184,176 @@ func (s *registrationService) CreateRegistration(ctx context.Context, registrati
 	return nil
 }
 
+// removeUploadedFiles deletes files uploaded for a registration that was not
+// created, a failure only leaves an orphaned file behind so it is logged.
+// It also runs when the request was cancelled.
+func (s *registrationService) removeUploadedFiles(ctx context.Context, fileIDs ...string) {
+	ctx = context.WithoutCancel(ctx)
+	for _, fileID := range fileIDs {
+		err := s.fileService.storage.Delete(ctx, fileID)
+		if err != nil {
+			log.Printf("failed to remove uploaded file %s: %v", fileID, err)
+		}
+	}
+}
+
+// UpdateRegistration is the PUT variant of PatchRegistration, empty fields
+// are left alone
 func (s *registrationService) UpdateRegistration(ctx context.Context, id string, registration dto.UpdateRegistrationDataRequest, token string, tx *gorm.DB) error {
+	return s.PatchRegistration(ctx, id, patchFromUpdateRequest(registration), token, tx)
+}
+
+func (s *registrationService) DeleteRegistration(ctx context.Context, id string, token string, tx *gorm.DB) error {
 	access := s.RegistrationsDataAccess(ctx, id, token, tx)
 	if !access {
 		return errors.New("data not found")
 	}
 
-	// Find existing registration
-	existingRegistration, err := s.registrationRepository.FindByID(ctx, id, tx)
+	// get registration by id
+	registration, err := s.registrationRepository.FindByID(ctx, id, tx)
 	if err != nil {
 		return err
 	}
 
-	// Update only the fields that are provided in the request
-	if registration.AdvisingConfirmation {
-		existingRegistration.AdvisingConfirmation = registration.AdvisingConfirmation
-	}
-	if registration.AcademicAdvisor != "" {
-		existingRegistration.AcademicAdvisor = registration.AcademicAdvisor
-	}
-	if registration.AcademicAdvisorEmail != "" {
-		existingRegistration.AcademicAdvisorEmail = registration.AcademicAdvisorEmail
+	// once approved, students have to go through the withdrawal flow so the record is kept
+	userData := s.userManagementService.GetUserData("GET", token)
+	if userRole, _ := userData["role"].(string); userRole == "MAHASISWA" {
+		if registration.Status != entity.REGISTRATION_STATUS_ACTIVE && registration.Status != "" {
+			return errors.New("registration can not be deleted while a withdrawal is requested or completed")
+		}
+		if registration.AcademicAdvisorValidation == "APPROVED" || registration.LOValidation == "APPROVED" {
+			return errors.New("approved registration must be withdrawn instead of deleted")
+		}
 	}
-	if registration.MentorName != "" {
-		existingRegistration.MentorName = registration.MentorName
+
+	// the documents and their files stay until the registration is purged, so
+	// a mistaken delete can be restored
+	err = s.registrationRepository.Destroy(ctx, id, tx)
+	if err != nil {
+		return err
 	}
-	if registration.MentorEmail != "" {
-		existingRegistration.MentorEmail = registration.MentorEmail
+
+	return s.recordHistory(ctx, registration, entity.REGISTRATION_HISTORY_DELETED, registration.Status, "", userData, tx)
+}
+
+// listRegistrations reads a listing page by page/limit or, when the request
+// carries a cursor, by keyset
+func (s *registrationService) listRegistrations(ctx context.Context, pagReq dto.PaginationRequest, filter dto.FilterRegistrationRequest, tx *gorm.DB) ([]entity.Registration, dto.PaginationResponse, error) {
+	if !pagReq.Keyset {
+		registrations, total, err := s.registrationRepository.Index(ctx, tx, pagReq, filter)
+		if err != nil {
+			return nil, dto.PaginationResponse{}, err
+		}
+
+		return registrations, helper.MetaDataPagination(total, pagReq), nil
 	}
-	if registration.Semester != 0 {
-		existingRegistration.Semester = registration.Semester
+
+	registrations, hasMore, total, err := s.registrationRepository.IndexKeyset(ctx, tx, pagReq, filter)
+	if err != nil {
+		return nil, dto.PaginationResponse{}, err
 	}
-	if registration.TotalSKS != 0 {
-		existingRegistration.TotalSKS = registration.TotalSKS
+
+	var first, last *dto.Cursor
+	if len(registrations) > 0 {
+		first = registrationCursor(registrations[0])
+		last = registrationCursor(registrations[len(registrations)-1])
 	}
 
-	// Perform the update
-	err = s.registrationRepository.Update(ctx, id, existingRegistration, tx)
-	if err != nil {
-		return err
+	return registrations, helper.CursorMetaData(pagReq, first, last, hasMore, total), nil
+}
+
+func registrationCursor(registration entity.Registration) *dto.Cursor {
+	cursor := &dto.Cursor{ID: registration.ID.String()}
+	if registration.CreatedAt != nil {
+		cursor.CreatedAt = *registration.CreatedAt
 	}
 
-	return nil
+	return cursor
 }
 
-func (s *registrationService) DeleteRegistration(ctx context.Context, id string, token string, tx *gorm.DB) error {
-	access := s.RegistrationsDataAccess(ctx, id, token, tx)
-	if !access {
-		return errors.New("data not found")
+// documentCompleteness is the checklist indicator attached to a registration
+// response, nil when the checklist can't be resolved
+func (s *registrationService) documentCompleteness(ctx context.Context, registration entity.Registration, tx *gorm.DB) *dto.DocumentCompletenessResponse {
+	completeness, err := s.documentRequirementService.CheckCompleteness(ctx, registration, tx)
+	if err != nil {
+		log.Printf("Error checking document completeness for registration %s: %v", registration.ID.String(), err)
+		return nil
 	}
+	return &completeness
+}
 
-	// get registration by id
-	registration, err := s.registrationRepository.FindByID(ctx, id, tx)
+// pageCompleteness is documentCompleteness for a listing page, keyed by
+// registration ID. The checklists of the page are loaded with one query.
+func (s *registrationService) pageCompleteness(ctx context.Context, registrations []entity.Registration, tx *gorm.DB) map[string]*dto.DocumentCompletenessResponse {
+	response := map[string]*dto.DocumentCompletenessResponse{}
+
+	completeness, err := s.documentRequirementService.CheckCompletenessForRegistrations(ctx, registrations, tx)
 	if err != nil {
-		return err
+		log.Printf("Error checking document completeness for %d registrations: %v", len(registrations), err)
+		return response
 	}
 
-	// delete document
-	for _, document := range registration.Document {
-		_, err = s.fileService.storage.GcsDelete(document.FileStorageID, "sim_mbkm", "")
-		if err != nil {
-			return err
-		}
+	for id := range completeness {
+		value := completeness[id]
+		response[id] = &value
 	}
+	return response
+}
 
-	err = s.registrationRepository.Destroy(ctx, id, tx)
+// requireCompleteDocuments blocks an approval until every required document
+// has been uploaded and accepted by a reviewer.
+func (s *registrationService) requireCompleteDocuments(ctx context.Context, registration entity.Registration, tx *gorm.DB) error {
+	completeness, err := s.documentRequirementService.CheckCompleteness(ctx, registration, tx)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	err = errMissingDocuments(completeness)
+	if err != nil {
+		return err
+	}
+
+	return errUnacceptedDocuments(completeness)
 }
 
 func convertToDocumentResponse(documents []entity.Document) []dto.DocumentResponse {
 	var documentResponses []dto.DocumentResponse
 	for _, document := range documents {
-		documentResponses = append(documentResponses, dto.DocumentResponse{
-			ID:             document.ID.String(),
-			RegistrationID: document.RegistrationID,
-			Name:           document.Name,
-			FileStorageID:  document.FileStorageID,
-			DocumentType:   document.DocumentType,
-		})
+		documentResponses = append(documentResponses, toDocumentResponse(document))
 	}
 	return documentResponses
 }
 
+func toDocumentResponse(document entity.Document) dto.DocumentResponse {
+	reviewStatus := document.ReviewStatus
+	if reviewStatus == "" {
+		reviewStatus = entity.DOCUMENT_REVIEW_PENDING
+	}
+
+	var reviewedAt string
+	if document.ReviewedAt != nil {
+		reviewedAt = document.ReviewedAt.Format(time.RFC3339)
+	}
+
+	return dto.DocumentResponse{
+		ID:             document.ID.String(),
+		RegistrationID: document.RegistrationID,
+		Name:           document.Name,
+		FileStorageID:  document.FileStorageID,
+		DocumentType:   document.DocumentType,
+		Checksum:       document.Checksum,
+		ContentType:    document.ContentType,
+		Size:           document.Size,
+		CurrentVersion: currentVersion(document),
+		LockVersion:    document.LockVersion,
+		ReviewStatus:   reviewStatus,
+		ReviewerName:   document.ReviewerName,
+		ReviewerEmail:  document.ReviewerEmail,
+		ReviewComment:  document.ReviewComment,
+		ReviewedAt:     reviewedAt,
+	}
+}
+
 func (s *registrationService) GetRegistrationTranscript(ctx context.Context, id string, token string, tx *gorm.DB) (dto.TranscriptResponse, error) {
 	// Check if the user has access to this registration
 	access := s.RegistrationsDataAccess(ctx, id, token, tx)
@@ -1201,14 +1411,11 @@ func (s *registrationService) GetStudentRegistrationsWithTranscripts(ctx context
 	filter.UserNRP = userNRP
 
 	// Get all student registrations
-	registrations, total, err := s.registrationRepository.Index(ctx, tx, pagReq, filter)
+	registrations, metaData, err := s.listRegistrations(ctx, pagReq, filter, tx)
 	if err != nil {
 		return dto.StudentTranscriptsResponse{}, dto.PaginationResponse{}, err
 	}
 
-	// Create pagination metadata
-	metaData := helper.MetaDataPagination(total, pagReq)
-
 	// Prepare the response
 	response := dto.StudentTranscriptsResponse{
 		UserID:   userData["id"].(string),
@@ -1267,14 +1474,11 @@ func (s *registrationService) GetStudentRegistrationsWithSyllabuses(ctx context.
 	filter.UserNRP = userNRP
 
 	// Get all student registrations
-	registrations, total, err := s.registrationRepository.Index(ctx, tx, pagReq, filter)
+	registrations, metaData, err := s.listRegistrations(ctx, pagReq, filter, tx)
 	if err != nil {
 		return dto.StudentSyllabusesResponse{}, dto.PaginationResponse{}, err
 	}
 
-	// Create pagination metadata
-	metaData := helper.MetaDataPagination(total, pagReq)
-
 	// Prepare the response
 	response := dto.StudentSyllabusesResponse{
 		UserID:   userData["id"].(string),
@@ -1333,14 +1537,11 @@ func (s *registrationService) FindRegistrationsWithMatching(ctx context.Context,
 	filter.UserNRP = userNRP
 
 	// Get all student registrations
-	registrations, total, err := s.registrationRepository.Index(ctx, tx, pagReq, filter)
+	registrations, metaData, err := s.listRegistrations(ctx, pagReq, filter, tx)
 	if err != nil {
 		return dto.StudentRegistrationsWithMatchingResponse{}, dto.PaginationResponse{}, err
 	}
 
-	// Create pagination metadata
-	metaData := helper.MetaDataPagination(total, pagReq)
-
 	// Prepare the response
 	response := dto.StudentRegistrationsWithMatchingResponse{
 		UserID:   userData["id"].(string),
@@ -1350,6 +1551,7 @@ func (s *registrationService) FindRegistrationsWithMatching(ctx context.Context,
 
 	// Process each registration to fetch matching data
 	var studentRegistrations []dto.StudentRegistrationWithMatchingResponse
+	completeness := s.pageCompleteness(ctx, registrations, tx)
 	for _, registration := range registrations {
 		// Get equivalents data
 		equivalents, err := s.matchingManagementService.GetEquivalentsByRegistrationID(registration.ID.String(), "GET", token)
@@ -1382,7 +1584,12 @@ func (s *registrationService) FindRegistrationsWithMatching(ctx context.Context,
 			Semester:                  registration.Semester,
 			TotalSKS:                  registration.TotalSKS,
 			ApprovalStatus:            registration.ApprovalStatus,
+			Status:                    registration.Status,
+			WithdrawalReason:          registration.WithdrawalReason,
+			LockVersion:               registration.LockVersion,
 			Documents:                 convertToDocumentResponse(registration.Document),
+			DocumentCompleteness:      completeness[registration.ID.String()],
+			AcademicRecord:            academicRecordResponse(registration),
 			Equivalents:               equivalents,
 			Matching:                  matching,
 		})
@@ -1411,167 +1618,27 @@ func (s *registrationService) CheckRegistrationEligibility(ctx context.Context,
 		}, errors.New("invalid user NRP")
 	}
 
-	// Check if the activity exists and is open for registration
-	activitiesData := s.activityManagementService.GetActivitiesData(map[string]interface{}{
-		"activity_id":     activityID,
-		"program_type_id": "",
-		"level_id":        "",
-		"group_id":        "",
-		"name":            "",
-	}, "POST", token)
-
-	if len(activitiesData) == 0 {
-		return dto.RegistrationEligibilityResponse{
-			Eligible: false,
-			Message:  "Activity not found",
-		}, errors.New("activity not found")
-	}
-
-	approvalStatus, ok := activitiesData[0]["approval_status"].(string)
-	if !ok || approvalStatus != "APPROVED" {
-		return dto.RegistrationEligibilityResponse{
-			Eligible: false,
-			Message:  "This activity is not open for registration",
-		}, errors.New("this activity is not open for registration")
-	}
-
-	// Check if already registered for this activity
-	registrationByActivityIDAndNRP, err := s.registrationRepository.FindByActivityIDAndNRP(ctx, activityID, userNRP, tx)
-	if err != nil {
-		if err.Error() != "record not found" {
-			return dto.RegistrationEligibilityResponse{
-				Eligible: false,
-				Message:  "Error checking existing registration",
-			}, errors.New("error getting registration by activity_id and user_nrp")
-		}
-	}
+	return s.checkEligibility(ctx, eligibilityApplicant{NRP: userNRP}, activityID, token, tx)
+}
 
-	if registrationByActivityIDAndNRP.ActivityID == activityID {
-		return dto.RegistrationEligibilityResponse{
-			Eligible: false,
-			Message:  "User already registered for this activity",
-		}, errors.New("user already registered")
+// documentLinksMessage lists signed download links so the advisor can open the
+// documents straight from the notification. Links are only added when signed
+// links are enabled and a public base URL is configured, a relative link is
+// useless in an email.
+func (s *registrationService) documentLinksMessage(documents []entity.Document) string {
+	if s.downloadSigner == nil || !s.downloadSigner.Enabled() || s.downloadSigner.baseURL == "" {
+		return ""
 	}
 
-	// Check existing registrations for time conflicts
-	registrationsByNRP, err := s.registrationRepository.FindByNRP(ctx, userNRP, tx)
-	if err != nil {
-		if err.Error() != "record not found" {
-			return dto.RegistrationEligibilityResponse{
-				Eligible: false,
-				Message:  "Error checking existing registrations",
-			}, errors.New("error checking existing registrations")
-		}
+	var links []string
+	for _, document := range documents {
+		link, _ := s.downloadSigner.SignedURL(document.ID.String(), 0)
+		links = append(links, fmt.Sprintf("%s: %s", document.DocumentType, link))
 	}
 
-	if registrationsByNRP.ActivityID != "" && registrationsByNRP.ActivityID != activityID {
-		// Get activity data for the existing registration
-		activitiesDataOld := s.activityManagementService.GetActivitiesData(map[string]interface{}{
-			"activity_id":     registrationsByNRP.ActivityID,
-			"program_type_id": "",
-			"level_id":        "",
-			"group_id":        "",
-			"name":            "",
-		}, "POST", token)
-
-		if len(activitiesDataOld) == 0 {
-			return dto.RegistrationEligibilityResponse{
-				Eligible: false,
-				Message:  "Error checking existing activity data",
-			}, errors.New("old activity data not found")
-		}
-
-		// Parse the old activity start date and duration
-		var activityOldStartDate time.Time
-		var activityOldMonthsDuration int
-
-		// Handle start_date
-		if startDateVal, ok := activitiesDataOld[0]["start_period"]; ok && startDateVal != nil {
-			if startDateStr, ok := startDateVal.(string); ok {
-				parsedTime, err := time.Parse(time.RFC3339, startDateStr)
-				if err != nil {
-					return dto.RegistrationEligibilityResponse{
-						Eligible: false,
-						Message:  "Invalid start period format in existing activity",
-					}, errors.New("invalid start_period format in old activity")
-				}
-				activityOldStartDate = parsedTime
-			} else if startDate, ok := startDateVal.(time.Time); ok {
-				activityOldStartDate = startDate
-			} else {
-				return dto.RegistrationEligibilityResponse{
-					Eligible: false,
-					Message:  "Invalid start period data in existing activity",
-				}, errors.New("invalid start_period type in old activity")
-			}
-		} else {
-			return dto.RegistrationEligibilityResponse{
-				Eligible: false,
-				Message:  "Start period not found in existing activity",
-			}, errors.New("start_period not found in old activity")
-		}
-
-		// Handle months_duration
-		if durationVal, ok := activitiesDataOld[0]["months_duration"]; ok && durationVal != nil {
-			switch v := durationVal.(type) {
-			case int:
-				activityOldMonthsDuration = v
-			case float64:
-				activityOldMonthsDuration = int(v)
-			default:
-				return dto.RegistrationEligibilityResponse{
-					Eligible: false,
-					Message:  "Invalid duration format in existing activity",
-				}, errors.New("invalid months_duration type in old activity")
-			}
-		} else {
-			return dto.RegistrationEligibilityResponse{
-				Eligible: false,
-				Message:  "Duration not found in existing activity",
-			}, errors.New("months_duration not found in old activity")
-		}
-
-		activityOldEndDate := activityOldStartDate.AddDate(0, activityOldMonthsDuration, 0)
-
-		// Get start date for the new activity
-		var activityNewStartDate time.Time
-		if startDateVal, ok := activitiesData[0]["start_period"]; ok && startDateVal != nil {
-			if startDateStr, ok := startDateVal.(string); ok {
-				parsedTime, err := time.Parse(time.RFC3339, startDateStr)
-				if err != nil {
-					return dto.RegistrationEligibilityResponse{
-						Eligible: false,
-						Message:  "Invalid start period format in new activity",
-					}, errors.New("invalid start_period format in new activity")
-				}
-				activityNewStartDate = parsedTime
-			} else if startDate, ok := startDateVal.(time.Time); ok {
-				activityNewStartDate = startDate
-			} else {
-				return dto.RegistrationEligibilityResponse{
-					Eligible: false,
-					Message:  "Invalid start period data in new activity",
-				}, errors.New("invalid start_period type in new activity")
-			}
-		} else {
-			return dto.RegistrationEligibilityResponse{
-				Eligible: false,
-				Message:  "Start period not found in new activity",
-			}, errors.New("start_period not found in new activity")
-		}
-
-		// Check for time conflicts
-		if !activityNewStartDate.After(activityOldEndDate) {
-			return dto.RegistrationEligibilityResponse{
-				Eligible: false,
-				Message:  "User already registered for an overlapping activity period",
-			}, errors.New("user already registered for an overlapping activity period")
-		}
+	if len(links) == 0 {
+		return ""
 	}
 
-	// All checks passed, user is eligible to register
-	return dto.RegistrationEligibilityResponse{
-		Eligible: true,
-		Message:  "User is eligible to register for this activity",
-	}, nil
+	return "\n" + strings.Join(links, "\n")
 }
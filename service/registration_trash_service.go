@@ -0,0 +1,284 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"registration-service/config"
+	"registration-service/dto"
+	"registration-service/entity"
+	"registration-service/helper"
+	"registration-service/repository"
+	"time"
+
+	storageService "github.com/SIM-MBKM/filestorage/storage"
+	"gorm.io/gorm"
+)
+
+type registrationTrashService struct {
+	registrationRepository        repository.RegistrationRepository
+	registrationHistoryRepository repository.RegistrationHistoryRepository
+	documentVersionRepository     repository.DocumentVersionRepository
+	registrationService           *registrationService // only the eligibility check a restore repeats
+	userManagementService         *UserManagementService
+	fileService                   *FileService
+	retention                     time.Duration
+	batchSize                     int
+}
+
+// RegistrationTrashService lets admins look into and restore deleted
+// registrations until they are purged after the retention period
+type RegistrationTrashService interface {
+	FindDeletedRegistrations(ctx context.Context, pagReq dto.PaginationRequest, tx *gorm.DB) ([]dto.DeletedRegistrationResponse, dto.PaginationResponse, error)
+	FindDeletedRegistrationByID(ctx context.Context, id string, tx *gorm.DB) (dto.DeletedRegistrationResponse, error)
+	RestoreRegistration(ctx context.Context, id string, token string, tx *gorm.DB) error
+	PurgeDeletedRegistrations(ctx context.Context, now time.Time) (dto.RegistrationPurgeResult, error)
+}
+
+func NewRegistrationTrashService(registrationRepository repository.RegistrationRepository, registrationHistoryRepository repository.RegistrationHistoryRepository, documentVersionRepository repository.DocumentVersionRepository, eligibilityRuleRepository repository.EligibilityRuleRepository, academicPeriodRepository repository.AcademicPeriodRepository, storageConfig config.StorageConfig, purgeConfig config.PurgeConfig, userManagementbaseURI string, activityManagementbaseURI string, asyncURIs []string, config *storageService.Config, tokenManager *storageService.CacheTokenManager) RegistrationTrashService {
+	batchSize := int(purgeConfig.BatchSize)
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	return &registrationTrashService{
+		registrationRepository:        registrationRepository,
+		registrationHistoryRepository: registrationHistoryRepository,
+		documentVersionRepository:     documentVersionRepository,
+		registrationService: &registrationService{
+			registrationRepository:    registrationRepository,
+			eligibilityRuleService:    NewEligibilityRuleService(eligibilityRuleRepository),
+			academicPeriodService:     NewAcademicPeriodService(academicPeriodRepository),
+			activityManagementService: NewActivityManagementService(activityManagementbaseURI, asyncURIs),
+		},
+		userManagementService: NewUserManagementService(userManagementbaseURI, asyncURIs),
+		fileService:           NewFileService(storageConfig, config, tokenManager),
+		retention:             time.Duration(purgeConfig.RetentionDays) * 24 * time.Hour,
+		batchSize:             batchSize,
+	}
+}
+
+func (s *registrationTrashService) FindDeletedRegistrations(ctx context.Context, pagReq dto.PaginationRequest, tx *gorm.DB) ([]dto.DeletedRegistrationResponse, dto.PaginationResponse, error) {
+	registrations, total, err := s.registrationRepository.IndexDeleted(ctx, pagReq, tx)
+	if err != nil {
+		return nil, dto.PaginationResponse{}, err
+	}
+
+	response := []dto.DeletedRegistrationResponse{}
+	for _, registration := range registrations {
+		response = append(response, s.toDeletedRegistrationResponse(registration))
+	}
+
+	return response, helper.MetaDataPagination(total, pagReq), nil
+}
+
+func (s *registrationTrashService) FindDeletedRegistrationByID(ctx context.Context, id string, tx *gorm.DB) (dto.DeletedRegistrationResponse, error) {
+	registration, err := s.registrationRepository.FindDeletedByID(ctx, id, tx)
+	if err != nil {
+		return dto.DeletedRegistrationResponse{}, err
+	}
+
+	return s.toDeletedRegistrationResponse(registration), nil
+}
+
+// RestoreRegistration puts a deleted registration back as it was, its
+// documents were kept so nothing has to be uploaded again. A registration
+// that holds its activity period is checked again like a new one, the student
+// may have registered for an overlapping activity since it was deleted.
+func (s *registrationTrashService) RestoreRegistration(ctx context.Context, id string, token string, tx *gorm.DB) error {
+	userData := s.userManagementService.GetUserData("GET", token)
+	if userData == nil {
+		return errors.New("Unauthorized")
+	}
+
+	restore := func(tx *gorm.DB) error {
+		// waits for a purge of the registration that is under way
+		registration, err := s.registrationRepository.LockDeleted(ctx, id, tx)
+		if err != nil {
+			return err
+		}
+
+		if holdsActivityPeriod(registration) {
+			err = s.registrationRepository.LockNRP(ctx, registration.UserNRP, tx)
+			if err != nil {
+				return err
+			}
+
+			_, err = s.registrationService.checkEligibility(ctx, eligibilityApplicant{NRP: registration.UserNRP, Semester: registration.Semester, TotalSKS: registration.TotalSKS, RegistrationID: id}, registration.ActivityID, token, tx)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = s.registrationRepository.Restore(ctx, id, tx)
+		if err != nil {
+			return err
+		}
+
+		_, err = s.registrationHistoryRepository.Create(ctx, newRegistrationHistory(registration, entity.REGISTRATION_HISTORY_RESTORED, registration.Status, "", userData), tx)
+		return err
+	}
+
+	if tx != nil {
+		return restore(tx)
+	}
+
+	return s.registrationRepository.WithTransaction(ctx, restore)
+}
+
+// holdsActivityPeriod tells whether the registration counts in the overlap
+// check, the same registrations FindActiveByNRP returns
+func holdsActivityPeriod(registration entity.Registration) bool {
+	switch registration.Status {
+	case entity.REGISTRATION_STATUS_DRAFT, entity.REGISTRATION_STATUS_WITHDRAWN:
+		return false
+	}
+
+	return registration.LOValidation != "REJECTED" && registration.AcademicAdvisorValidation != "REJECTED"
+}
+
+// PurgeDeletedRegistrations removes up to a batch of the registrations deleted
+// longer than the retention period ago. A registration whose files can't be
+// deleted stays in the trash and is tried again on the next run, the run reads
+// on past it so it doesn't hold up the others.
+func (s *registrationTrashService) PurgeDeletedRegistrations(ctx context.Context, now time.Time) (dto.RegistrationPurgeResult, error) {
+	result := dto.RegistrationPurgeResult{Purged: []string{}, Skipped: []string{}}
+	before := now.Add(-s.retention)
+
+	var after *dto.Cursor
+	for len(result.Purged) < s.batchSize {
+		limit := s.batchSize - len(result.Purged)
+		registrations, err := s.registrationRepository.FindDeletedBefore(ctx, before, after, limit, nil)
+		if err != nil {
+			return result, err
+		}
+
+		for _, registration := range registrations {
+			id := registration.ID.String()
+
+			err = s.purge(ctx, id)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				// restored since the batch was read
+				continue
+			}
+			if err != nil {
+				log.Printf("failed to purge registration %s: %v", id, err)
+				result.Skipped = append(result.Skipped, id)
+				continue
+			}
+
+			result.Purged = append(result.Purged, id)
+		}
+
+		if len(registrations) < limit {
+			break
+		}
+
+		last := registrations[len(registrations)-1]
+		after = &dto.Cursor{CreatedAt: last.DeletedAt.Time, ID: last.ID.String()}
+	}
+
+	return result, nil
+}
+
+// purge deletes the files of a deleted registration and then the registration.
+// The row stays locked meanwhile, a restore waits and then finds it gone.
+func (s *registrationTrashService) purge(ctx context.Context, id string) error {
+	return s.registrationRepository.WithTransaction(ctx, func(tx *gorm.DB) error {
+		registration, err := s.registrationRepository.LockDeleted(ctx, id, tx)
+		if err != nil {
+			return err
+		}
+
+		err = deleteDocumentFiles(ctx, s.fileService, s.documentVersionRepository, registration.Document)
+		if err != nil {
+			return err
+		}
+
+		return s.registrationRepository.Purge(ctx, id, tx)
+	})
+}
+
+// deleteDocumentFiles removes the stored files of the documents, older
+// versions included
+func deleteDocumentFiles(ctx context.Context, fileService *FileService, documentVersionRepository repository.DocumentVersionRepository, documents []entity.Document) error {
+	for _, document := range documents {
+		fileIDs := map[string]bool{document.FileStorageID: true}
+
+		versions, err := documentVersionRepository.FindByDocumentID(ctx, document.ID.String(), nil)
+		if err != nil {
+			return err
+		}
+		for _, version := range versions {
+			fileIDs[version.FileStorageID] = true
+		}
+
+		for fileID := range fileIDs {
+			err = fileService.storage.Delete(ctx, fileID)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *registrationTrashService) toDeletedRegistrationResponse(registration entity.Registration) dto.DeletedRegistrationResponse {
+	documents := []dto.DocumentResponse{}
+	for _, document := range registration.Document {
+		documents = append(documents, toDocumentResponse(document))
+	}
+
+	return dto.DeletedRegistrationResponse{
+		ID:                        registration.ID.String(),
+		ActivityID:                registration.ActivityID,
+		ActivityName:              registration.ActivityName,
+		UserID:                    registration.UserID,
+		UserNRP:                   registration.UserNRP,
+		UserName:                  registration.UserName,
+		AcademicAdvisor:           registration.AcademicAdvisor,
+		AcademicAdvisorEmail:      registration.AcademicAdvisorEmail,
+		Status:                    registration.Status,
+		LOValidation:              registration.LOValidation,
+		AcademicAdvisorValidation: registration.AcademicAdvisorValidation,
+		Documents:                 documents,
+		DeletedAt:                 registration.DeletedAt.Time,
+		PurgeAt:                   registration.DeletedAt.Time.Add(s.retention),
+	}
+}
+
+// RegistrationPurgeJob runs PurgeDeletedRegistrations in the background, once
+// when it starts and then every interval until ctx is done
+type RegistrationPurgeJob struct {
+	registrationTrashService RegistrationTrashService
+	interval                 time.Duration
+}
+
+func NewRegistrationPurgeJob(registrationTrashService RegistrationTrashService, purgeConfig config.PurgeConfig) *RegistrationPurgeJob {
+	interval := time.Duration(purgeConfig.IntervalMinutes) * time.Minute
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	return &RegistrationPurgeJob{
+		registrationTrashService: registrationTrashService,
+		interval:                 interval,
+	}
+}
+
+func (j *RegistrationPurgeJob) Start(ctx context.Context) {
+	runEvery(ctx, j.interval, j.run)
+}
+
+func (j *RegistrationPurgeJob) run(ctx context.Context) {
+	result, err := j.registrationTrashService.PurgeDeletedRegistrations(ctx, time.Now())
+	if err != nil {
+		log.Printf("registration purge failed: %v", err)
+		return
+	}
+
+	if len(result.Purged) > 0 || len(result.Skipped) > 0 {
+		log.Printf("registration purge removed %d registrations, %d skipped", len(result.Purged), len(result.Skipped))
+	}
+}
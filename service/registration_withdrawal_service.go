@@ -0,0 +1,313 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"registration-service/dto"
+	"registration-service/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RequestWithdrawal moves an active registration into the withdrawal-requested
+// state. The registration is kept; it only becomes WITHDRAWN once both the
+// academic advisor and LO-MBKM confirm the request.
+func (s *registrationService) RequestWithdrawal(ctx context.Context, id string, request dto.WithdrawalRequest, token string, tx *gorm.DB) error {
+	userNRP := s.ValidateStudent(ctx, token, tx)
+	if userNRP == "" {
+		return errors.New("Unauthorized")
+	}
+
+	registration, err := s.registrationRepository.FindByID(ctx, id, tx)
+	if err != nil {
+		return err
+	}
+
+	if registration.UserNRP != userNRP {
+		return errors.New("data not found")
+	}
+
+	switch registration.Status {
+	case entity.REGISTRATION_STATUS_DRAFT:
+		return errors.New("a draft can't be withdrawn, delete it instead")
+	case entity.REGISTRATION_STATUS_WITHDRAWAL_REQUESTED:
+		return errors.New("withdrawal already requested")
+	case entity.REGISTRATION_STATUS_WITHDRAWN:
+		return errors.New("registration already withdrawn")
+	}
+
+	fromStatus := registration.Status
+	now := time.Now()
+	registration.Status = entity.REGISTRATION_STATUS_WITHDRAWAL_REQUESTED
+	registration.StatusBeforeWithdrawal = fromStatus
+	registration.WithdrawalReason = request.Reason
+	registration.WithdrawalRequestedAt = &now
+	registration.WithdrawalAdvisorValidation = "PENDING"
+	registration.WithdrawalLOValidation = "PENDING"
+
+	err = s.registrationRepository.Update(ctx, id, registration, tx)
+	if err != nil {
+		return err
+	}
+
+	userData := s.userManagementService.GetUserData("GET", token)
+	err = s.recordHistory(ctx, registration, entity.REGISTRATION_HISTORY_WITHDRAWAL_REQUESTED, fromStatus, request.Reason, userData, tx)
+	if err != nil {
+		return err
+	}
+
+	// notify the academic advisor, LO-MBKM picks the request up from the listing
+	message := fmt.Sprintf("%s has requested to withdraw from %s", registration.UserName, registration.ActivityName)
+	s.brokerService.SendNotification(map[string]interface{}{
+		"sender_name":    userData["name"],
+		"sender_email":   userData["email"],
+		"receiver_email": registration.AcademicAdvisorEmail,
+		"type":           "WITHDRAWAL REQUEST",
+		"message":        message,
+	}, "POST", token)
+
+	return nil
+}
+
+func (s *registrationService) AdvisorWithdrawalApproval(ctx context.Context, token string, approval dto.ApprovalRequest, tx *gorm.DB) error {
+	userEmail := s.ValidateAdvisor(ctx, token, tx)
+	if userEmail == "" {
+		return errors.New("Unauthorized")
+	}
+
+	userData := s.userManagementService.GetUserData("GET", token)
+
+	delegations, err := s.delegatedAdvisors(ctx, userEmail, tx)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range approval.ID {
+		registration, err := s.registrationRepository.FindByID(ctx, id, tx)
+		if err != nil {
+			return err
+		}
+
+		delegation, delegated, allowed := actingFor(registration, userEmail, delegations)
+		if !allowed {
+			return errors.New("Unauthorized")
+		}
+
+		err = checkLockVersion(approval.LockVersion, "registration", id, registration.LockVersion)
+		if err != nil {
+			return err
+		}
+
+		if registration.WithdrawalAdvisorValidation == approval.Status {
+			return fmt.Errorf("withdrawal already %s", statusText(approval.Status))
+		}
+
+		registration.WithdrawalAdvisorValidation = approval.Status
+
+		var note string
+		if delegated {
+			note = delegationNote(statusText(approval.Status), delegation)
+		}
+
+		err = s.processWithdrawalApproval(ctx, registration, approval.Status, userData, note, token, tx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *registrationService) LOWithdrawalApproval(ctx context.Context, token string, approval dto.ApprovalRequest, tx *gorm.DB) error {
+	userData := s.userManagementService.GetUserData("GET", token)
+	if userData == nil {
+		return errors.New("Unauthorized")
+	}
+
+	for _, id := range approval.ID {
+		registration, err := s.registrationRepository.FindByID(ctx, id, tx)
+		if err != nil {
+			return err
+		}
+
+		err = checkLockVersion(approval.LockVersion, "registration", id, registration.LockVersion)
+		if err != nil {
+			return err
+		}
+
+		if registration.WithdrawalLOValidation == approval.Status {
+			return fmt.Errorf("withdrawal already %s", statusText(approval.Status))
+		}
+
+		registration.WithdrawalLOValidation = approval.Status
+
+		err = s.processWithdrawalApproval(ctx, registration, approval.Status, userData, "", token, tx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// processWithdrawalApproval applies one reviewer's decision. A single
+// rejection sends the registration back to the status it had before the
+// request, while both approvals finalise the withdrawal and cancel the report
+// schedules in monitoring. The note is kept with the decision, e.g. when a
+// delegate took it.
+func (s *registrationService) processWithdrawalApproval(ctx context.Context, registration entity.Registration, status string, userData map[string]interface{}, note string, token string, tx *gorm.DB) error {
+	if registration.Status != entity.REGISTRATION_STATUS_WITHDRAWAL_REQUESTED {
+		return errors.New("registration has no pending withdrawal request")
+	}
+
+	fromStatus := registration.Status
+	action := entity.REGISTRATION_HISTORY_WITHDRAWAL_APPROVED
+
+	switch status {
+	case "APPROVED":
+		if registration.WithdrawalAdvisorValidation == "APPROVED" && registration.WithdrawalLOValidation == "APPROVED" {
+			now := time.Now()
+			registration.Status = entity.REGISTRATION_STATUS_WITHDRAWN
+			registration.WithdrawnAt = &now
+			registration.ApprovalStatus = false
+		}
+	case "REJECTED":
+		action = entity.REGISTRATION_HISTORY_WITHDRAWAL_REJECTED
+		registration.Status = registration.StatusBeforeWithdrawal
+		// requests made before the status was kept came from ACTIVE
+		if registration.Status == "" {
+			registration.Status = entity.REGISTRATION_STATUS_ACTIVE
+		}
+	default:
+		return errors.New("invalid approval status")
+	}
+
+	err := s.registrationRepository.Update(ctx, registration.ID.String(), registration, tx)
+	if err != nil {
+		return err
+	}
+
+	err = s.recordHistory(ctx, registration, action, fromStatus, note, userData, tx)
+	if err != nil {
+		return err
+	}
+
+	if registration.Status == entity.REGISTRATION_STATUS_WITHDRAWN {
+		err = s.recordHistory(ctx, registration, entity.REGISTRATION_HISTORY_WITHDRAWN, fromStatus, registration.WithdrawalReason, userData, tx)
+		if err != nil {
+			return err
+		}
+
+		err = s.monitoringManagementService.CancelReportSchedulesByRegistrationID(registration.ID.String(), token)
+		if err != nil {
+			// the withdrawal itself is already stored, don't fail the main flow
+			log.Printf("ERROR CANCELLING REPORT SCHEDULES for %s: %v", registration.ID.String(), err)
+		}
+	}
+
+	if registration.Status != entity.REGISTRATION_STATUS_WITHDRAWAL_REQUESTED {
+		s.sendWithdrawalNotification(registration, userData, token)
+	}
+
+	return nil
+}
+
+func (s *registrationService) sendWithdrawalNotification(registration entity.Registration, userData map[string]interface{}, token string) {
+	mahasiswaData := s.userManagementService.GetUserByFilter(map[string]interface{}{
+		"user_nrp": registration.UserNRP,
+	}, "POST", token)
+
+	if len(mahasiswaData) == 0 {
+		log.Printf("No user data found for NRP: %s", registration.UserNRP)
+		return
+	}
+
+	message := fmt.Sprintf("Your withdrawal request from %s has been rejected", registration.ActivityName)
+	if registration.Status == entity.REGISTRATION_STATUS_WITHDRAWN {
+		message = fmt.Sprintf("You have been withdrawn from %s", registration.ActivityName)
+	}
+
+	s.brokerService.SendNotification(map[string]interface{}{
+		"sender_name":    userData["name"],
+		"sender_email":   userData["email"],
+		"receiver_email": mahasiswaData[0]["email"],
+		"type":           "WITHDRAWAL REGISTRATION",
+		"message":        message,
+	}, "POST", token)
+}
+
+func (s *registrationService) FindRegistrationHistory(ctx context.Context, id string, token string, tx *gorm.DB) ([]dto.RegistrationHistoryResponse, error) {
+	access := s.RegistrationsDataAccess(ctx, id, token, tx)
+	if !access {
+		return nil, errors.New("data not found")
+	}
+
+	histories, err := s.registrationHistoryRepository.FindByRegistrationID(ctx, id, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var response []dto.RegistrationHistoryResponse
+	for _, history := range histories {
+		var createdAt string
+		if history.CreatedAt != nil {
+			createdAt = history.CreatedAt.Format(time.RFC3339)
+		}
+
+		response = append(response, dto.RegistrationHistoryResponse{
+			ID:             history.ID.String(),
+			RegistrationID: history.RegistrationID,
+			Action:         history.Action,
+			FromStatus:     history.FromStatus,
+			ToStatus:       history.ToStatus,
+			ActorID:        history.ActorID,
+			ActorName:      history.ActorName,
+			ActorEmail:     history.ActorEmail,
+			ActorRole:      history.ActorRole,
+			Note:           history.Note,
+			CreatedAt:      createdAt,
+		})
+	}
+
+	return response, nil
+}
+
+// recordHistory stores an audit entry for the registration, the actor is taken
+// from the user management data of the current token.
+func (s *registrationService) recordHistory(ctx context.Context, registration entity.Registration, action string, fromStatus string, note string, userData map[string]interface{}, tx *gorm.DB) error {
+	_, err := s.registrationHistoryRepository.Create(ctx, newRegistrationHistory(registration, action, fromStatus, note, userData), tx)
+	return err
+}
+
+// newRegistrationHistory builds the history entry of a status change, the
+// actor is taken from the user management data of the request
+func newRegistrationHistory(registration entity.Registration, action string, fromStatus string, note string, userData map[string]interface{}) entity.RegistrationHistory {
+	history := entity.RegistrationHistory{
+		ID:             uuid.New(),
+		RegistrationID: registration.ID.String(),
+		Action:         action,
+		FromStatus:     fromStatus,
+		ToStatus:       registration.Status,
+		Note:           note,
+	}
+
+	if userData != nil {
+		history.ActorID, _ = userData["id"].(string)
+		history.ActorName, _ = userData["name"].(string)
+		history.ActorEmail, _ = userData["email"].(string)
+		history.ActorRole, _ = userData["role"].(string)
+	}
+
+	return history
+}
+
+func statusText(status string) string {
+	if status == "REJECTED" {
+		return "rejected"
+	}
+	return "approved"
+}
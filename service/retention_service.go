@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"registration-service/config"
+	"registration-service/dto"
+	"registration-service/helper"
+	"registration-service/repository"
+	"strings"
+	"time"
+
+	storageService "github.com/SIM-MBKM/filestorage/storage"
+	"gorm.io/gorm"
+)
+
+// PSEUDONYM_PREFIX marks a field replaced by the retention job
+const PSEUDONYM_PREFIX = "anon-"
+
+var errPseudonymKeyMissing = errors.New("RETENTION_PSEUDONYM_KEY is not set, registrations can't be anonymized")
+
+type retentionService struct {
+	retentionRepository           repository.RetentionRepository
+	registrationRepository        repository.RegistrationRepository
+	registrationHistoryRepository repository.RegistrationHistoryRepository
+	documentVersionRepository     repository.DocumentVersionRepository
+	fileService                   *FileService
+	retentionYears                int64
+	batchSize                     int
+	pseudonymKey                  []byte
+}
+
+// RetentionService anonymizes registrations once their retention period is
+// over. The personal fields get pseudonyms, the same value always gets the
+// same pseudonym so the statistics per student or advisor stay right, and the
+// documents are purged.
+type RetentionService interface {
+	RetentionReport(ctx context.Context, request dto.RetentionReportRequest, pagReq dto.PaginationRequest, tx *gorm.DB) (dto.RetentionReportResponse, dto.PaginationResponse, error)
+	AnonymizeDueRegistrations(ctx context.Context, now time.Time, dryRun bool) (dto.RetentionRunResponse, error)
+}
+
+func NewRetentionService(retentionRepository repository.RetentionRepository, registrationRepository repository.RegistrationRepository, registrationHistoryRepository repository.RegistrationHistoryRepository, documentVersionRepository repository.DocumentVersionRepository, storageConfig config.StorageConfig, retentionConfig config.RetentionConfig, config *storageService.Config, tokenManager *storageService.CacheTokenManager) RetentionService {
+	batchSize := int(retentionConfig.BatchSize)
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	return &retentionService{
+		retentionRepository:           retentionRepository,
+		registrationRepository:        registrationRepository,
+		registrationHistoryRepository: registrationHistoryRepository,
+		documentVersionRepository:     documentVersionRepository,
+		fileService:                   NewFileService(storageConfig, config, tokenManager),
+		retentionYears:                retentionConfig.RetentionYears,
+		batchSize:                     batchSize,
+		pseudonymKey:                  []byte(retentionConfig.PseudonymKey),
+	}
+}
+
+// cutoff is the retention date a registration has to be older than to be due
+func (s *retentionService) cutoff(now time.Time) time.Time {
+	return now.AddDate(-int(s.retentionYears), 0, 0)
+}
+
+// RetentionReport lists the registrations due for anonymization, or with
+// status anonymized the ones already anonymized
+func (s *retentionService) RetentionReport(ctx context.Context, request dto.RetentionReportRequest, pagReq dto.PaginationRequest, tx *gorm.DB) (dto.RetentionReportResponse, dto.PaginationResponse, error) {
+	if request.Status == "" {
+		request.Status = dto.RETENTION_STATUS_DUE
+	}
+
+	response := dto.RetentionReportResponse{
+		Status:         request.Status,
+		RetentionYears: s.retentionYears,
+		Cutoff:         s.cutoff(time.Now()),
+		Registrations:  []dto.RetentionReportItem{},
+	}
+
+	var candidates []repository.RetentionCandidate
+	var total int64
+	var err error
+	switch request.Status {
+	case dto.RETENTION_STATUS_DUE:
+		candidates, total, err = s.retentionRepository.IndexDue(ctx, response.Cutoff, pagReq, tx)
+	case dto.RETENTION_STATUS_ANONYMIZED:
+		candidates, total, err = s.retentionRepository.IndexAnonymized(ctx, pagReq, tx)
+	default:
+		return dto.RetentionReportResponse{}, dto.PaginationResponse{}, errors.New("status must be due or anonymized")
+	}
+	if err != nil {
+		return dto.RetentionReportResponse{}, dto.PaginationResponse{}, err
+	}
+
+	for _, candidate := range candidates {
+		response.Registrations = append(response.Registrations, s.toRetentionReportItem(candidate))
+	}
+
+	return response, helper.MetaDataPagination(total, pagReq), nil
+}
+
+// AnonymizeDueRegistrations anonymizes up to a batch of due registrations.
+// Documents go first, a registration whose files can't be deleted is left as
+// it is and tried again on the next run, the run reads on past it so it
+// doesn't hold up the others. With dryRun nothing is changed and the response
+// lists what would have been anonymized.
+func (s *retentionService) AnonymizeDueRegistrations(ctx context.Context, now time.Time, dryRun bool) (dto.RetentionRunResponse, error) {
+	response := dto.RetentionRunResponse{
+		DryRun:     dryRun,
+		Cutoff:     s.cutoff(now),
+		Anonymized: []dto.RetentionReportItem{},
+		Skipped:    []string{},
+	}
+
+	if !dryRun && len(s.pseudonymKey) == 0 {
+		return response, errPseudonymKeyMissing
+	}
+
+	for len(response.Anonymized) < s.batchSize {
+		// anonymized registrations drop out of the due ones, the skipped ones
+		// stay in front
+		limit := s.batchSize - len(response.Anonymized)
+		candidates, _, err := s.retentionRepository.IndexDue(ctx, response.Cutoff, dto.PaginationRequest{Offset: len(response.Skipped), Limit: limit}, nil)
+		if err != nil {
+			return response, err
+		}
+
+		for _, candidate := range candidates {
+			item := s.toRetentionReportItem(candidate)
+			if dryRun {
+				response.Anonymized = append(response.Anonymized, item)
+				continue
+			}
+
+			anonymizedAt := time.Now()
+			err = s.anonymize(ctx, candidate.RegistrationID, anonymizedAt)
+			if err != nil {
+				log.Printf("failed to anonymize registration %s: %v", candidate.RegistrationID, err)
+				response.Skipped = append(response.Skipped, candidate.RegistrationID)
+				continue
+			}
+
+			item.AnonymizedAt = &anonymizedAt
+			response.Anonymized = append(response.Anonymized, item)
+		}
+
+		if dryRun || len(candidates) < limit {
+			break
+		}
+	}
+
+	return response, nil
+}
+
+func (s *retentionService) anonymize(ctx context.Context, id string, anonymizedAt time.Time) error {
+	return s.registrationRepository.WithTransaction(ctx, func(tx *gorm.DB) error {
+		// the lock keeps the registration from being edited or deleted while
+		// its files are deleted and its fields pseudonymized
+		registration, err := s.registrationRepository.Lock(ctx, id, tx)
+		if err != nil {
+			return err
+		}
+
+		histories, err := s.registrationHistoryRepository.FindByRegistrationID(ctx, id, tx)
+		if err != nil {
+			return err
+		}
+
+		err = deleteDocumentFiles(ctx, s.fileService, s.documentVersionRepository, registration.Document)
+		if err != nil {
+			return err
+		}
+
+		registration.UserID = s.pseudonym(registration.UserID)
+		registration.UserName = s.pseudonym(registration.UserName)
+		registration.UserNRP = s.pseudonym(registration.UserNRP)
+		registration.AcademicAdvisorID = s.pseudonym(registration.AcademicAdvisorID)
+		registration.AcademicAdvisor = s.pseudonym(registration.AcademicAdvisor)
+		registration.AcademicAdvisorEmail = s.pseudonym(registration.AcademicAdvisorEmail)
+		registration.MentorName = s.pseudonym(registration.MentorName)
+		registration.MentorEmail = s.pseudonym(registration.MentorEmail)
+		registration.WithdrawalReason = ""
+		registration.AnonymizedAt = &anonymizedAt
+
+		// notes can name people, e.g. the advisors of a reassignment
+		for i := range histories {
+			histories[i].ActorID = s.pseudonym(histories[i].ActorID)
+			histories[i].ActorName = s.pseudonym(histories[i].ActorName)
+			histories[i].ActorEmail = s.pseudonym(histories[i].ActorEmail)
+			histories[i].Note = ""
+		}
+
+		return s.retentionRepository.Anonymize(ctx, registration, histories, tx)
+	})
+}
+
+// pseudonym derives a stable pseudonym from the value with a keyed hash, it
+// can't be turned back into the value without the key
+func (s *retentionService) pseudonym(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" || strings.HasPrefix(value, PSEUDONYM_PREFIX) {
+		return value
+	}
+
+	mac := hmac.New(sha256.New, s.pseudonymKey)
+	mac.Write([]byte(value))
+	return PSEUDONYM_PREFIX + hex.EncodeToString(mac.Sum(nil))[:16]
+}
+
+func (s *retentionService) toRetentionReportItem(candidate repository.RetentionCandidate) dto.RetentionReportItem {
+	return dto.RetentionReportItem{
+		RegistrationID:   candidate.RegistrationID,
+		ActivityID:       candidate.ActivityID,
+		ActivityName:     candidate.ActivityName,
+		AcademicPeriodID: candidate.AcademicPeriodID,
+		Status:           candidate.Status,
+		DocumentCount:    candidate.DocumentCount,
+		RetainedFrom:     candidate.RetainedFrom,
+		DueAt:            candidate.RetainedFrom.AddDate(int(s.retentionYears), 0, 0),
+		AnonymizedAt:     candidate.AnonymizedAt,
+	}
+}
+
+// RetentionJob runs AnonymizeDueRegistrations in the background, once when it
+// starts and then every interval until ctx is done
+type RetentionJob struct {
+	retentionService RetentionService
+	interval         time.Duration
+	dryRun           bool
+}
+
+func NewRetentionJob(retentionService RetentionService, retentionConfig config.RetentionConfig) *RetentionJob {
+	interval := time.Duration(retentionConfig.IntervalMinutes) * time.Minute
+	if interval <= 0 {
+		interval = 24 * time.Hour
+	}
+
+	return &RetentionJob{
+		retentionService: retentionService,
+		interval:         interval,
+		dryRun:           retentionConfig.DryRun,
+	}
+}
+
+func (j *RetentionJob) Start(ctx context.Context) {
+	runEvery(ctx, j.interval, j.run)
+}
+
+func (j *RetentionJob) run(ctx context.Context) {
+	result, err := j.retentionService.AnonymizeDueRegistrations(ctx, time.Now(), j.dryRun)
+	if err != nil {
+		log.Printf("retention run failed: %v", err)
+		return
+	}
+
+	if j.dryRun {
+		for _, item := range result.Anonymized {
+			log.Printf("retention dry run: registration %s due since %s would be anonymized", item.RegistrationID, item.DueAt.Format(time.DateOnly))
+		}
+		return
+	}
+
+	if len(result.Anonymized) > 0 || len(result.Skipped) > 0 {
+		log.Printf("retention anonymized %d registrations, %d skipped", len(result.Anonymized), len(result.Skipped))
+	}
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+var ErrMalwareDetected = errors.New("file rejected by malware scanner")
+
+// FileScanner inspects an uploaded file before it reaches the storage
+type FileScanner interface {
+	Scan(ctx context.Context, file io.Reader) error
+}
+
+func NewFileScanner(scanner string, clamAVAddress string, timeout time.Duration) FileScanner {
+	switch strings.ToLower(scanner) {
+	case "clamav", "clamd":
+		return NewClamAVScanner(clamAVAddress, timeout)
+	default:
+		return noopScanner{}
+	}
+}
+
+type noopScanner struct{}
+
+func (noopScanner) Scan(ctx context.Context, file io.Reader) error {
+	return nil
+}
+
+// clamAVScanner streams the file to a clamd daemon using the INSTREAM command
+type clamAVScanner struct {
+	network string
+	address string
+	timeout time.Duration
+}
+
+const clamAVChunkSize = 32 * 1024
+
+// NewClamAVScanner accepts addresses like tcp://localhost:3310 or
+// unix:///var/run/clamav/clamd.ctl, a bare host:port is treated as tcp.
+func NewClamAVScanner(address string, timeout time.Duration) FileScanner {
+	network := "tcp"
+	if strings.HasPrefix(address, "unix://") {
+		network = "unix"
+		address = strings.TrimPrefix(address, "unix://")
+	} else {
+		address = strings.TrimPrefix(address, "tcp://")
+	}
+
+	return &clamAVScanner{network: network, address: address, timeout: timeout}
+}
+
+func (s *clamAVScanner) Scan(ctx context.Context, file io.Reader) error {
+	dialer := net.Dialer{Timeout: s.timeout}
+	conn, err := dialer.DialContext(ctx, s.network, s.address)
+	if err != nil {
+		return fmt.Errorf("malware scanner unavailable: %w", err)
+	}
+	defer conn.Close()
+
+	if s.timeout > 0 {
+		conn.SetDeadline(time.Now().Add(s.timeout))
+	}
+
+	if _, err = conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return fmt.Errorf("malware scanner unavailable: %w", err)
+	}
+
+	buffer := make([]byte, clamAVChunkSize)
+	size := make([]byte, 4)
+	for {
+		n, readErr := file.Read(buffer)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size, uint32(n))
+			if _, err = conn.Write(size); err != nil {
+				return fmt.Errorf("malware scanner unavailable: %w", err)
+			}
+			if _, err = conn.Write(buffer[:n]); err != nil {
+				return fmt.Errorf("malware scanner unavailable: %w", err)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+
+	// a zero length chunk ends the stream
+	if _, err = conn.Write([]byte{0, 0, 0, 0}); err != nil {
+		return fmt.Errorf("malware scanner unavailable: %w", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadBytes(0)
+	if err != nil && err != io.EOF {
+		return fmt.Errorf("malware scanner unavailable: %w", err)
+	}
+	result := string(bytes.TrimRight(reply, "\x00\n"))
+
+	switch {
+	case strings.HasSuffix(result, "OK"):
+		return nil
+	case strings.HasSuffix(result, "FOUND"):
+		return fmt.Errorf("%w: %s", ErrMalwareDetected, strings.TrimSpace(strings.TrimPrefix(strings.TrimSuffix(result, "FOUND"), "stream:")))
+	default:
+		return fmt.Errorf("malware scanner error: %s", result)
+	}
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// runEvery calls run right away and then every interval in the background
+// until ctx is done, a run still going when the next one is due delays it
+func runEvery(ctx context.Context, interval time.Duration, run func(ctx context.Context)) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			run(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// localStorage keeps documents on the local filesystem, it is meant for
+// development and tests where no cloud credentials are available.
+type localStorage struct {
+	directory string
+}
+
+func NewLocalStorage(directory string) DocumentStorage {
+	return &localStorage{directory: directory}
+}
+
+func (s *localStorage) Upload(ctx context.Context, file *multipart.FileHeader) (string, error) {
+	src, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	err = os.MkdirAll(s.directory, 0o750)
+	if err != nil {
+		return "", err
+	}
+
+	fileID := uuid.New().String() + strings.ToLower(filepath.Ext(file.Filename))
+	dst, err := os.OpenFile(filepath.Join(s.directory, fileID), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o640)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = io.Copy(dst, src)
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(filepath.Join(s.directory, fileID))
+		return "", err
+	}
+
+	return fileID, nil
+}
+
+func (s *localStorage) Download(ctx context.Context, fileID string) (FileObject, error) {
+	filePath, err := s.path(fileID)
+	if err != nil {
+		return FileObject{}, err
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return FileObject{}, errors.New("file not found")
+		}
+		return FileObject{}, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return FileObject{}, err
+	}
+
+	return FileObject{
+		Content:     file,
+		ContentType: mime.TypeByExtension(filepath.Ext(fileID)),
+		Size:        info.Size(),
+	}, nil
+}
+
+func (s *localStorage) Delete(ctx context.Context, fileID string) error {
+	filePath, err := s.path(fileID)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(filePath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// path rejects ids that would point outside the storage directory
+func (s *localStorage) path(fileID string) (string, error) {
+	if fileID == "" || fileID != filepath.Base(fileID) || fileID == "." || fileID == ".." {
+		return "", errors.New("invalid file id")
+	}
+
+	return filepath.Join(s.directory, fileID), nil
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"registration-service/config"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// s3Storage talks to any S3 compatible object storage (AWS, MinIO, ...)
+// using Signature Version 4 over plain HTTP.
+type s3Storage struct {
+	endpoint     *url.URL
+	region       string
+	bucket       string
+	accessKey    string
+	secretKey    string
+	usePathStyle bool
+	prefix       string
+	client       *http.Client
+}
+
+func NewS3Storage(storageConfig config.StorageConfig) DocumentStorage {
+	endpoint, err := url.Parse(storageConfig.S3Endpoint)
+	if err != nil || endpoint.Host == "" {
+		endpoint = &url.URL{Scheme: "https", Host: "s3.amazonaws.com"}
+	}
+
+	return &s3Storage{
+		endpoint:     endpoint,
+		region:       storageConfig.S3Region,
+		bucket:       storageConfig.S3Bucket,
+		accessKey:    storageConfig.S3AccessKey,
+		secretKey:    storageConfig.S3SecretKey,
+		usePathStyle: storageConfig.S3UsePathStyle,
+		prefix:       storageConfig.S3ObjectPrefix,
+		client:       &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+func (s *s3Storage) Upload(ctx context.Context, file *multipart.FileHeader) (string, error) {
+	src, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	key := s.prefix + uuid.New().String() + strings.ToLower(filepath.Ext(file.Filename))
+
+	req, err := s.newRequest(ctx, http.MethodPut, key, src)
+	if err != nil {
+		return "", err
+	}
+	req.ContentLength = file.Size
+	if contentType := file.Header.Get("Content-Type"); contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	s.sign(req)
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("s3 upload failed with status %d", res.StatusCode)
+	}
+
+	return key, nil
+}
+
+func (s *s3Storage) Download(ctx context.Context, fileID string) (FileObject, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, fileID, nil)
+	if err != nil {
+		return FileObject{}, err
+	}
+	s.sign(req)
+
+	return doDownload(s.client, req)
+}
+
+func (s *s3Storage) Delete(ctx context.Context, fileID string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, fileID, nil)
+	if err != nil {
+		return err
+	}
+	s.sign(req)
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	// S3 answers 204 even when the key does not exist
+	if res.StatusCode != http.StatusNoContent && res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("s3 delete failed with status %d", res.StatusCode)
+	}
+
+	return nil
+}
+
+func (s *s3Storage) newRequest(ctx context.Context, method string, key string, body io.Reader) (*http.Request, error) {
+	objectURL := *s.endpoint
+	if s.usePathStyle {
+		objectURL.Path = path.Join("/", s.endpoint.Path, s.bucket, key)
+	} else {
+		objectURL.Host = s.bucket + "." + s.endpoint.Host
+		objectURL.Path = path.Join("/", s.endpoint.Path, key)
+	}
+
+	return http.NewRequestWithContext(ctx, method, objectURL.String(), body)
+}
+
+// sign adds the AWS Signature Version 4 headers, the payload is sent unsigned
+// so uploads can be streamed.
+func (s *s3Storage) sign(req *http.Request) {
+	now := time.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := "UNSIGNED-PAYLOAD"
+
+	req.Header.Set("Host", req.URL.Host)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaderNames := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	if req.Header.Get("Content-Type") != "" {
+		signedHeaderNames = append(signedHeaderNames, "content-type")
+	}
+	sort.Strings(signedHeaderNames)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range signedHeaderNames {
+		value := req.Header.Get(name)
+		if name == "host" {
+			value = req.URL.Host
+		}
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+	signedHeaders := strings.Join(signedHeaderNames, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", date, s.region)
+	hashedRequest := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(hashedRequest[:]),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+	signingKey = hmacSHA256(signingKey, s.region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature,
+	))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
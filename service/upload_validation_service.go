@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"registration-service/config"
+	"registration-service/entity"
+	"strings"
+	"time"
+)
+
+// UploadedFile is what the validation learned about a file, it is stored on
+// the document next to the storage id.
+type UploadedFile struct {
+	Checksum    string
+	ContentType string
+	Size        int64
+}
+
+type uploadValidator struct {
+	maxSizeBytes     int64
+	allowedMimeTypes []string
+	scanner          FileScanner
+}
+
+type UploadValidator interface {
+	Validate(ctx context.Context, requirement entity.DocumentRequirement, file *multipart.FileHeader) (UploadedFile, error)
+}
+
+func NewUploadValidator(uploadConfig config.UploadConfig) UploadValidator {
+	return &uploadValidator{
+		maxSizeBytes:     uploadConfig.MaxSizeBytes,
+		allowedMimeTypes: uploadConfig.AllowedMimeTypes,
+		scanner:          NewFileScanner(uploadConfig.Scanner, uploadConfig.ClamAVAddress, time.Duration(uploadConfig.ClamAVTimeout)*time.Second),
+	}
+}
+
+// Validate checks the size and the sniffed content type of the file against
+// the requirement of its document type, falling back to the configured
+// defaults, then computes the checksum and runs the malware scanner.
+func (v *uploadValidator) Validate(ctx context.Context, requirement entity.DocumentRequirement, file *multipart.FileHeader) (UploadedFile, error) {
+	if file == nil {
+		return UploadedFile{}, fmt.Errorf("%s file is required", requirement.DocumentType)
+	}
+
+	maxSize := v.maxSizeBytes
+	if requirement.MaxSizeBytes > 0 {
+		maxSize = requirement.MaxSizeBytes
+	}
+
+	if maxSize > 0 && file.Size > maxSize {
+		return UploadedFile{}, fmt.Errorf("%s exceeds the maximum size of %d bytes", requirement.DocumentType, maxSize)
+	}
+
+	allowed := v.allowedMimeTypes
+	if requirement.AllowedMimeTypes != "" {
+		allowed = strings.Split(requirement.AllowedMimeTypes, ",")
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return UploadedFile{}, err
+	}
+	defer src.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(src, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return UploadedFile{}, err
+	}
+
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head[:n]))
+	if !mimeTypeAllowed(contentType, allowed) {
+		return UploadedFile{}, fmt.Errorf("%s has unsupported file type %s, allowed types: %s", requirement.DocumentType, contentType, strings.Join(allowed, ", "))
+	}
+
+	if _, err = src.Seek(0, io.SeekStart); err != nil {
+		return UploadedFile{}, err
+	}
+
+	// hash while streaming to the scanner so the file is only read once more
+	hash := sha256.New()
+	counter := &countingReader{reader: io.TeeReader(src, hash)}
+	reader := io.Reader(counter)
+	if maxSize > 0 {
+		reader = io.LimitReader(counter, maxSize+1)
+	}
+
+	err = v.scanner.Scan(ctx, reader)
+	if err != nil {
+		return UploadedFile{}, err
+	}
+
+	// drain whatever the scanner did not read
+	if _, err = io.Copy(io.Discard, reader); err != nil {
+		return UploadedFile{}, err
+	}
+
+	if maxSize > 0 && counter.size > maxSize {
+		return UploadedFile{}, fmt.Errorf("%s exceeds the maximum size of %d bytes", requirement.DocumentType, maxSize)
+	}
+
+	return UploadedFile{
+		Checksum:    hex.EncodeToString(hash.Sum(nil)),
+		ContentType: contentType,
+		Size:        counter.size,
+	}, nil
+}
+
+func mimeTypeAllowed(contentType string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+
+	for _, mimeType := range allowed {
+		mimeType = strings.ToLower(strings.TrimSpace(mimeType))
+		if mimeType == contentType {
+			return true
+		}
+		// allow wildcards such as image/*
+		if strings.HasSuffix(mimeType, "/*") && strings.HasPrefix(contentType, strings.TrimSuffix(mimeType, "*")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type countingReader struct {
+	reader io.Reader
+	size   int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.size += int64(n)
+	return n, err
+}
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"errors"
+	"fmt"
 	"log"
 	"strings"
 
@@ -17,6 +19,7 @@ const (
 	GET_USER_ROLE_ENDPOINT           = "api/v1/user/service/users/me/role"
 	GET_USER_DATA_ENDPOINT           = "api/v1/user/service/users/me"
 	GET_DOSEN_DATA_BY_EMAIL_ENDPOINT = "api/v1/user/service/by-email/"
+	GET_STUDENT_ADVISOR_ENDPOINT     = "api/v1/user/service/advisor/by-nrp/"
 )
 
 func NewUserManagementService(baseURI string, asyncURIs []string) *UserManagementService {
@@ -149,6 +152,42 @@ func (s *UserManagementService) GetDosenDataByEmail(email string, method string,
 		"nip":          dosen["nrp"],
 		"name":         dosen["name"],
 		"email":        dosen["email"],
+		"role":         dosen["role"],
 	}
 	return dosenData
 }
+
+// GetStudentAdvisor returns the academic advisor assigned to the student, in
+// the same shape as GetDosenDataByEmail. It is nil without an error when the
+// student has no advisor assigned.
+func (s *UserManagementService) GetStudentAdvisor(nrp string, method string, token string) (map[string]interface{}, error) {
+	// split token
+	tokenParts := strings.Split(token, " ")
+	if len(tokenParts) != 2 {
+		return nil, errors.New("invalid token")
+	}
+
+	token = tokenParts[1]
+
+	endpoint := GET_STUDENT_ADVISOR_ENDPOINT + nrp
+
+	res, err := s.baseService.Request(method, endpoint, nil, token)
+	if err != nil {
+		log.Printf("failed to get the academic advisor of %s: %v", nrp, err)
+		return nil, fmt.Errorf("failed to get the academic advisor of the student: %w", err)
+	}
+
+	advisor, ok := res["data"].(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+
+	return map[string]interface{}{
+		"id":           advisor["auth_user_id"],
+		"auth_user_id": advisor["auth_user_id"],
+		"nip":          advisor["nrp"],
+		"name":         advisor["name"],
+		"email":        advisor["email"],
+		"role":         advisor["role"],
+	}, nil
+}
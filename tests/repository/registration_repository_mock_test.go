@@ -97,11 +97,12 @@ func TestRegistrationRepository_Index_WithFilters(t *testing.T) {
 
 	// Setup test data
 	ctx := context.Background()
+	approved := true
 	filter := dto.FilterRegistrationRequest{
 		ActivityName:         "Test Activity",
 		UserNRP:              "12345",
 		AcademicAdvisorEmail: "advisor@example.com",
-		ApprovalStatus:       true,
+		ApprovalStatus:       &approved,
 	}
 	pagReq := dto.PaginationRequest{
 		Limit:  10,
@@ -124,6 +125,45 @@ func TestRegistrationRepository_Index_WithFilters(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestRegistrationRepository_Index_WithUnapprovedFilter(t *testing.T) {
+	// Create mock repository
+	mockRepo := new(repository_mock.MockRegistrationRepository)
+
+	// Setup test data
+	ctx := context.Background()
+	approved := false
+	semesterMin := 5
+	filter := dto.FilterRegistrationRequest{
+		UserName:       "user",
+		ApprovalStatus: &approved,
+		Statuses:       []string{entity.REGISTRATION_STATUS_ACTIVE, entity.REGISTRATION_STATUS_WITHDRAWAL_REQUESTED},
+		SemesterMin:    &semesterMin,
+		Search:         "activity",
+		SortBy:         "user_name",
+		SortDirection:  "asc",
+	}
+	pagReq := dto.PaginationRequest{
+		Limit:  10,
+		Offset: 0,
+	}
+
+	expectedRegistrations := []entity.Registration{createMockRegistration()}
+	expectedTotal := int64(1)
+
+	// Setup mock behavior
+	mockRepo.On("Index", ctx, mock.Anything, pagReq, filter).Return(expectedRegistrations, expectedTotal, nil)
+
+	// Call method
+	registrations, total, err := mockRepo.Index(ctx, nil, pagReq, filter)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, expectedRegistrations, registrations)
+	assert.Equal(t, expectedTotal, total)
+	assert.False(t, *filter.ApprovalStatus)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestRegistrationRepository_Update_Error(t *testing.T) {
 	// Create mock repository
 	mockRepo := new(repository_mock.MockRegistrationRepository)
@@ -188,6 +228,30 @@ func TestRegistrationRepository_FindByNRP_Error(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestRegistrationRepository_FindActiveByNRP(t *testing.T) {
+	// Create mock repository
+	mockRepo := new(repository_mock.MockRegistrationRepository)
+
+	// Setup test data
+	ctx := context.Background()
+	nrp := "12345"
+	registrations := []entity.Registration{
+		{ID: uuid.New(), ActivityID: "activity-1", UserNRP: nrp, Status: entity.REGISTRATION_STATUS_ACTIVE},
+		{ID: uuid.New(), ActivityID: "activity-2", UserNRP: nrp, Status: entity.REGISTRATION_STATUS_WITHDRAWAL_REQUESTED},
+	}
+
+	// Setup mock behavior
+	mockRepo.On("FindActiveByNRP", ctx, nrp, mock.Anything).Return(registrations, nil)
+
+	// Call method
+	result, err := mockRepo.FindActiveByNRP(ctx, nrp, nil)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestRegistrationRepository_FindByActivityIDAndNRP_Error(t *testing.T) {
 	// Create mock repository
 	mockRepo := new(repository_mock.MockRegistrationRepository)
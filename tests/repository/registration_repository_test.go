@@ -0,0 +1,204 @@
+package repository_test
+
+import (
+	"context"
+	"regexp"
+	"registration-service/dto"
+	"registration-service/entity"
+	repository_mock "registration-service/mocks/repository"
+	"registration-service/repository"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// These tests run the real repository on a sqlmock database, sqlmock fails a
+// statement it doesn't expect so they also pin down the transactions used.
+
+func expectFindRegistration(mock sqlmock.Sqlmock, id string, lockVersion int64) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "registrations" WHERE id = $1`)).
+		WithArgs(id, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "lock_version"}).AddRow(id, lockVersion))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "documents" WHERE "documents"."registration_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "registration_id"}))
+}
+
+func TestRegistrationRepository_Update_UsesCallerTransaction(t *testing.T) {
+	sqldb, db, mock := repository_mock.DbMock(t)
+	defer sqldb.Close()
+
+	repo := repository.NewRegistrationRepository(db)
+	id := uuid.New()
+	registration := createMockRegistration()
+	registration.ID = id
+	registration.LockVersion = 3
+
+	// one transaction only, a second BEGIN would fail the expectations
+	mock.ExpectBegin()
+	expectFindRegistration(mock, id.String(), 3)
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "registrations" SET`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectRollback()
+
+	err := repo.WithTransaction(context.Background(), func(tx *gorm.DB) error {
+		err := repo.Update(context.Background(), id.String(), registration, tx)
+		assert.NoError(t, err)
+
+		// roll back the caller's transaction, the update has to go with it
+		return gorm.ErrInvalidTransaction
+	})
+
+	assert.ErrorIs(t, err, gorm.ErrInvalidTransaction)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRegistrationRepository_Update_OwnTransaction(t *testing.T) {
+	sqldb, db, mock := repository_mock.DbMock(t)
+	defer sqldb.Close()
+
+	repo := repository.NewRegistrationRepository(db)
+	id := uuid.New()
+	registration := createMockRegistration()
+	registration.ID = id
+
+	mock.ExpectBegin()
+	expectFindRegistration(mock, id.String(), 0)
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "registrations" SET`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.Update(context.Background(), id.String(), registration, nil)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRegistrationRepository_Destroy_UsesCallerTransaction(t *testing.T) {
+	sqldb, db, mock := repository_mock.DbMock(t)
+	defer sqldb.Close()
+
+	repo := repository.NewRegistrationRepository(db)
+	id := uuid.New().String()
+
+	mock.ExpectBegin()
+	expectFindRegistration(mock, id, 0)
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "registrations" SET "deleted_at"=NOW()`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.WithTransaction(context.Background(), func(tx *gorm.DB) error {
+		return repo.Destroy(context.Background(), id, tx)
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRegistrationRepository_Update_StaleLockVersion(t *testing.T) {
+	sqldb, db, mock := repository_mock.DbMock(t)
+	defer sqldb.Close()
+
+	repo := repository.NewRegistrationRepository(db)
+	id := uuid.New()
+	registration := createMockRegistration()
+	registration.ID = id
+	registration.LockVersion = 2
+
+	// somebody else updated the row to version 3 after the client read it
+	mock.ExpectBegin()
+	expectFindRegistration(mock, id.String(), 3)
+	mock.ExpectExec(`UPDATE "registrations" SET .* WHERE id = \$\d+ AND lock_version = \$\d+`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err := repo.Update(context.Background(), id.String(), registration, nil)
+
+	assert.ErrorIs(t, err, repository.ErrStaleVersion)
+	assert.ErrorIs(t, err, repository.ErrConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRegistrationRepository_LockDeleted_LocksTheRow(t *testing.T) {
+	sqldb, db, mock := repository_mock.DbMock(t)
+	defer sqldb.Close()
+
+	repo := repository.NewRegistrationRepository(db)
+	id := uuid.New().String()
+
+	mock.ExpectQuery(`SELECT \* FROM "registrations" WHERE id = \$1 AND registrations.deleted_at IS NOT NULL .* FOR UPDATE$`).
+		WithArgs(id, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "deleted_at"}).AddRow(id, time.Now()))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "documents" WHERE "documents"."registration_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "registration_id"}))
+
+	registration, err := repo.LockDeleted(context.Background(), id, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, id, registration.ID.String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRegistrationRepository_Lock_LocksTheRow(t *testing.T) {
+	sqldb, db, mock := repository_mock.DbMock(t)
+	defer sqldb.Close()
+
+	repo := repository.NewRegistrationRepository(db)
+	id := uuid.New().String()
+
+	mock.ExpectQuery(`SELECT \* FROM "registrations" WHERE id = \$1 AND "registrations"."deleted_at" IS NULL .* FOR UPDATE$`).
+		WithArgs(id, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "documents" WHERE "documents"."registration_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "registration_id"}))
+
+	registration, err := repo.Lock(context.Background(), id, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, id, registration.ID.String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRegistrationRepository_FindDeletedBefore_ReadsPastCursor(t *testing.T) {
+	sqldb, db, mock := repository_mock.DbMock(t)
+	defer sqldb.Close()
+
+	repo := repository.NewRegistrationRepository(db)
+	before := time.Now()
+	after := dto.Cursor{CreatedAt: before.Add(-48 * time.Hour), ID: uuid.New().String()}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE registrations.deleted_at IS NOT NULL AND registrations.deleted_at < $1 AND (registrations.deleted_at, registrations.id) > ($2, $3) ORDER BY registrations.deleted_at ASC, registrations.id ASC LIMIT $4`)).
+		WithArgs(before, after.CreatedAt, after.ID, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	registrations, err := repo.FindDeletedBefore(context.Background(), before, &after, 10, nil)
+
+	assert.NoError(t, err)
+	assert.Empty(t, registrations)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRegistrationRepository_FindActiveByNRP_LeavesOutDrafts(t *testing.T) {
+	sqldb, db, mock := repository_mock.DbMock(t)
+	defer sqldb.Close()
+
+	repo := repository.NewRegistrationRepository(db)
+
+	// a draft overlapping the new activity doesn't hold its period, the
+	// per-period limit doesn't count it either
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE user_nrp = $1 AND status NOT IN ($2,$3) AND lo_validation <> $4 AND academic_advisor_validation <> $5`)).
+		WithArgs("5025201001", entity.REGISTRATION_STATUS_WITHDRAWN, entity.REGISTRATION_STATUS_DRAFT, "REJECTED", "REJECTED").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(uuid.New().String(), entity.REGISTRATION_STATUS_ACTIVE))
+
+	registrations, err := repo.FindActiveByNRP(context.Background(), "5025201001", nil)
+
+	assert.NoError(t, err)
+	assert.Len(t, registrations, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,146 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"registration-service/config"
+	"registration-service/dto"
+	"registration-service/entity"
+	repository_mock "registration-service/mocks/repository"
+	"registration-service/service"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// DocumentReviewServiceTestSuite runs the review and version access of the
+// real document service for an advisor who stands in for another one, and the
+// registration checks of a document update
+type DocumentReviewServiceTestSuite struct {
+	suite.Suite
+	mockDocumentRepo          *repository_mock.MockDocumentRepository
+	mockRegistrationRepo      *repository_mock.MockRegistrationRepository
+	mockDocumentVersionRepo   *repository_mock.MockDocumentVersionRepository
+	mockAdvisorDelegationRepo *repository_mock.MockAdvisorDelegationRepository
+	userManagement            *httptest.Server
+	service                   service.DocumentService
+	document                  entity.Document
+}
+
+func (suite *DocumentReviewServiceTestSuite) SetupTest() {
+	suite.mockDocumentRepo = new(repository_mock.MockDocumentRepository)
+	suite.mockRegistrationRepo = new(repository_mock.MockRegistrationRepository)
+	suite.mockDocumentVersionRepo = new(repository_mock.MockDocumentVersionRepository)
+	suite.mockAdvisorDelegationRepo = new(repository_mock.MockAdvisorDelegationRepository)
+
+	suite.userManagement = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{
+			"auth_user_id": "delegate-1",
+			"role":         "DOSEN PEMBIMBING",
+			"email":        "delegate@example.com",
+			"name":         "Delegate",
+		}})
+	}))
+
+	suite.service = service.NewDocumentService(
+		suite.mockDocumentRepo,
+		suite.mockRegistrationRepo,
+		new(repository_mock.MockDocumentRequirementRepository),
+		suite.mockDocumentVersionRepo,
+		suite.mockAdvisorDelegationRepo,
+		config.UploadConfig{},
+		config.DownloadConfig{},
+		config.StorageConfig{Driver: service.STORAGE_DRIVER_LOCAL, LocalDirectory: suite.T().TempDir()},
+		suite.userManagement.URL+"/",
+		"",
+		nil,
+		nil,
+		nil,
+	)
+
+	registration := entity.Registration{
+		ID:                   uuid.New(),
+		UserID:               "student-1",
+		UserNRP:              "5025201001",
+		AcademicAdvisorEmail: "advisor@example.com",
+		Status:               entity.REGISTRATION_STATUS_ACTIVE,
+	}
+	suite.document = entity.Document{
+		ID:             uuid.New(),
+		RegistrationID: registration.ID.String(),
+		DocumentType:   entity.DOCUMENT_TYPE_ACCEPTANCE_LETTER,
+		ReviewStatus:   entity.DOCUMENT_REVIEW_PENDING,
+	}
+	suite.mockDocumentRepo.On("FindByID", mock.Anything, suite.document.ID.String(), mock.Anything).Return(suite.document, nil)
+	suite.mockRegistrationRepo.On("FindByID", mock.Anything, registration.ID.String(), mock.Anything).Return(registration, nil)
+}
+
+func (suite *DocumentReviewServiceTestSuite) TearDownTest() {
+	suite.userManagement.Close()
+}
+
+// delegateFor lets the test user stand in for the advisor
+func (suite *DocumentReviewServiceTestSuite) delegateFor(advisorEmails ...string) {
+	delegations := []entity.AdvisorDelegation{}
+	for _, email := range advisorEmails {
+		delegations = append(delegations, entity.AdvisorDelegation{
+			ID:            uuid.New(),
+			AdvisorEmail:  email,
+			DelegateEmail: "delegate@example.com",
+			StartsAt:      time.Now().AddDate(0, 0, -1),
+			EndsAt:        time.Now().AddDate(0, 0, 7),
+		})
+	}
+	suite.mockAdvisorDelegationRepo.On("FindActiveByDelegateEmail", mock.Anything, "delegate@example.com", mock.Anything, mock.Anything).Return(delegations, nil)
+}
+
+func (suite *DocumentReviewServiceTestSuite) TestDelegateReviewsDocument() {
+	suite.delegateFor("Advisor@Example.com")
+	suite.mockDocumentRepo.On("Update", mock.Anything, suite.document.ID.String(), mock.MatchedBy(func(document entity.Document) bool {
+		return document.ReviewStatus == entity.DOCUMENT_REVIEW_ACCEPTED && document.ReviewerEmail == "delegate@example.com"
+	}), mock.Anything).Return(nil)
+
+	err := suite.service.ReviewDocument(context.Background(), suite.document.ID.String(), dto.DocumentReviewRequest{Status: entity.DOCUMENT_REVIEW_ACCEPTED}, "Bearer x", nil)
+
+	suite.NoError(err)
+	suite.mockDocumentRepo.AssertExpectations(suite.T())
+}
+
+func (suite *DocumentReviewServiceTestSuite) TestAdvisorWithoutDelegationCannotReview() {
+	suite.delegateFor("other@example.com")
+
+	err := suite.service.ReviewDocument(context.Background(), suite.document.ID.String(), dto.DocumentReviewRequest{Status: entity.DOCUMENT_REVIEW_ACCEPTED}, "Bearer x", nil)
+
+	suite.EqualError(err, "Unauthorized")
+	suite.mockDocumentRepo.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *DocumentReviewServiceTestSuite) TestDelegateSeesDocumentVersions() {
+	suite.delegateFor("advisor@example.com")
+	suite.mockDocumentVersionRepo.On("FindByDocumentID", mock.Anything, suite.document.ID.String(), mock.Anything).Return([]entity.DocumentVersion{}, nil)
+
+	versions, err := suite.service.FindDocumentVersions(context.Background(), suite.document.ID.String(), "Bearer x", nil)
+
+	suite.NoError(err)
+	suite.Len(versions, 1)
+}
+
+func (suite *DocumentReviewServiceTestSuite) TestUpdateRefusesAnotherRegistration() {
+	request := dto.UpdateDocumentRequest{RegistrationID: uuid.New().String()}
+
+	err := suite.service.UpdateDocument(context.Background(), suite.document.ID.String(), request, nil, "Bearer x", nil)
+
+	suite.EqualError(err, "document does not belong to the registration")
+	suite.mockRegistrationRepo.AssertNotCalled(suite.T(), "FindByID", mock.Anything, request.RegistrationID, mock.Anything)
+	suite.mockDocumentRepo.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDocumentReviewServiceSuite(t *testing.T) {
+	suite.Run(t, new(DocumentReviewServiceTestSuite))
+}
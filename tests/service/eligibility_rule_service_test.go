@@ -0,0 +1,167 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"registration-service/dto"
+	"registration-service/entity"
+	repository_mock "registration-service/mocks/repository"
+	"registration-service/service"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// EligibilityRuleServiceTestSuite runs the rule evaluation and the scope
+// resolution of the real eligibility rule service
+type EligibilityRuleServiceTestSuite struct {
+	suite.Suite
+	mockEligibilityRuleRepo *repository_mock.MockEligibilityRuleRepository
+	service                 service.EligibilityRuleService
+}
+
+func (suite *EligibilityRuleServiceTestSuite) SetupTest() {
+	suite.mockEligibilityRuleRepo = new(repository_mock.MockEligibilityRuleRepository)
+	suite.service = service.NewEligibilityRuleService(suite.mockEligibilityRuleRepo)
+}
+
+func (suite *EligibilityRuleServiceTestSuite) TestEvaluateMinSemester() {
+	rule := entity.EligibilityRule{Rule: entity.ELIGIBILITY_RULE_MIN_SEMESTER, Value: 5, Severity: entity.ELIGIBILITY_RESULT_FAIL}
+
+	cases := []struct {
+		semester int
+		expected string
+	}{
+		{semester: 0, expected: entity.ELIGIBILITY_RESULT_WARN},
+		{semester: 4, expected: entity.ELIGIBILITY_RESULT_FAIL},
+		{semester: 5, expected: entity.ELIGIBILITY_RESULT_PASS},
+		{semester: 7, expected: entity.ELIGIBILITY_RESULT_PASS},
+	}
+
+	for _, c := range cases {
+		outcome, reason := service.EvaluateEligibilityRule(service.EligibilityInput{Semester: c.semester}, rule)
+		suite.Equal(c.expected, outcome, "semester %d", c.semester)
+		suite.NotEmpty(reason)
+	}
+}
+
+func (suite *EligibilityRuleServiceTestSuite) TestEvaluateMinTotalSKS() {
+	rule := entity.EligibilityRule{Rule: entity.ELIGIBILITY_RULE_MIN_TOTAL_SKS, Value: 90, Severity: entity.ELIGIBILITY_RESULT_FAIL}
+
+	outcome, _ := service.EvaluateEligibilityRule(service.EligibilityInput{}, rule)
+	suite.Equal(entity.ELIGIBILITY_RESULT_WARN, outcome)
+
+	outcome, reason := service.EvaluateEligibilityRule(service.EligibilityInput{TotalSKS: 89}, rule)
+	suite.Equal(entity.ELIGIBILITY_RESULT_FAIL, outcome)
+	suite.Contains(reason, "89")
+
+	outcome, _ = service.EvaluateEligibilityRule(service.EligibilityInput{TotalSKS: 90}, rule)
+	suite.Equal(entity.ELIGIBILITY_RESULT_PASS, outcome)
+}
+
+func (suite *EligibilityRuleServiceTestSuite) TestEvaluateMaxConcurrentProgramsSkipsDraftsAndTheSameActivity() {
+	rule := entity.EligibilityRule{Rule: entity.ELIGIBILITY_RULE_MAX_CONCURRENT_PROGRAMS, Value: 2, Severity: entity.ELIGIBILITY_RESULT_FAIL}
+	input := service.EligibilityInput{
+		ActivityID: "activity-new",
+		Registrations: []entity.Registration{
+			{ActivityID: "activity-1", Status: entity.REGISTRATION_STATUS_ACTIVE},
+			{ActivityID: "activity-2", Status: entity.REGISTRATION_STATUS_DRAFT},
+			{ActivityID: "activity-new", Status: entity.REGISTRATION_STATUS_ACTIVE},
+		},
+	}
+
+	outcome, _ := service.EvaluateEligibilityRule(input, rule)
+	suite.Equal(entity.ELIGIBILITY_RESULT_PASS, outcome)
+
+	input.Registrations = append(input.Registrations, entity.Registration{ActivityID: "activity-3", Status: entity.REGISTRATION_STATUS_ACTIVE})
+	outcome, _ = service.EvaluateEligibilityRule(input, rule)
+	suite.Equal(entity.ELIGIBILITY_RESULT_FAIL, outcome)
+}
+
+func (suite *EligibilityRuleServiceTestSuite) TestEvaluateAdvisorApprovalHistory() {
+	rule := entity.EligibilityRule{Rule: entity.ELIGIBILITY_RULE_ADVISOR_APPROVAL_HISTORY, Value: 1, Severity: entity.ELIGIBILITY_RESULT_FAIL}
+
+	outcome, _ := service.EvaluateEligibilityRule(service.EligibilityInput{Registrations: []entity.Registration{{AcademicAdvisorValidation: "PENDING"}}}, rule)
+	suite.Equal(entity.ELIGIBILITY_RESULT_FAIL, outcome)
+
+	outcome, _ = service.EvaluateEligibilityRule(service.EligibilityInput{Registrations: []entity.Registration{{AcademicAdvisorValidation: "APPROVED"}}}, rule)
+	suite.Equal(entity.ELIGIBILITY_RESULT_PASS, outcome)
+}
+
+func (suite *EligibilityRuleServiceTestSuite) TestEvaluateWarnSeverityTurnsFailureIntoWarning() {
+	rule := entity.EligibilityRule{Rule: entity.ELIGIBILITY_RULE_MIN_SEMESTER, Value: 5, Severity: entity.ELIGIBILITY_RESULT_WARN}
+
+	outcome, _ := service.EvaluateEligibilityRule(service.EligibilityInput{Semester: 3}, rule)
+	suite.Equal(entity.ELIGIBILITY_RESULT_WARN, outcome)
+
+	outcome, _ = service.EvaluateEligibilityRule(service.EligibilityInput{Semester: 6}, rule)
+	suite.Equal(entity.ELIGIBILITY_RESULT_PASS, outcome)
+}
+
+func (suite *EligibilityRuleServiceTestSuite) TestEvaluateUnknownRuleWarns() {
+	outcome, _ := service.EvaluateEligibilityRule(service.EligibilityInput{}, entity.EligibilityRule{Rule: "removed_rule", Severity: entity.ELIGIBILITY_RESULT_FAIL})
+	suite.Equal(entity.ELIGIBILITY_RESULT_WARN, outcome)
+}
+
+func (suite *EligibilityRuleServiceTestSuite) TestFindRulesForActivityMostSpecificEntryWins() {
+	rules := []entity.EligibilityRule{
+		{Rule: entity.ELIGIBILITY_RULE_MIN_SEMESTER, Value: 3, Enabled: true},
+		{Rule: entity.ELIGIBILITY_RULE_MIN_SEMESTER, LevelID: "level-1", Value: 4, Enabled: true},
+		{Rule: entity.ELIGIBILITY_RULE_MIN_SEMESTER, ProgramTypeID: "program-1", Value: 5, Enabled: true},
+		{Rule: entity.ELIGIBILITY_RULE_MIN_SEMESTER, ProgramTypeID: "program-1", LevelID: "level-1", Value: 6, Enabled: true},
+		{Rule: entity.ELIGIBILITY_RULE_MIN_TOTAL_SKS, Value: 90, Enabled: true},
+	}
+	suite.mockEligibilityRuleRepo.On("FindByScope", mock.Anything, "program-1", "level-1", mock.Anything).Return(rules, nil)
+
+	resolved, err := suite.service.FindRulesForActivity(context.Background(), "program-1", "level-1", nil)
+
+	suite.NoError(err)
+	suite.Len(resolved, 2)
+	suite.Equal(entity.ELIGIBILITY_RULE_MIN_SEMESTER, resolved[0].Rule)
+	suite.Equal(6, resolved[0].Value)
+	suite.Equal(entity.ELIGIBILITY_RULE_MIN_TOTAL_SKS, resolved[1].Rule)
+}
+
+func (suite *EligibilityRuleServiceTestSuite) TestFindRulesForActivityScopeCanDisableDefault() {
+	rules := []entity.EligibilityRule{
+		{Rule: entity.ELIGIBILITY_RULE_MIN_SEMESTER, Value: 3, Enabled: true},
+		{Rule: entity.ELIGIBILITY_RULE_MIN_SEMESTER, ProgramTypeID: "program-1", Value: 3, Enabled: false},
+	}
+	suite.mockEligibilityRuleRepo.On("FindByScope", mock.Anything, "program-1", "", mock.Anything).Return(rules, nil)
+
+	resolved, err := suite.service.FindRulesForActivity(context.Background(), "program-1", "", nil)
+
+	suite.NoError(err)
+	suite.Empty(resolved)
+}
+
+func (suite *EligibilityRuleServiceTestSuite) TestFindRulesForActivityError() {
+	suite.mockEligibilityRuleRepo.On("FindByScope", mock.Anything, "program-1", "", mock.Anything).Return([]entity.EligibilityRule{}, errors.New("database error"))
+
+	_, err := suite.service.FindRulesForActivity(context.Background(), "program-1", "", nil)
+
+	suite.Error(err)
+}
+
+func (suite *EligibilityRuleServiceTestSuite) TestCreateEligibilityRuleRejectsUnknownRule() {
+	err := suite.service.CreateEligibilityRule(context.Background(), dto.EligibilityRuleRequest{Rule: "no_such_rule", Value: 1}, nil)
+
+	suite.Error(err)
+	suite.mockEligibilityRuleRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *EligibilityRuleServiceTestSuite) TestCreateEligibilityRuleDefaults() {
+	suite.mockEligibilityRuleRepo.On("Create", mock.Anything, mock.MatchedBy(func(rule entity.EligibilityRule) bool {
+		return rule.Rule == entity.ELIGIBILITY_RULE_MIN_TOTAL_SKS && rule.Severity == entity.ELIGIBILITY_RESULT_FAIL && rule.Enabled
+	}), mock.Anything).Return(entity.EligibilityRule{}, nil)
+
+	err := suite.service.CreateEligibilityRule(context.Background(), dto.EligibilityRuleRequest{Rule: entity.ELIGIBILITY_RULE_MIN_TOTAL_SKS, Value: 90}, nil)
+
+	suite.NoError(err)
+	suite.mockEligibilityRuleRepo.AssertExpectations(suite.T())
+}
+
+func TestEligibilityRuleServiceSuite(t *testing.T) {
+	suite.Run(t, new(EligibilityRuleServiceTestSuite))
+}
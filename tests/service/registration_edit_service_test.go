@@ -0,0 +1,245 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"registration-service/config"
+	"registration-service/dto"
+	"registration-service/entity"
+	repository_mock "registration-service/mocks/repository"
+	"registration-service/service"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const editTestToken = "Bearer edit-test"
+
+// RegistrationEditServiceTestSuite runs PatchRegistration of the real
+// registration service, user management is a test server answering with the
+// user in user
+type RegistrationEditServiceTestSuite struct {
+	suite.Suite
+	mockRegistrationRepo        *repository_mock.MockRegistrationRepository
+	mockRegistrationHistoryRepo *repository_mock.MockRegistrationHistoryRepository
+	mockAdvisorDelegationRepo   *repository_mock.MockAdvisorDelegationRepository
+	userManagement              *httptest.Server
+	user                        map[string]interface{}
+}
+
+func (suite *RegistrationEditServiceTestSuite) SetupTest() {
+	suite.resetMocks()
+	suite.user = nil
+
+	suite.userManagement = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": suite.user})
+	}))
+}
+
+func (suite *RegistrationEditServiceTestSuite) resetMocks() {
+	suite.mockRegistrationRepo = new(repository_mock.MockRegistrationRepository)
+	suite.mockRegistrationHistoryRepo = new(repository_mock.MockRegistrationHistoryRepository)
+	suite.mockAdvisorDelegationRepo = new(repository_mock.MockAdvisorDelegationRepository)
+}
+
+func (suite *RegistrationEditServiceTestSuite) TearDownTest() {
+	suite.userManagement.Close()
+}
+
+func (suite *RegistrationEditServiceTestSuite) newService(reopenApprovals bool) service.RegistrationService {
+	return service.NewRegistrationService(
+		suite.mockRegistrationRepo,
+		new(repository_mock.MockDocumentRepository),
+		suite.mockRegistrationHistoryRepo,
+		new(repository_mock.MockDocumentRequirementRepository),
+		new(repository_mock.MockEligibilityRuleRepository),
+		new(repository_mock.MockAcademicPeriodRepository),
+		suite.mockAdvisorDelegationRepo,
+		config.UploadConfig{},
+		config.DownloadConfig{},
+		config.StorageConfig{Driver: service.STORAGE_DRIVER_LOCAL, LocalDirectory: suite.T().TempDir()},
+		config.ExportConfig{},
+		config.ImportConfig{},
+		config.AcademicRecordConfig{},
+		config.AdvisorConfig{Mode: service.ADVISOR_MODE_OFF},
+		config.RegistrationEditConfig{ReopenApprovals: reopenApprovals},
+		"secret",
+		suite.userManagement.URL+"/",
+		"", "", "", "",
+		nil,
+		nil,
+		nil,
+	)
+}
+
+func (suite *RegistrationEditServiceTestSuite) actAs(role string) {
+	suite.user = map[string]interface{}{
+		"auth_user_id": "user-1",
+		"nrp":          "5025201001",
+		"name":         "Test User",
+		"role":         role,
+		"email":        "user@example.com",
+	}
+}
+
+// registrationIn returns a registration of user-1, advised by the test
+// user, in the given edit state
+func registrationIn(state string) entity.Registration {
+	registration := entity.Registration{
+		ID:                        uuid.New(),
+		UserID:                    "user-1",
+		UserNRP:                   "5025201001",
+		AcademicAdvisorEmail:      "user@example.com",
+		MentorName:                "Mentor",
+		MentorEmail:               "mentor@example.com",
+		Semester:                  5,
+		TotalSKS:                  100,
+		Status:                    entity.REGISTRATION_STATUS_ACTIVE,
+		AcademicAdvisorValidation: "PENDING",
+		LOValidation:              "PENDING",
+	}
+
+	switch state {
+	case service.REGISTRATION_EDIT_STATE_DRAFT, service.REGISTRATION_EDIT_STATE_WITHDRAWAL_REQUESTED, service.REGISTRATION_EDIT_STATE_WITHDRAWN:
+		registration.Status = state
+	case service.REGISTRATION_EDIT_STATE_APPROVED:
+		registration.LOValidation = "APPROVED"
+	}
+
+	return registration
+}
+
+func patchRequest(suite *RegistrationEditServiceTestSuite, body string) dto.PatchRegistrationRequest {
+	var request dto.PatchRegistrationRequest
+	suite.Require().NoError(json.Unmarshal([]byte(body), &request))
+	return request
+}
+
+func (suite *RegistrationEditServiceTestSuite) expectRegistration(registration entity.Registration) {
+	suite.mockRegistrationRepo.On("FindByID", mock.Anything, registration.ID.String(), mock.Anything).Return(registration, nil)
+	suite.mockAdvisorDelegationRepo.On("FindActiveByDelegateEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]entity.AdvisorDelegation{}, nil)
+	suite.mockRegistrationHistoryRepo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(entity.RegistrationHistory{}, nil)
+}
+
+func (suite *RegistrationEditServiceTestSuite) TestEditMatrix() {
+	cases := []struct {
+		role    string
+		state   string
+		body    string
+		allowed bool
+	}{
+		{"MAHASISWA", service.REGISTRATION_EDIT_STATE_DRAFT, `{"semester": 6}`, true},
+		{"MAHASISWA", service.REGISTRATION_EDIT_STATE_SUBMITTED, `{"semester": 6}`, true},
+		{"MAHASISWA", service.REGISTRATION_EDIT_STATE_APPROVED, `{"semester": 6}`, false},
+		{"MAHASISWA", service.REGISTRATION_EDIT_STATE_APPROVED, `{"mentor_name": "Other Mentor"}`, true},
+		{"MAHASISWA", service.REGISTRATION_EDIT_STATE_WITHDRAWAL_REQUESTED, `{"mentor_name": "Other Mentor"}`, false},
+		{"MAHASISWA", service.REGISTRATION_EDIT_STATE_WITHDRAWN, `{"mentor_name": "Other Mentor"}`, false},
+		{"DOSEN PEMBIMBING", service.REGISTRATION_EDIT_STATE_DRAFT, `{"mentor_name": "Other Mentor"}`, false},
+		{"DOSEN PEMBIMBING", service.REGISTRATION_EDIT_STATE_SUBMITTED, `{"mentor_name": "Other Mentor"}`, true},
+		{"DOSEN PEMBIMBING", service.REGISTRATION_EDIT_STATE_SUBMITTED, `{"total_sks": 110}`, false},
+		{"DOSEN PEMBIMBING", service.REGISTRATION_EDIT_STATE_APPROVED, `{"mentor_name": "Other Mentor"}`, false},
+		{"LO-MBKM", service.REGISTRATION_EDIT_STATE_APPROVED, `{"semester": 6}`, true},
+		{"LO-MBKM", service.REGISTRATION_EDIT_STATE_WITHDRAWAL_REQUESTED, `{"mentor_email": "other@example.com"}`, true},
+		{"LO-MBKM", service.REGISTRATION_EDIT_STATE_WITHDRAWAL_REQUESTED, `{"semester": 6}`, false},
+		{"ADMIN", service.REGISTRATION_EDIT_STATE_WITHDRAWN, `{"mentor_name": "Other Mentor"}`, false},
+	}
+
+	for _, c := range cases {
+		suite.resetMocks()
+		suite.actAs(c.role)
+		registration := registrationIn(c.state)
+		suite.expectRegistration(registration)
+		suite.mockRegistrationRepo.On("Update", mock.Anything, registration.ID.String(), mock.Anything, mock.Anything).Return(nil)
+
+		err := suite.newService(false).PatchRegistration(context.Background(), registration.ID.String(), patchRequest(suite, c.body), editTestToken, nil)
+
+		if c.allowed {
+			suite.NoError(err, "%s %s %s", c.role, c.state, c.body)
+			suite.mockRegistrationRepo.AssertCalled(suite.T(), "Update", mock.Anything, registration.ID.String(), mock.Anything, mock.Anything)
+		} else {
+			suite.ErrorContains(err, "can't be changed", "%s %s %s", c.role, c.state, c.body)
+			suite.mockRegistrationRepo.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		}
+	}
+}
+
+func (suite *RegistrationEditServiceTestSuite) TestStudentChangeReopensApprovalsWhenConfigured() {
+	suite.actAs("MAHASISWA")
+	registration := registrationIn(service.REGISTRATION_EDIT_STATE_APPROVED)
+	registration.ApprovalStatus = true
+	suite.expectRegistration(registration)
+	suite.mockRegistrationRepo.On("Update", mock.Anything, registration.ID.String(), mock.MatchedBy(func(updated entity.Registration) bool {
+		return updated.Semester == 6 && updated.LOValidation == "PENDING" && !updated.ApprovalStatus
+	}), mock.Anything).Return(nil)
+
+	err := suite.newService(true).PatchRegistration(context.Background(), registration.ID.String(), patchRequest(suite, `{"semester": 6}`), editTestToken, nil)
+
+	suite.NoError(err)
+	suite.mockRegistrationRepo.AssertExpectations(suite.T())
+	suite.mockRegistrationHistoryRepo.AssertCalled(suite.T(), "Create", mock.Anything, mock.MatchedBy(func(history entity.RegistrationHistory) bool {
+		return history.Action == entity.REGISTRATION_HISTORY_APPROVAL_REOPENED
+	}), mock.Anything)
+}
+
+func (suite *RegistrationEditServiceTestSuite) TestExplicitNullClearsDraftField() {
+	suite.actAs("MAHASISWA")
+	registration := registrationIn(service.REGISTRATION_EDIT_STATE_DRAFT)
+	suite.expectRegistration(registration)
+	suite.mockRegistrationRepo.On("Update", mock.Anything, registration.ID.String(), mock.MatchedBy(func(updated entity.Registration) bool {
+		return updated.Semester == 0 && updated.TotalSKS == 100
+	}), mock.Anything).Return(nil)
+
+	err := suite.newService(false).PatchRegistration(context.Background(), registration.ID.String(), patchRequest(suite, `{"semester": null}`), editTestToken, nil)
+
+	suite.NoError(err)
+	suite.mockRegistrationRepo.AssertExpectations(suite.T())
+	// drafts keep no history
+	suite.mockRegistrationHistoryRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *RegistrationEditServiceTestSuite) TestExplicitNullRejectedOnceSubmitted() {
+	suite.actAs("MAHASISWA")
+	registration := registrationIn(service.REGISTRATION_EDIT_STATE_SUBMITTED)
+	suite.expectRegistration(registration)
+
+	for _, body := range []string{`{"semester": null}`, `{"total_sks": null}`, `{"mentor_name": null}`} {
+		err := suite.newService(false).PatchRegistration(context.Background(), registration.ID.String(), patchRequest(suite, body), editTestToken, nil)
+
+		suite.Error(err, body)
+	}
+	suite.mockRegistrationRepo.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *RegistrationEditServiceTestSuite) TestAbsentFieldsAreLeftAlone() {
+	suite.actAs("MAHASISWA")
+	registration := registrationIn(service.REGISTRATION_EDIT_STATE_SUBMITTED)
+	suite.expectRegistration(registration)
+	suite.mockRegistrationRepo.On("Update", mock.Anything, registration.ID.String(), mock.MatchedBy(func(updated entity.Registration) bool {
+		return updated.MentorName == "Other Mentor" && updated.MentorEmail == "mentor@example.com" && updated.Semester == 5 && updated.TotalSKS == 100
+	}), mock.Anything).Return(nil)
+
+	err := suite.newService(false).PatchRegistration(context.Background(), registration.ID.String(), patchRequest(suite, `{"mentor_name": "Other Mentor"}`), editTestToken, nil)
+
+	suite.NoError(err)
+	suite.mockRegistrationRepo.AssertExpectations(suite.T())
+}
+
+func (suite *RegistrationEditServiceTestSuite) TestOptionalTellsAbsentNullAndValueApart() {
+	request := patchRequest(suite, `{"semester": null, "total_sks": 120}`)
+
+	suite.True(request.Semester.Set)
+	suite.True(request.Semester.Null)
+	suite.True(request.TotalSKS.Set)
+	suite.False(request.TotalSKS.Null)
+	suite.Equal(120, request.TotalSKS.Value)
+	suite.False(request.MentorName.Set)
+}
+
+func TestRegistrationEditServiceSuite(t *testing.T) {
+	suite.Run(t, new(RegistrationEditServiceTestSuite))
+}
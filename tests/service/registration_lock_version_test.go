@@ -0,0 +1,82 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"registration-service/dto"
+	"registration-service/repository"
+	"registration-service/service"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// The If-Match checks run on the edit suite, the lock version of the
+// request is the one the controller read from If-Match
+
+func (suite *RegistrationEditServiceTestSuite) TestPatchRejectsStaleIfMatch() {
+	suite.actAs("MAHASISWA")
+	registration := registrationIn(service.REGISTRATION_EDIT_STATE_SUBMITTED)
+	registration.LockVersion = 3
+	suite.expectRegistration(registration)
+
+	request := patchRequest(suite, `{"mentor_name": "Other Mentor"}`)
+	stale := int64(2)
+	request.LockVersion = &stale
+
+	err := suite.newService(false).PatchRegistration(context.Background(), registration.ID.String(), request, editTestToken, nil)
+
+	suite.ErrorIs(err, service.ErrStaleVersion)
+	var staleErr *service.StaleVersionError
+	suite.True(errors.As(err, &staleErr))
+	suite.Equal(registration.ID.String(), staleErr.ID)
+	suite.mockRegistrationRepo.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *RegistrationEditServiceTestSuite) TestPatchAcceptsCurrentOrMissingIfMatch() {
+	suite.actAs("MAHASISWA")
+	registration := registrationIn(service.REGISTRATION_EDIT_STATE_SUBMITTED)
+	registration.LockVersion = 3
+	suite.expectRegistration(registration)
+	suite.mockRegistrationRepo.On("Update", mock.Anything, registration.ID.String(), mock.Anything, mock.Anything).Return(nil)
+
+	current := int64(3)
+	for _, version := range []*int64{&current, nil} {
+		request := patchRequest(suite, `{"mentor_name": "Other Mentor"}`)
+		request.LockVersion = version
+
+		err := suite.newService(false).PatchRegistration(context.Background(), registration.ID.String(), request, editTestToken, nil)
+
+		suite.NoError(err)
+	}
+	suite.mockRegistrationRepo.AssertNumberOfCalls(suite.T(), "Update", 2)
+}
+
+func (suite *RegistrationEditServiceTestSuite) TestPatchSurfacesConcurrentUpdate() {
+	suite.actAs("MAHASISWA")
+	registration := registrationIn(service.REGISTRATION_EDIT_STATE_SUBMITTED)
+	suite.expectRegistration(registration)
+	// the version matched on read but the row changed before the update
+	suite.mockRegistrationRepo.On("Update", mock.Anything, registration.ID.String(), mock.Anything, mock.Anything).
+		Return(repository.NewStaleVersionError("registration", registration.ID.String()))
+
+	err := suite.newService(false).PatchRegistration(context.Background(), registration.ID.String(), patchRequest(suite, `{"mentor_name": "Other Mentor"}`), editTestToken, nil)
+
+	suite.ErrorIs(err, service.ErrStaleVersion)
+	suite.ErrorIs(err, service.ErrConflict)
+	suite.mockRegistrationHistoryRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *RegistrationEditServiceTestSuite) TestLOApprovalRejectsStaleIfMatch() {
+	suite.actAs("LO-MBKM")
+	registration := registrationIn(service.REGISTRATION_EDIT_STATE_SUBMITTED)
+	registration.LockVersion = 5
+	suite.expectRegistration(registration)
+
+	stale := int64(4)
+	approval := dto.ApprovalRequest{ID: []string{registration.ID.String()}, Status: "APPROVED", LockVersion: &stale}
+
+	err := suite.newService(false).LORegistrationApproval(context.Background(), editTestToken, approval, nil)
+
+	suite.ErrorIs(err, service.ErrStaleVersion)
+	suite.mockRegistrationRepo.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -60,7 +60,11 @@ func (suite *RegistrationServiceTestSuite) SetupTest() {
 	suite.service = mockService
 }
 
+// mockRegistrationService reimplements part of the service on the mocks, the
+// embedded interface stands in for the unexported helpers of the interface,
+// which a test double outside the package can't implement
 type mockRegistrationService struct {
+	service.RegistrationService
 	registrationRepository      *repository_mock.MockRegistrationRepository
 	documentRepository          *repository_mock.MockDocumentRepository
 	userManagementService       *service_mock.MockUserManagementService
@@ -70,7 +74,7 @@ type mockRegistrationService struct {
 	monitoringManagementService *service_mock.MockMonitoringManagementService
 }
 
-func (s *mockRegistrationService) FindTotalRegistrationByAdvisorEmail(ctx context.Context, token string, tx *gorm.DB) (entity.RegistrationCount, error) {
+func (s *mockRegistrationService) FindTotalRegistrationByAdvisorEmail(ctx context.Context, token string, academicPeriodID string, tx *gorm.DB) (entity.RegistrationCount, error) {
 
 	userData := s.userManagementService.GetUserData("GET", token)
 
@@ -80,7 +84,7 @@ func (s *mockRegistrationService) FindTotalRegistrationByAdvisorEmail(ctx contex
 		return entity.RegistrationCount{}, errors.New("email is not a string")
 	}
 
-	registratoinCounter, err := s.registrationRepository.FindTotalRegistrationByAdvisorEmail(ctx, emailString, tx)
+	registratoinCounter, err := s.registrationRepository.FindTotalRegistrationByAdvisorEmail(ctx, emailString, academicPeriodID, tx)
 
 	if err != nil {
 		return entity.RegistrationCount{}, err
@@ -1406,6 +1410,58 @@ func (s *mockRegistrationService) CheckRegistrationEligibility(ctx context.Conte
 	}, nil
 }
 
+// errNotCovered is returned by the methods the test double leaves out, the
+// tests in this file don't call them
+var errNotCovered = errors.New("not covered by mockRegistrationService")
+
+func (s *mockRegistrationService) RequestWithdrawal(ctx context.Context, id string, request dto.WithdrawalRequest, token string, tx *gorm.DB) error {
+	return errNotCovered
+}
+
+func (s *mockRegistrationService) AdvisorWithdrawalApproval(ctx context.Context, token string, approval dto.ApprovalRequest, tx *gorm.DB) error {
+	return errNotCovered
+}
+
+func (s *mockRegistrationService) LOWithdrawalApproval(ctx context.Context, token string, approval dto.ApprovalRequest, tx *gorm.DB) error {
+	return errNotCovered
+}
+
+func (s *mockRegistrationService) FindRegistrationHistory(ctx context.Context, id string, token string, tx *gorm.DB) ([]dto.RegistrationHistoryResponse, error) {
+	return nil, errNotCovered
+}
+
+func (s *mockRegistrationService) CreateDraftRegistration(ctx context.Context, registration dto.CreateDraftRegistrationRequest, token string, tx *gorm.DB) (dto.GetRegistrationResponse, error) {
+	return dto.GetRegistrationResponse{}, errNotCovered
+}
+
+func (s *mockRegistrationService) SubmitRegistration(ctx context.Context, id string, token string, tx *gorm.DB) error {
+	return errNotCovered
+}
+
+func (s *mockRegistrationService) RegistrationDocumentsArchive(ctx context.Context, id string, token string, tx *gorm.DB) (service.DocumentArchive, error) {
+	return service.DocumentArchive{}, errNotCovered
+}
+
+func (s *mockRegistrationService) ActivityDocumentsArchive(ctx context.Context, activityID string, token string, tx *gorm.DB) (service.DocumentArchive, error) {
+	return service.DocumentArchive{}, errNotCovered
+}
+
+func (s *mockRegistrationService) ExportRegistrations(ctx context.Context, scope string, filter dto.FilterRegistrationRequest, request dto.RegistrationExportRequest, token string, tx *gorm.DB) (service.RegistrationExport, error) {
+	return service.RegistrationExport{}, errNotCovered
+}
+
+func (s *mockRegistrationService) ImportRegistrations(ctx context.Context, file *multipart.FileHeader, request dto.RegistrationImportRequest, token string, tx *gorm.DB) (dto.RegistrationImportResponse, error) {
+	return dto.RegistrationImportResponse{}, errNotCovered
+}
+
+func (s *mockRegistrationService) ReassignAdvisor(ctx context.Context, request dto.AdvisorReassignmentRequest, token string, tx *gorm.DB) (dto.AdvisorReassignmentResponse, error) {
+	return dto.AdvisorReassignmentResponse{}, errNotCovered
+}
+
+func (s *mockRegistrationService) PatchRegistration(ctx context.Context, id string, request dto.PatchRegistrationRequest, token string, tx *gorm.DB) error {
+	return errNotCovered
+}
+
 // TestRegistrationsDataAccessAuthorized tests successful authorization scenarios
 func (suite *RegistrationServiceTestSuite) TestRegistrationsDataAccessAuthorized() {
 	// Setup
@@ -3502,10 +3558,10 @@ func (suite *RegistrationServiceTestSuite) TestFindTotalRegistrationByAdvisorEma
 
 	// Setup mocks
 	suite.mockUserManagementService.On("GetUserData", "GET", token).Return(userData)
-	suite.mockRegistrationRepo.On("FindTotalRegistrationByAdvisorEmail", ctx, advisorEmail, mock.Anything).Return(expectedCount, nil)
+	suite.mockRegistrationRepo.On("FindTotalRegistrationByAdvisorEmail", ctx, advisorEmail, "", mock.Anything).Return(expectedCount, nil)
 
 	// Call the method
-	result, err := suite.service.FindTotalRegistrationByAdvisorEmail(ctx, token, nil)
+	result, err := suite.service.FindTotalRegistrationByAdvisorEmail(ctx, token, "", nil)
 
 	// Assertions
 	suite.NoError(err)
@@ -3533,7 +3589,7 @@ func (suite *RegistrationServiceTestSuite) TestFindTotalRegistrationByAdvisorEma
 	suite.mockUserManagementService.On("GetUserData", "GET", token).Return(userData)
 
 	// Call the method
-	result, err := suite.service.FindTotalRegistrationByAdvisorEmail(ctx, token, nil)
+	result, err := suite.service.FindTotalRegistrationByAdvisorEmail(ctx, token, "", nil)
 
 	// Assertions
 	suite.Error(err)
@@ -3562,10 +3618,10 @@ func (suite *RegistrationServiceTestSuite) TestFindTotalRegistrationByAdvisorEma
 
 	// Setup mocks
 	suite.mockUserManagementService.On("GetUserData", "GET", token).Return(userData)
-	suite.mockRegistrationRepo.On("FindTotalRegistrationByAdvisorEmail", ctx, advisorEmail, mock.Anything).Return(entity.RegistrationCount{}, expectedError)
+	suite.mockRegistrationRepo.On("FindTotalRegistrationByAdvisorEmail", ctx, advisorEmail, "", mock.Anything).Return(entity.RegistrationCount{}, expectedError)
 
 	// Call the method
-	result, err := suite.service.FindTotalRegistrationByAdvisorEmail(ctx, token, nil)
+	result, err := suite.service.FindTotalRegistrationByAdvisorEmail(ctx, token, "", nil)
 
 	// Assertions
 	suite.Error(err)
@@ -3586,7 +3642,7 @@ func (suite *RegistrationServiceTestSuite) TestFindTotalRegistrationByAdvisorEma
 	suite.mockUserManagementService.On("GetUserData", "GET", token).Return(nil)
 
 	// Call the method
-	result, err := suite.service.FindTotalRegistrationByAdvisorEmail(ctx, token, nil)
+	result, err := suite.service.FindTotalRegistrationByAdvisorEmail(ctx, token, "", nil)
 
 	// Assertions
 	suite.Error(err)
@@ -0,0 +1,338 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"registration-service/config"
+	"registration-service/entity"
+	repository_mock "registration-service/mocks/repository"
+	"registration-service/repository"
+	"registration-service/service"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+// RegistrationTrashServiceTestSuite runs the trash service on the real
+// registration repository over sqlmock, so the statements and their order
+// within the transactions are checked. Files live in a local storage directory.
+// The test server answers for user management and, for the eligibility check
+// of a restore, for activity management with the periods in activityStarts.
+type RegistrationTrashServiceTestSuite struct {
+	suite.Suite
+	sqldb                       *sql.DB
+	db                          *gorm.DB
+	sqlmock                     sqlmock.Sqlmock
+	mockRegistrationHistoryRepo *repository_mock.MockRegistrationHistoryRepository
+	mockDocumentVersionRepo     *repository_mock.MockDocumentVersionRepository
+	mockAcademicPeriodRepo      *repository_mock.MockAcademicPeriodRepository
+	mockEligibilityRuleRepo     *repository_mock.MockEligibilityRuleRepository
+	userManagement              *httptest.Server
+	activityStarts              map[string]time.Time
+	storageDirectory            string
+	service                     service.RegistrationTrashService
+}
+
+func (suite *RegistrationTrashServiceTestSuite) SetupTest() {
+	sqldb, db, sqlmock := repository_mock.DbMock(suite.T())
+	suite.sqldb = sqldb
+	suite.db = db
+	suite.sqlmock = sqlmock
+	suite.mockRegistrationHistoryRepo = new(repository_mock.MockRegistrationHistoryRepository)
+	suite.mockDocumentVersionRepo = new(repository_mock.MockDocumentVersionRepository)
+	suite.mockAcademicPeriodRepo = new(repository_mock.MockAcademicPeriodRepository)
+	suite.mockEligibilityRuleRepo = new(repository_mock.MockEligibilityRuleRepository)
+	suite.storageDirectory = suite.T().TempDir()
+	suite.activityStarts = map[string]time.Time{}
+
+	suite.userManagement = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/activity/filter") {
+			var filter map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&filter)
+			activityID, _ := filter["activity_id"].(string)
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": []interface{}{map[string]interface{}{
+				"id":              activityID,
+				"approval_status": "APPROVED",
+				"start_period":    suite.activityStarts[activityID].Format(time.RFC3339),
+				"months_duration": 4,
+			}}})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"auth_user_id": "admin-1", "role": "ADMIN", "email": "admin@example.com"}})
+	}))
+
+	suite.service = service.NewRegistrationTrashService(
+		repository.NewRegistrationRepository(db),
+		suite.mockRegistrationHistoryRepo,
+		suite.mockDocumentVersionRepo,
+		suite.mockEligibilityRuleRepo,
+		suite.mockAcademicPeriodRepo,
+		config.StorageConfig{Driver: service.STORAGE_DRIVER_LOCAL, LocalDirectory: suite.storageDirectory},
+		config.PurgeConfig{RetentionDays: 30, BatchSize: 1},
+		suite.userManagement.URL+"/",
+		suite.userManagement.URL+"/",
+		nil,
+		nil,
+		nil,
+	)
+}
+
+func (suite *RegistrationTrashServiceTestSuite) TearDownTest() {
+	suite.userManagement.Close()
+	suite.sqldb.Close()
+}
+
+// storedFile puts a document file into the storage directory
+func (suite *RegistrationTrashServiceTestSuite) storedFile() string {
+	fileID := uuid.New().String() + ".pdf"
+	suite.Require().NoError(os.WriteFile(filepath.Join(suite.storageDirectory, fileID), []byte("%PDF"), 0o600))
+	return fileID
+}
+
+func (suite *RegistrationTrashServiceTestSuite) fileExists(fileID string) bool {
+	_, err := os.Stat(filepath.Join(suite.storageDirectory, fileID))
+	return err == nil
+}
+
+func (suite *RegistrationTrashServiceTestSuite) expectCandidates(deletedAt time.Time, ids ...string) {
+	rows := sqlmock.NewRows([]string{"id", "deleted_at"})
+	for _, id := range ids {
+		rows.AddRow(id, deletedAt)
+	}
+	suite.sqlmock.ExpectQuery(`SELECT \* FROM "registrations" WHERE registrations.deleted_at IS NOT NULL AND registrations.deleted_at < \$1`).
+		WillReturnRows(rows)
+}
+
+// expectLock expects the row lock of a deleted registration, documents maps
+// document ids to file ids. Without a row the registration was restored.
+func (suite *RegistrationTrashServiceTestSuite) expectLock(id string, found bool, documents map[string]string) {
+	rows := sqlmock.NewRows([]string{"id", "deleted_at"})
+	if found {
+		rows.AddRow(id, time.Now().AddDate(0, 0, -40))
+	}
+	suite.sqlmock.ExpectQuery(`SELECT \* FROM "registrations" WHERE id = \$1 AND registrations.deleted_at IS NOT NULL .* FOR UPDATE$`).
+		WithArgs(id, 1).
+		WillReturnRows(rows)
+	if !found {
+		return
+	}
+
+	documentRows := sqlmock.NewRows([]string{"id", "registration_id", "file_storage_id"})
+	for documentID, fileID := range documents {
+		documentRows.AddRow(documentID, id, fileID)
+	}
+	suite.sqlmock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "documents" WHERE "documents"."registration_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(documentRows)
+}
+
+// expectLockedRegistration expects the row lock of a deleted registration of
+// the student 5025201001
+func (suite *RegistrationTrashServiceTestSuite) expectLockedRegistration(id string, status string, activityID string) {
+	suite.sqlmock.ExpectQuery(`SELECT \* FROM "registrations" WHERE id = \$1 AND registrations.deleted_at IS NOT NULL .* FOR UPDATE$`).
+		WithArgs(id, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "activity_id", "user_nrp", "lo_validation", "academic_advisor_validation", "deleted_at"}).
+			AddRow(id, status, activityID, "5025201001", "PENDING", "PENDING", time.Now().AddDate(0, 0, -3)))
+	suite.sqlmock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "documents" WHERE "documents"."registration_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "registration_id"}))
+}
+
+// expectEligibilityCheck expects the student's lock and the registrations
+// the eligibility check reads, active lists the other registrations as
+// id and activity id pairs
+func (suite *RegistrationTrashServiceTestSuite) expectEligibilityCheck(active ...[2]string) {
+	suite.sqlmock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	rows := sqlmock.NewRows([]string{"id", "activity_id", "activity_name", "user_nrp", "status"})
+	for _, registration := range active {
+		rows.AddRow(registration[0], registration[1], "Activity "+registration[1], "5025201001", entity.REGISTRATION_STATUS_ACTIVE)
+	}
+	suite.sqlmock.ExpectQuery(regexp.QuoteMeta(`WHERE user_nrp = $1 AND status NOT IN ($2,$3)`)).
+		WillReturnRows(rows)
+	suite.sqlmock.ExpectQuery(regexp.QuoteMeta(`WHERE activity_id = $1 AND user_nrp = $2`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+}
+
+func (suite *RegistrationTrashServiceTestSuite) expectRestore(id string) {
+	suite.sqlmock.ExpectExec(regexp.QuoteMeta(`UPDATE "registrations" SET "deleted_at"=$1,"updated_at"=$2 WHERE id = $3 AND deleted_at IS NOT NULL`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func (suite *RegistrationTrashServiceTestSuite) expectRestoredHistory(id string) {
+	suite.mockRegistrationHistoryRepo.On("Create", mock.Anything, mock.MatchedBy(func(history entity.RegistrationHistory) bool {
+		return history.RegistrationID == id && history.Action == entity.REGISTRATION_HISTORY_RESTORED
+	}), mock.Anything).Return(entity.RegistrationHistory{}, nil)
+}
+
+func (suite *RegistrationTrashServiceTestSuite) expectPurge(id string) {
+	suite.sqlmock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "document_versions"`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.sqlmock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "documents"`)).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.sqlmock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "registration_histories"`)).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.sqlmock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "registrations" WHERE id = $1 AND deleted_at IS NOT NULL`)).
+		WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func (suite *RegistrationTrashServiceTestSuite) TestPurgeDeletesFilesWhileHoldingTheLock() {
+	id := uuid.New().String()
+	documentID := uuid.New().String()
+	fileID := suite.storedFile()
+	suite.mockDocumentVersionRepo.On("FindByDocumentID", mock.Anything, documentID, mock.Anything).Return([]entity.DocumentVersion{}, nil)
+
+	suite.expectCandidates(time.Now().AddDate(0, 0, -40), id)
+	suite.sqlmock.ExpectBegin()
+	suite.expectLock(id, true, map[string]string{documentID: fileID})
+	suite.expectPurge(id)
+	suite.sqlmock.ExpectCommit()
+
+	result, err := suite.service.PurgeDeletedRegistrations(context.Background(), time.Now())
+
+	suite.NoError(err)
+	suite.Equal([]string{id}, result.Purged)
+	suite.Empty(result.Skipped)
+	suite.False(suite.fileExists(fileID))
+	suite.NoError(suite.sqlmock.ExpectationsWereMet())
+}
+
+func (suite *RegistrationTrashServiceTestSuite) TestPurgeKeepsFilesOfRestoredRegistration() {
+	id := uuid.New().String()
+	fileID := suite.storedFile()
+
+	// the registration was restored between reading the batch and the lock
+	suite.expectCandidates(time.Now().AddDate(0, 0, -40), id)
+	suite.sqlmock.ExpectBegin()
+	suite.expectLock(id, false, nil)
+	suite.sqlmock.ExpectRollback()
+	suite.expectCandidates(time.Now())
+
+	result, err := suite.service.PurgeDeletedRegistrations(context.Background(), time.Now())
+
+	suite.NoError(err)
+	suite.Empty(result.Purged)
+	suite.Empty(result.Skipped)
+	suite.True(suite.fileExists(fileID))
+	suite.mockDocumentVersionRepo.AssertNotCalled(suite.T(), "FindByDocumentID", mock.Anything, mock.Anything, mock.Anything)
+	suite.NoError(suite.sqlmock.ExpectationsWereMet())
+}
+
+func (suite *RegistrationTrashServiceTestSuite) TestPurgeReadsPastFailedRegistrations() {
+	failing := uuid.New().String()
+	next := uuid.New().String()
+	failingDocumentID := uuid.New().String()
+	deletedAt := time.Now().AddDate(0, 0, -40)
+	suite.mockDocumentVersionRepo.On("FindByDocumentID", mock.Anything, failingDocumentID, mock.Anything).Return([]entity.DocumentVersion{}, errors.New("storage unavailable"))
+
+	// the batch size is 1, the failing registration fills the first batch
+	suite.expectCandidates(deletedAt, failing)
+	suite.sqlmock.ExpectBegin()
+	suite.expectLock(failing, true, map[string]string{failingDocumentID: suite.storedFile()})
+	suite.sqlmock.ExpectRollback()
+
+	suite.sqlmock.ExpectQuery(regexp.QuoteMeta(`AND (registrations.deleted_at, registrations.id) > ($2, $3)`)).
+		WithArgs(sqlmock.AnyArg(), deletedAt, failing, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "deleted_at"}).AddRow(next, deletedAt))
+	suite.sqlmock.ExpectBegin()
+	suite.expectLock(next, true, nil)
+	suite.expectPurge(next)
+	suite.sqlmock.ExpectCommit()
+
+	result, err := suite.service.PurgeDeletedRegistrations(context.Background(), time.Now())
+
+	suite.NoError(err)
+	suite.Equal([]string{next}, result.Purged)
+	suite.Equal([]string{failing}, result.Skipped)
+	suite.NoError(suite.sqlmock.ExpectationsWereMet())
+}
+
+func (suite *RegistrationTrashServiceTestSuite) TestRestoreTakesTheLocksAndChecksEligibility() {
+	id := uuid.New().String()
+	suite.activityStarts["activity-1"] = time.Now().AddDate(0, 1, 0)
+	suite.activityStarts["activity-2"] = time.Now().AddDate(1, 0, 0)
+	suite.mockAcademicPeriodRepo.On("FindByDate", mock.Anything, mock.Anything, mock.Anything).Return(entity.AcademicPeriod{}, gorm.ErrRecordNotFound)
+	suite.mockEligibilityRuleRepo.On("FindByScope", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]entity.EligibilityRule{}, nil)
+	suite.expectRestoredHistory(id)
+
+	suite.sqlmock.ExpectBegin()
+	suite.expectLockedRegistration(id, entity.REGISTRATION_STATUS_ACTIVE, "activity-1")
+	suite.expectEligibilityCheck([2]string{uuid.New().String(), "activity-2"})
+	suite.expectRestore(id)
+	suite.sqlmock.ExpectCommit()
+
+	err := suite.service.RestoreRegistration(context.Background(), id, "Bearer admin", nil)
+
+	suite.NoError(err)
+	suite.mockRegistrationHistoryRepo.AssertExpectations(suite.T())
+	suite.NoError(suite.sqlmock.ExpectationsWereMet())
+}
+
+func (suite *RegistrationTrashServiceTestSuite) TestRestoreRefusesOverlappingRegistration() {
+	id := uuid.New().String()
+	// the student registered for an overlapping activity after the delete
+	suite.activityStarts["activity-1"] = time.Now().AddDate(0, 1, 0)
+	suite.activityStarts["activity-2"] = time.Now().AddDate(0, 2, 0)
+	suite.mockAcademicPeriodRepo.On("FindByDate", mock.Anything, mock.Anything, mock.Anything).Return(entity.AcademicPeriod{}, gorm.ErrRecordNotFound)
+	suite.mockEligibilityRuleRepo.On("FindByScope", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]entity.EligibilityRule{}, nil)
+
+	suite.sqlmock.ExpectBegin()
+	suite.expectLockedRegistration(id, entity.REGISTRATION_STATUS_ACTIVE, "activity-1")
+	suite.expectEligibilityCheck([2]string{uuid.New().String(), "activity-2"})
+	suite.sqlmock.ExpectRollback()
+
+	err := suite.service.RestoreRegistration(context.Background(), id, "Bearer admin", nil)
+
+	suite.ErrorIs(err, repository.ErrConflict)
+	suite.mockRegistrationHistoryRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything, mock.Anything)
+	suite.NoError(suite.sqlmock.ExpectationsWereMet())
+}
+
+func (suite *RegistrationTrashServiceTestSuite) TestRestoreUsesCallerTransaction() {
+	id := uuid.New().String()
+	suite.expectRestoredHistory(id)
+
+	// a draft holds no period, it is checked once it is submitted
+	suite.sqlmock.ExpectBegin()
+	suite.expectLockedRegistration(id, entity.REGISTRATION_STATUS_DRAFT, "activity-1")
+	suite.expectRestore(id)
+
+	tx := suite.db.Begin()
+	err := suite.service.RestoreRegistration(context.Background(), id, "Bearer admin", tx)
+
+	suite.NoError(err)
+	suite.mockRegistrationHistoryRepo.AssertExpectations(suite.T())
+	suite.NoError(suite.sqlmock.ExpectationsWereMet())
+}
+
+func (suite *RegistrationTrashServiceTestSuite) TestRestoreAfterPurgeFindsNothing() {
+	id := uuid.New().String()
+
+	// the purge held the lock and removed the row before the restore got it
+	suite.sqlmock.ExpectBegin()
+	suite.expectLock(id, false, nil)
+	suite.sqlmock.ExpectRollback()
+
+	err := suite.service.RestoreRegistration(context.Background(), id, "Bearer admin", nil)
+
+	suite.Error(err)
+	suite.mockRegistrationHistoryRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything, mock.Anything)
+	suite.NoError(suite.sqlmock.ExpectationsWereMet())
+}
+
+func TestRegistrationTrashServiceSuite(t *testing.T) {
+	suite.Run(t, new(RegistrationTrashServiceTestSuite))
+}
@@ -0,0 +1,178 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"registration-service/config"
+	"registration-service/dto"
+	"registration-service/entity"
+	repository_mock "registration-service/mocks/repository"
+	"registration-service/repository"
+	"registration-service/service"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+// RetentionServiceTestSuite runs the anonymization of the real retention
+// service, Anonymize is captured to look at the pseudonyms
+type RetentionServiceTestSuite struct {
+	suite.Suite
+	mockRetentionRepo           *repository_mock.MockRetentionRepository
+	mockRegistrationRepo        *repository_mock.MockRegistrationRepository
+	mockRegistrationHistoryRepo *repository_mock.MockRegistrationHistoryRepository
+	anonymized                  map[string]entity.Registration
+}
+
+func (suite *RetentionServiceTestSuite) SetupTest() {
+	suite.mockRetentionRepo = new(repository_mock.MockRetentionRepository)
+	suite.mockRegistrationRepo = new(repository_mock.MockRegistrationRepository)
+	suite.mockRegistrationHistoryRepo = new(repository_mock.MockRegistrationHistoryRepository)
+	suite.anonymized = map[string]entity.Registration{}
+
+	suite.mockRegistrationRepo.On("WithTransaction", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, fn func(tx *gorm.DB) error) error { return fn(nil) })
+	suite.mockRegistrationHistoryRepo.On("FindByRegistrationID", mock.Anything, mock.Anything, mock.Anything).Return([]entity.RegistrationHistory{}, nil)
+	suite.mockRetentionRepo.On("Anonymize", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			registration := args.Get(1).(entity.Registration)
+			suite.anonymized[registration.ID.String()] = registration
+		}).
+		Return(nil)
+}
+
+func (suite *RetentionServiceTestSuite) newService(pseudonymKey string, batchSize int64) service.RetentionService {
+	return service.NewRetentionService(
+		suite.mockRetentionRepo,
+		suite.mockRegistrationRepo,
+		suite.mockRegistrationHistoryRepo,
+		new(repository_mock.MockDocumentVersionRepository),
+		config.StorageConfig{Driver: service.STORAGE_DRIVER_LOCAL, LocalDirectory: suite.T().TempDir()},
+		config.RetentionConfig{RetentionYears: 5, BatchSize: batchSize, PseudonymKey: pseudonymKey},
+		nil,
+		nil,
+	)
+}
+
+// dueRegistration makes a registration due and returns its id
+func (suite *RetentionServiceTestSuite) dueRegistration(nrp string, email string) string {
+	registration := entity.Registration{
+		ID:                   uuid.New(),
+		UserID:               "user-" + nrp,
+		UserNRP:              nrp,
+		UserName:             "Student " + nrp,
+		AcademicAdvisorEmail: email,
+		MentorName:           "Mentor",
+	}
+	suite.mockRegistrationRepo.On("Lock", mock.Anything, registration.ID.String(), mock.Anything).Return(registration, nil)
+
+	return registration.ID.String()
+}
+
+func candidates(ids ...string) []repository.RetentionCandidate {
+	result := []repository.RetentionCandidate{}
+	for _, id := range ids {
+		result = append(result, repository.RetentionCandidate{RegistrationID: id, RetainedFrom: time.Now().AddDate(-6, 0, 0)})
+	}
+	return result
+}
+
+func (suite *RetentionServiceTestSuite) expectDue(offset int, limit int, ids ...string) {
+	suite.mockRetentionRepo.On("IndexDue", mock.Anything, mock.Anything, dto.PaginationRequest{Offset: offset, Limit: limit}, mock.Anything).
+		Return(candidates(ids...), int64(len(ids)), nil).Once()
+}
+
+func (suite *RetentionServiceTestSuite) TestPseudonymsAreStable() {
+	first := suite.dueRegistration("5025201001", "Advisor@Example.com")
+	second := suite.dueRegistration("5025201001", " advisor@example.com ")
+	other := suite.dueRegistration("5025201002", "other@example.com")
+	suite.expectDue(0, 10, first, second, other)
+
+	result, err := suite.newService("retention-key", 10).AnonymizeDueRegistrations(context.Background(), time.Now(), false)
+
+	suite.NoError(err)
+	suite.Len(result.Anonymized, 3)
+	suite.Empty(result.Skipped)
+
+	// the same student and advisor get the same pseudonym in every registration
+	suite.Equal(suite.anonymized[first].UserNRP, suite.anonymized[second].UserNRP)
+	suite.Equal(suite.anonymized[first].AcademicAdvisorEmail, suite.anonymized[second].AcademicAdvisorEmail)
+	suite.NotEqual(suite.anonymized[first].UserNRP, suite.anonymized[other].UserNRP)
+	suite.NotEqual(suite.anonymized[first].AcademicAdvisorEmail, suite.anonymized[other].AcademicAdvisorEmail)
+
+	pseudonym := suite.anonymized[first].UserNRP
+	suite.True(strings.HasPrefix(pseudonym, service.PSEUDONYM_PREFIX))
+	suite.NotContains(pseudonym, "5025201001")
+	suite.NotNil(suite.anonymized[first].AnonymizedAt)
+
+	// a later run with the same key, e.g. after a restart, derives the same one
+	again := suite.dueRegistration("5025201001", "advisor@example.com")
+	suite.expectDue(0, 10, again)
+
+	_, err = suite.newService("retention-key", 10).AnonymizeDueRegistrations(context.Background(), time.Now(), false)
+
+	suite.NoError(err)
+	suite.Equal(pseudonym, suite.anonymized[again].UserNRP)
+
+	// another key gives other pseudonyms
+	otherKey := suite.dueRegistration("5025201001", "advisor@example.com")
+	suite.expectDue(0, 10, otherKey)
+
+	_, err = suite.newService("another-key", 10).AnonymizeDueRegistrations(context.Background(), time.Now(), false)
+
+	suite.NoError(err)
+	suite.NotEqual(pseudonym, suite.anonymized[otherKey].UserNRP)
+}
+
+func (suite *RetentionServiceTestSuite) TestDryRunChangesNothing() {
+	first := suite.dueRegistration("5025201001", "advisor@example.com")
+	second := suite.dueRegistration("5025201002", "advisor@example.com")
+	suite.expectDue(0, 10, first, second)
+
+	// the dry run doesn't need the key, it derives no pseudonyms
+	result, err := suite.newService("", 10).AnonymizeDueRegistrations(context.Background(), time.Now(), true)
+
+	suite.NoError(err)
+	suite.True(result.DryRun)
+	suite.Len(result.Anonymized, 2)
+	suite.Equal(first, result.Anonymized[0].RegistrationID)
+	suite.Nil(result.Anonymized[0].AnonymizedAt)
+	suite.mockRegistrationRepo.AssertNotCalled(suite.T(), "Lock", mock.Anything, mock.Anything, mock.Anything)
+	suite.mockRetentionRepo.AssertNotCalled(suite.T(), "Anonymize", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *RetentionServiceTestSuite) TestRefusesToAnonymizeWithoutKey() {
+	_, err := suite.newService("", 10).AnonymizeDueRegistrations(context.Background(), time.Now(), false)
+
+	suite.Error(err)
+	suite.mockRetentionRepo.AssertNotCalled(suite.T(), "IndexDue", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	suite.mockRetentionRepo.AssertNotCalled(suite.T(), "Anonymize", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *RetentionServiceTestSuite) TestReadsPastFailedRegistrations() {
+	failing := uuid.New().String()
+	suite.mockRegistrationRepo.On("Lock", mock.Anything, failing, mock.Anything).Return(entity.Registration{}, errors.New("database error"))
+	next := suite.dueRegistration("5025201002", "advisor@example.com")
+
+	// the failing registration stays due and in front, the next page starts
+	// after it
+	suite.expectDue(0, 1, failing)
+	suite.expectDue(1, 1, next)
+
+	result, err := suite.newService("retention-key", 1).AnonymizeDueRegistrations(context.Background(), time.Now(), false)
+
+	suite.NoError(err)
+	suite.Equal([]string{failing}, result.Skipped)
+	suite.Len(result.Anonymized, 1)
+	suite.Equal(next, result.Anonymized[0].RegistrationID)
+	suite.mockRetentionRepo.AssertExpectations(suite.T())
+}
+
+func TestRetentionServiceSuite(t *testing.T) {
+	suite.Run(t, new(RetentionServiceTestSuite))
+}
@@ -12,28 +12,113 @@ import (
 	"gorm.io/gorm"
 	"registration-service/config"
 	"registration-service/controller"
+	"registration-service/migration"
 	"registration-service/repository"
 	"registration-service/service"
 )
 
 // Injectors from wire.go:
 
-func InitializeRegistration(db *gorm.DB, secretKey config.SecretKey, userManagementbaseURI config.UserManagementbaseURI, activityManagementbaseURI config.ActivityManagementbaseURI, matchingManagementbaseURI config.MatchingManagementbaseURI, monitoringManagementbaseURI config.MonitoringManagementbaseURI, brokerbaseURI config.BrokerbaseURI, asyncURIs config.AsyncURIs, config2 *storage.Config, tokenManager *storage.CacheTokenManager) (controller.RegistrationController, error) {
+func InitializeRegistration(db *gorm.DB, secretKey config.SecretKey, userManagementbaseURI config.UserManagementbaseURI, activityManagementbaseURI config.ActivityManagementbaseURI, matchingManagementbaseURI config.MatchingManagementbaseURI, monitoringManagementbaseURI config.MonitoringManagementbaseURI, brokerbaseURI config.BrokerbaseURI, asyncURIs config.AsyncURIs, uploadConfig config.UploadConfig, downloadConfig config.DownloadConfig, storageConfig config.StorageConfig, exportConfig config.ExportConfig, importConfig config.ImportConfig, academicRecordConfig config.AcademicRecordConfig, advisorConfig config.AdvisorConfig, registrationEditConfig config.RegistrationEditConfig, config2 *storage.Config, tokenManager *storage.CacheTokenManager) (controller.RegistrationController, error) {
 	registrationRepository := ProvideRegistrationRepository(db)
 	documentRepository := ProvideDocumentRepository(db)
-	registrationService := ProvideRegistrationService(registrationRepository, documentRepository, secretKey, userManagementbaseURI, activityManagementbaseURI, matchingManagementbaseURI, monitoringManagementbaseURI, brokerbaseURI, asyncURIs, config2, tokenManager)
+	registrationHistoryRepository := ProvideRegistrationHistoryRepository(db)
+	documentRequirementRepository := ProvideDocumentRequirementRepository(db)
+	eligibilityRuleRepository := ProvideEligibilityRuleRepository(db)
+	academicPeriodRepository := ProvideAcademicPeriodRepository(db)
+	advisorDelegationRepository := ProvideAdvisorDelegationRepository(db)
+	registrationService := ProvideRegistrationService(registrationRepository, documentRepository, registrationHistoryRepository, documentRequirementRepository, eligibilityRuleRepository, academicPeriodRepository, advisorDelegationRepository, uploadConfig, downloadConfig, storageConfig, exportConfig, importConfig, academicRecordConfig, advisorConfig, registrationEditConfig, secretKey, userManagementbaseURI, activityManagementbaseURI, matchingManagementbaseURI, monitoringManagementbaseURI, brokerbaseURI, asyncURIs, config2, tokenManager)
 	registrationController := ProvideRegistrationController(registrationService)
 	return registrationController, nil
 }
 
-func InitializeDocument(db *gorm.DB, config2 *storage.Config, tokenManager *storage.CacheTokenManager) (controller.DocumentController, error) {
+func InitializeDocument(db *gorm.DB, uploadConfig config.UploadConfig, downloadConfig config.DownloadConfig, storageConfig config.StorageConfig, userManagementbaseURI config.UserManagementbaseURI, brokerbaseURI config.BrokerbaseURI, asyncURIs config.AsyncURIs, config2 *storage.Config, tokenManager *storage.CacheTokenManager) (controller.DocumentController, error) {
 	documentRepository := ProvideDocumentRepository(db)
 	registrationRepository := ProvideRegistrationRepository(db)
-	documentService := ProvideDocumentService(documentRepository, registrationRepository, config2, tokenManager)
+	documentRequirementRepository := ProvideDocumentRequirementRepository(db)
+	documentVersionRepository := ProvideDocumentVersionRepository(db)
+	advisorDelegationRepository := ProvideAdvisorDelegationRepository(db)
+	documentService := ProvideDocumentService(documentRepository, registrationRepository, documentRequirementRepository, documentVersionRepository, advisorDelegationRepository, uploadConfig, downloadConfig, storageConfig, userManagementbaseURI, brokerbaseURI, asyncURIs, config2, tokenManager)
 	documentController := ProvideDocumentController(documentService)
 	return documentController, nil
 }
 
+func InitializeDocumentRequirement(db *gorm.DB) (controller.DocumentRequirementController, error) {
+	documentRequirementRepository := ProvideDocumentRequirementRepository(db)
+	documentRequirementService := ProvideDocumentRequirementService(documentRequirementRepository)
+	documentRequirementController := ProvideDocumentRequirementController(documentRequirementService)
+	return documentRequirementController, nil
+}
+
+func InitializeEligibilityRule(db *gorm.DB) (controller.EligibilityRuleController, error) {
+	eligibilityRuleRepository := ProvideEligibilityRuleRepository(db)
+	eligibilityRuleService := ProvideEligibilityRuleService(eligibilityRuleRepository)
+	eligibilityRuleController := ProvideEligibilityRuleController(eligibilityRuleService)
+	return eligibilityRuleController, nil
+}
+
+func InitializeAcademicPeriod(db *gorm.DB) (controller.AcademicPeriodController, error) {
+	academicPeriodRepository := ProvideAcademicPeriodRepository(db)
+	academicPeriodService := ProvideAcademicPeriodService(academicPeriodRepository)
+	academicPeriodController := ProvideAcademicPeriodController(academicPeriodService)
+	return academicPeriodController, nil
+}
+
+func InitializeAdvisorDelegation(db *gorm.DB, userManagementbaseURI config.UserManagementbaseURI, asyncURIs config.AsyncURIs) (controller.AdvisorDelegationController, error) {
+	advisorDelegationRepository := ProvideAdvisorDelegationRepository(db)
+	advisorDelegationService := ProvideAdvisorDelegationService(advisorDelegationRepository, userManagementbaseURI, asyncURIs)
+	advisorDelegationController := ProvideAdvisorDelegationController(advisorDelegationService)
+	return advisorDelegationController, nil
+}
+
+func InitializeRegistrationTrash(db *gorm.DB, storageConfig config.StorageConfig, purgeConfig config.PurgeConfig, userManagementbaseURI config.UserManagementbaseURI, activityManagementbaseURI config.ActivityManagementbaseURI, asyncURIs config.AsyncURIs, config2 *storage.Config, tokenManager *storage.CacheTokenManager) (controller.RegistrationTrashController, error) {
+	registrationRepository := ProvideRegistrationRepository(db)
+	registrationHistoryRepository := ProvideRegistrationHistoryRepository(db)
+	documentVersionRepository := ProvideDocumentVersionRepository(db)
+	eligibilityRuleRepository := ProvideEligibilityRuleRepository(db)
+	academicPeriodRepository := ProvideAcademicPeriodRepository(db)
+	registrationTrashService := ProvideRegistrationTrashService(registrationRepository, registrationHistoryRepository, documentVersionRepository, eligibilityRuleRepository, academicPeriodRepository, storageConfig, purgeConfig, userManagementbaseURI, activityManagementbaseURI, asyncURIs, config2, tokenManager)
+	registrationTrashController := ProvideRegistrationTrashController(registrationTrashService)
+	return registrationTrashController, nil
+}
+
+func InitializeRegistrationPurgeJob(db *gorm.DB, storageConfig config.StorageConfig, purgeConfig config.PurgeConfig, userManagementbaseURI config.UserManagementbaseURI, activityManagementbaseURI config.ActivityManagementbaseURI, asyncURIs config.AsyncURIs, config2 *storage.Config, tokenManager *storage.CacheTokenManager) (*service.RegistrationPurgeJob, error) {
+	registrationRepository := ProvideRegistrationRepository(db)
+	registrationHistoryRepository := ProvideRegistrationHistoryRepository(db)
+	documentVersionRepository := ProvideDocumentVersionRepository(db)
+	eligibilityRuleRepository := ProvideEligibilityRuleRepository(db)
+	academicPeriodRepository := ProvideAcademicPeriodRepository(db)
+	registrationTrashService := ProvideRegistrationTrashService(registrationRepository, registrationHistoryRepository, documentVersionRepository, eligibilityRuleRepository, academicPeriodRepository, storageConfig, purgeConfig, userManagementbaseURI, activityManagementbaseURI, asyncURIs, config2, tokenManager)
+	registrationPurgeJob := ProvideRegistrationPurgeJob(registrationTrashService, purgeConfig)
+	return registrationPurgeJob, nil
+}
+
+func InitializeRetention(db *gorm.DB, storageConfig config.StorageConfig, retentionConfig config.RetentionConfig, config2 *storage.Config, tokenManager *storage.CacheTokenManager) (controller.RetentionController, error) {
+	retentionRepository := ProvideRetentionRepository(db)
+	registrationRepository := ProvideRegistrationRepository(db)
+	registrationHistoryRepository := ProvideRegistrationHistoryRepository(db)
+	documentVersionRepository := ProvideDocumentVersionRepository(db)
+	retentionService := ProvideRetentionService(retentionRepository, registrationRepository, registrationHistoryRepository, documentVersionRepository, storageConfig, retentionConfig, config2, tokenManager)
+	retentionController := ProvideRetentionController(retentionService)
+	return retentionController, nil
+}
+
+func InitializeRetentionJob(db *gorm.DB, storageConfig config.StorageConfig, retentionConfig config.RetentionConfig, config2 *storage.Config, tokenManager *storage.CacheTokenManager) (*service.RetentionJob, error) {
+	retentionRepository := ProvideRetentionRepository(db)
+	registrationRepository := ProvideRegistrationRepository(db)
+	registrationHistoryRepository := ProvideRegistrationHistoryRepository(db)
+	documentVersionRepository := ProvideDocumentVersionRepository(db)
+	retentionService := ProvideRetentionService(retentionRepository, registrationRepository, registrationHistoryRepository, documentVersionRepository, storageConfig, retentionConfig, config2, tokenManager)
+	retentionJob := ProvideRetentionJob(retentionService, retentionConfig)
+	return retentionJob, nil
+}
+
+func InitializeHealth(db *gorm.DB, migrator *migration.Migrator) (controller.HealthController, error) {
+	healthService := ProvideHealthService(db, migrator)
+	healthController := ProvideHealthController(healthService)
+	return healthController, nil
+}
+
 // wire.go:
 
 func ProvideRegistrationRepository(db *gorm.DB) repository.RegistrationRepository {
@@ -44,9 +129,46 @@ func ProvideDocumentRepository(db *gorm.DB) repository.DocumentRepository {
 	return repository.NewDocumentRepository(db)
 }
 
+func ProvideRegistrationHistoryRepository(db *gorm.DB) repository.RegistrationHistoryRepository {
+	return repository.NewRegistrationHistoryRepository(db)
+}
+
+func ProvideDocumentRequirementRepository(db *gorm.DB) repository.DocumentRequirementRepository {
+	return repository.NewDocumentRequirementRepository(db)
+}
+
+func ProvideDocumentVersionRepository(db *gorm.DB) repository.DocumentVersionRepository {
+	return repository.NewDocumentVersionRepository(db)
+}
+
+func ProvideEligibilityRuleRepository(db *gorm.DB) repository.EligibilityRuleRepository {
+	return repository.NewEligibilityRuleRepository(db)
+}
+
+func ProvideAcademicPeriodRepository(db *gorm.DB) repository.AcademicPeriodRepository {
+	return repository.NewAcademicPeriodRepository(db)
+}
+
+func ProvideAdvisorDelegationRepository(db *gorm.DB) repository.AdvisorDelegationRepository {
+	return repository.NewAdvisorDelegationRepository(db)
+}
+
 func ProvideRegistrationService(
 	registrationRepository repository.RegistrationRepository,
 	documentRepository repository.DocumentRepository,
+	registrationHistoryRepository repository.RegistrationHistoryRepository,
+	documentRequirementRepository repository.DocumentRequirementRepository,
+	eligibilityRuleRepository repository.EligibilityRuleRepository,
+	academicPeriodRepository repository.AcademicPeriodRepository,
+	advisorDelegationRepository repository.AdvisorDelegationRepository,
+	uploadConfig config.UploadConfig,
+	downloadConfig config.DownloadConfig,
+	storageConfig config.StorageConfig,
+	exportConfig config.ExportConfig,
+	importConfig config.ImportConfig,
+	academicRecordConfig config.AcademicRecordConfig,
+	advisorConfig config.AdvisorConfig,
+	registrationEditConfig config.RegistrationEditConfig,
 	secretKey config.SecretKey,
 	userManagementbaseURI config.UserManagementbaseURI,
 	activityManagementbaseURI config.ActivityManagementbaseURI,
@@ -56,7 +178,7 @@ func ProvideRegistrationService(
 	asyncURIs config.AsyncURIs, config2 *storage.Config,
 	tokenManager *storage.CacheTokenManager,
 ) service.RegistrationService {
-	return service.NewRegistrationService(registrationRepository, documentRepository, string(secretKey), string(userManagementbaseURI), string(activityManagementbaseURI), string(matchingManagementbaseURI), string(monitoringManagementbaseURI), string(brokerbaseURI), []string(asyncURIs), config2, tokenManager)
+	return service.NewRegistrationService(registrationRepository, documentRepository, registrationHistoryRepository, documentRequirementRepository, eligibilityRuleRepository, academicPeriodRepository, advisorDelegationRepository, uploadConfig, downloadConfig, storageConfig, exportConfig, importConfig, academicRecordConfig, advisorConfig, registrationEditConfig, string(secretKey), string(userManagementbaseURI), string(activityManagementbaseURI), string(matchingManagementbaseURI), string(monitoringManagementbaseURI), string(brokerbaseURI), []string(asyncURIs), config2, tokenManager)
 }
 
 func ProvideRegistrationController(registrationService service.RegistrationService) controller.RegistrationController {
@@ -66,25 +188,181 @@ func ProvideRegistrationController(registrationService service.RegistrationServi
 var RegistrationSet = wire.NewSet(
 	ProvideRegistrationRepository,
 	ProvideDocumentRepository,
+	ProvideRegistrationHistoryRepository,
+	ProvideDocumentRequirementRepository,
+	ProvideEligibilityRuleRepository,
+	ProvideAcademicPeriodRepository,
+	ProvideAdvisorDelegationRepository,
 	ProvideRegistrationService,
 	ProvideRegistrationController,
 )
 
 func ProvideDocumentService(
 	documentRepository repository.DocumentRepository,
-	registrationRepository repository.RegistrationRepository, config2 *storage.Config,
+	registrationRepository repository.RegistrationRepository,
+	documentRequirementRepository repository.DocumentRequirementRepository,
+	documentVersionRepository repository.DocumentVersionRepository,
+	advisorDelegationRepository repository.AdvisorDelegationRepository,
+	uploadConfig config.UploadConfig,
+	downloadConfig config.DownloadConfig,
+	storageConfig config.StorageConfig,
+	userManagementbaseURI config.UserManagementbaseURI,
+	brokerbaseURI config.BrokerbaseURI,
+	asyncURIs config.AsyncURIs, config2 *storage.Config,
 	tokenManager *storage.CacheTokenManager,
 ) service.DocumentService {
-	return service.NewDocumentService(documentRepository, registrationRepository, config2, tokenManager)
+	return service.NewDocumentService(documentRepository, registrationRepository, documentRequirementRepository, documentVersionRepository, advisorDelegationRepository, uploadConfig, downloadConfig, storageConfig, string(userManagementbaseURI), string(brokerbaseURI), []string(asyncURIs), config2, tokenManager)
 }
 
 func ProvideDocumentController(documentService service.DocumentService) controller.DocumentController {
 	return controller.NewDocumentController(documentService)
 }
 
+func ProvideDocumentRequirementService(documentRequirementRepository repository.DocumentRequirementRepository) service.DocumentRequirementService {
+	return service.NewDocumentRequirementService(documentRequirementRepository)
+}
+
+func ProvideDocumentRequirementController(documentRequirementService service.DocumentRequirementService) controller.DocumentRequirementController {
+	return controller.NewDocumentRequirementController(documentRequirementService)
+}
+
+func ProvideHealthService(db *gorm.DB, migrator *migration.Migrator) service.HealthService {
+	return service.NewHealthService(db, migrator)
+}
+
+func ProvideHealthController(healthService service.HealthService) controller.HealthController {
+	return controller.NewHealthController(healthService)
+}
+
+var HealthSet = wire.NewSet(
+	ProvideHealthService,
+	ProvideHealthController,
+)
+
+func ProvideEligibilityRuleService(eligibilityRuleRepository repository.EligibilityRuleRepository) service.EligibilityRuleService {
+	return service.NewEligibilityRuleService(eligibilityRuleRepository)
+}
+
+func ProvideEligibilityRuleController(eligibilityRuleService service.EligibilityRuleService) controller.EligibilityRuleController {
+	return controller.NewEligibilityRuleController(eligibilityRuleService)
+}
+
+var EligibilityRuleSet = wire.NewSet(
+	ProvideEligibilityRuleRepository,
+	ProvideEligibilityRuleService,
+	ProvideEligibilityRuleController,
+)
+
+func ProvideAcademicPeriodService(academicPeriodRepository repository.AcademicPeriodRepository) service.AcademicPeriodService {
+	return service.NewAcademicPeriodService(academicPeriodRepository)
+}
+
+func ProvideAcademicPeriodController(academicPeriodService service.AcademicPeriodService) controller.AcademicPeriodController {
+	return controller.NewAcademicPeriodController(academicPeriodService)
+}
+
+var AcademicPeriodSet = wire.NewSet(
+	ProvideAcademicPeriodRepository,
+	ProvideAcademicPeriodService,
+	ProvideAcademicPeriodController,
+)
+
+func ProvideAdvisorDelegationService(advisorDelegationRepository repository.AdvisorDelegationRepository, userManagementbaseURI config.UserManagementbaseURI, asyncURIs config.AsyncURIs) service.AdvisorDelegationService {
+	return service.NewAdvisorDelegationService(advisorDelegationRepository, string(userManagementbaseURI), []string(asyncURIs))
+}
+
+func ProvideAdvisorDelegationController(advisorDelegationService service.AdvisorDelegationService) controller.AdvisorDelegationController {
+	return controller.NewAdvisorDelegationController(advisorDelegationService)
+}
+
+var AdvisorDelegationSet = wire.NewSet(
+	ProvideAdvisorDelegationRepository,
+	ProvideAdvisorDelegationService,
+	ProvideAdvisorDelegationController,
+)
+
+func ProvideRegistrationTrashService(
+	registrationRepository repository.RegistrationRepository,
+	registrationHistoryRepository repository.RegistrationHistoryRepository,
+	documentVersionRepository repository.DocumentVersionRepository,
+	eligibilityRuleRepository repository.EligibilityRuleRepository,
+	academicPeriodRepository repository.AcademicPeriodRepository,
+	storageConfig config.StorageConfig,
+	purgeConfig config.PurgeConfig,
+	userManagementbaseURI config.UserManagementbaseURI,
+	activityManagementbaseURI config.ActivityManagementbaseURI,
+	asyncURIs config.AsyncURIs, config2 *storage.Config,
+	tokenManager *storage.CacheTokenManager,
+) service.RegistrationTrashService {
+	return service.NewRegistrationTrashService(registrationRepository, registrationHistoryRepository, documentVersionRepository, eligibilityRuleRepository, academicPeriodRepository, storageConfig, purgeConfig, string(userManagementbaseURI), string(activityManagementbaseURI), []string(asyncURIs), config2, tokenManager)
+}
+
+func ProvideRegistrationTrashController(registrationTrashService service.RegistrationTrashService) controller.RegistrationTrashController {
+	return controller.NewRegistrationTrashController(registrationTrashService)
+}
+
+func ProvideRegistrationPurgeJob(registrationTrashService service.RegistrationTrashService, purgeConfig config.PurgeConfig) *service.RegistrationPurgeJob {
+	return service.NewRegistrationPurgeJob(registrationTrashService, purgeConfig)
+}
+
+var RegistrationTrashSet = wire.NewSet(
+	ProvideRegistrationRepository,
+	ProvideRegistrationHistoryRepository,
+	ProvideDocumentVersionRepository,
+	ProvideEligibilityRuleRepository,
+	ProvideAcademicPeriodRepository,
+	ProvideRegistrationTrashService,
+	ProvideRegistrationTrashController,
+	ProvideRegistrationPurgeJob,
+)
+
+func ProvideRetentionRepository(db *gorm.DB) repository.RetentionRepository {
+	return repository.NewRetentionRepository(db)
+}
+
+func ProvideRetentionService(
+	retentionRepository repository.RetentionRepository,
+	registrationRepository repository.RegistrationRepository,
+	registrationHistoryRepository repository.RegistrationHistoryRepository,
+	documentVersionRepository repository.DocumentVersionRepository,
+	storageConfig config.StorageConfig,
+	retentionConfig config.RetentionConfig,
+	config2 *storage.Config,
+	tokenManager *storage.CacheTokenManager,
+) service.RetentionService {
+	return service.NewRetentionService(retentionRepository, registrationRepository, registrationHistoryRepository, documentVersionRepository, storageConfig, retentionConfig, config2, tokenManager)
+}
+
+func ProvideRetentionController(retentionService service.RetentionService) controller.RetentionController {
+	return controller.NewRetentionController(retentionService)
+}
+
+func ProvideRetentionJob(retentionService service.RetentionService, retentionConfig config.RetentionConfig) *service.RetentionJob {
+	return service.NewRetentionJob(retentionService, retentionConfig)
+}
+
+var RetentionSet = wire.NewSet(
+	ProvideRetentionRepository,
+	ProvideRegistrationRepository,
+	ProvideRegistrationHistoryRepository,
+	ProvideDocumentVersionRepository,
+	ProvideRetentionService,
+	ProvideRetentionController,
+	ProvideRetentionJob,
+)
+
+var DocumentRequirementSet = wire.NewSet(
+	ProvideDocumentRequirementRepository,
+	ProvideDocumentRequirementService,
+	ProvideDocumentRequirementController,
+)
+
 var DocumentSet = wire.NewSet(
 	ProvideDocumentRepository,
 	ProvideRegistrationRepository,
+	ProvideDocumentRequirementRepository,
+	ProvideDocumentVersionRepository,
+	ProvideAdvisorDelegationRepository,
 	ProvideDocumentService,
 	ProvideDocumentController,
 )